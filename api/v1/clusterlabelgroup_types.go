@@ -0,0 +1,61 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterLabelGroupSpec defines the desired state of ClusterLabelGroup
+type ClusterLabelGroupSpec struct {
+	// Do not use the most recent value stored in the database
+	DisableUsingMostRecentValue bool `json:"disableUsingMostRecentValue,omitempty"`
+
+	// Label selector for the namespaces whose pods are tracked. All namespaces are selected when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Label selector for the pods to be tracked for energy measurements in the selected namespaces
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:unservedversion
+
+// ClusterLabelGroup is the Schema for the ClusterLabelGroups API
+type ClusterLabelGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterLabelGroupSpec `json:"spec,omitempty"`
+	Status LabelGroupStatus      `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterLabelGroupList contains a list of ClusterLabelGroup
+type ClusterLabelGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterLabelGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterLabelGroup{}, &ClusterLabelGroupList{})
+}
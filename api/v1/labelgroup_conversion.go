@@ -0,0 +1,271 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// V2SpecAnnotation keeps the parts of a v2 spec that cannot be expressed in v1,
+// so that updates made through the v1 API do not drop them.
+const V2SpecAnnotation = "susql.ibm.com/v2-spec"
+
+// Same formats of the v1 totals that the v1 controller used to write
+const (
+	energyFormat = "%.2f"
+	carbonFormat = "%.10f"
+)
+
+// ConvertTo converts this LabelGroup to the hub version (v2).
+func (src *LabelGroup) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*susqlv2.LabelGroup)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := restoreV2Spec(&dst.ObjectMeta.Annotations, &dst.Spec); err != nil {
+		return err
+	}
+
+	dst.Spec.DisableUsingMostRecentValue = src.Spec.DisableUsingMostRecentValue
+	dst.Spec.Labels = src.Spec.Labels
+	dst.Spec.Selector = src.Spec.Selector
+
+	if err := restoreV2Status(src.Status.V2Status, &dst.Status); err != nil {
+		return err
+	}
+
+	return convertStatusTo(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v2) to this LabelGroup.
+func (dst *LabelGroup) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*susqlv2.LabelGroup)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	delete(dst.ObjectMeta.Annotations, V2SpecAnnotation)
+
+	dst.Spec.DisableUsingMostRecentValue = src.Spec.DisableUsingMostRecentValue
+	dst.Spec.Labels = src.Spec.Labels
+	dst.Spec.Selector = src.Spec.Selector
+
+	roundTrip := &susqlv2.LabelGroup{}
+	if err := dst.ConvertTo(roundTrip); err != nil {
+		return err
+	}
+	if err := preserveV2Spec(&dst.ObjectMeta.Annotations, src.Spec, roundTrip.Spec); err != nil {
+		return err
+	}
+
+	convertStatusFrom(&src.Status, &dst.Status)
+
+	return preserveV2Status(&src.Status, &dst.Status)
+}
+
+// ConvertTo converts this ClusterLabelGroup to the hub version (v2).
+func (src *ClusterLabelGroup) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*susqlv2.ClusterLabelGroup)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if err := restoreV2Spec(&dst.ObjectMeta.Annotations, &dst.Spec); err != nil {
+		return err
+	}
+
+	dst.Spec.DisableUsingMostRecentValue = src.Spec.DisableUsingMostRecentValue
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector
+	dst.Spec.Selector = src.Spec.Selector
+
+	if err := restoreV2Status(src.Status.V2Status, &dst.Status); err != nil {
+		return err
+	}
+
+	return convertStatusTo(&src.Status, &dst.Status)
+}
+
+// ConvertFrom converts from the hub version (v2) to this ClusterLabelGroup.
+func (dst *ClusterLabelGroup) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*susqlv2.ClusterLabelGroup)
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	delete(dst.ObjectMeta.Annotations, V2SpecAnnotation)
+
+	dst.Spec.DisableUsingMostRecentValue = src.Spec.DisableUsingMostRecentValue
+	dst.Spec.NamespaceSelector = src.Spec.NamespaceSelector
+	dst.Spec.Selector = src.Spec.Selector
+
+	roundTrip := &susqlv2.ClusterLabelGroup{}
+	if err := dst.ConvertTo(roundTrip); err != nil {
+		return err
+	}
+	if err := preserveV2Spec(&dst.ObjectMeta.Annotations, src.Spec, roundTrip.Spec); err != nil {
+		return err
+	}
+
+	convertStatusFrom(&src.Status, &dst.Status)
+
+	return preserveV2Status(&src.Status, &dst.Status)
+}
+
+// restoreV2Spec fills spec with the v2 fields kept in the annotations by preserveV2Spec and
+// removes the annotation from the converted object
+func restoreV2Spec(annotations *map[string]string, spec interface{}) error {
+	value, found := (*annotations)[V2SpecAnnotation]
+	if !found {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(value), spec); err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", V2SpecAnnotation, err)
+	}
+
+	delete(*annotations, V2SpecAnnotation)
+	if len(*annotations) == 0 {
+		*annotations = nil
+	}
+
+	return nil
+}
+
+// preserveV2Spec stores the v2 spec in the annotations when converting it to v1 and back
+// does not give the same spec, meaning that v1 cannot represent some of its fields
+func preserveV2Spec(annotations *map[string]string, spec interface{}, roundTrip interface{}) error {
+	if equality.Semantic.DeepEqual(spec, roundTrip) {
+		return nil
+	}
+
+	value, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+	(*annotations)[V2SpecAnnotation] = string(value)
+
+	return nil
+}
+
+// restoreV2Status fills status with the v2 status kept by preserveV2Status, whose fields that v1 represents are then
+// converted from v1
+func restoreV2Status(value *runtime.RawExtension, status *susqlv2.LabelGroupStatus) error {
+	if value == nil || len(value.Raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(value.Raw, status); err != nil {
+		return fmt.Errorf("invalid v2Status: %w", err)
+	}
+
+	return nil
+}
+
+// preserveV2Status keeps the v2 status in the v1 status when converting the v1 status back to v2 does not give the
+// same status, meaning that v1 cannot represent some of its fields
+func preserveV2Status(src *susqlv2.LabelGroupStatus, dst *LabelGroupStatus) error {
+	roundTrip := susqlv2.LabelGroupStatus{}
+	if err := convertStatusTo(dst, &roundTrip); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(*src, roundTrip) {
+		return nil
+	}
+
+	value, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	dst.V2Status = &runtime.RawExtension{Raw: value}
+
+	return nil
+}
+
+// convertStatusTo converts the fields of the v1 status into the v2 status, which may hold the restored v2 status.
+// Its totals are kept when the v1 totals are their formatted values, which are less precise.
+func convertStatusTo(src *LabelGroupStatus, dst *susqlv2.LabelGroupStatus) error {
+	var err error
+
+	dst.Phase = susqlv2.LabelGroupPhase(src.Phase)
+	dst.KubernetesLabels = src.KubernetesLabels
+	dst.PrometheusLabels = src.PrometheusLabels
+	dst.SusQLPrometheusEnergyQuery = src.SusQLPrometheusEnergyQuery
+	dst.SusQLPrometheusCarbonQuery = src.SusQLPrometheusCarbonQuery
+	dst.ActiveContainerIds = src.ActiveContainerIds
+	dst.Conditions = src.Conditions
+
+	if dst.TotalEnergyJoules, err = convertTotalTo(src.TotalEnergy, dst.TotalEnergyJoules, energyFormat, resource.Milli); err != nil {
+		return fmt.Errorf("invalid totalEnergy: %w", err)
+	}
+
+	if dst.TotalCarbonGrams, err = convertTotalTo(src.TotalCarbon, dst.TotalCarbonGrams, carbonFormat, resource.Nano); err != nil {
+		return fmt.Errorf("invalid totalCarbon: %w", err)
+	}
+
+	return nil
+}
+
+func convertStatusFrom(src *susqlv2.LabelGroupStatus, dst *LabelGroupStatus) {
+	dst.Phase = LabelGroupPhase(src.Phase)
+	dst.KubernetesLabels = src.KubernetesLabels
+	dst.PrometheusLabels = src.PrometheusLabels
+	dst.SusQLPrometheusEnergyQuery = src.SusQLPrometheusEnergyQuery
+	dst.SusQLPrometheusCarbonQuery = src.SusQLPrometheusCarbonQuery
+	dst.ActiveContainerIds = src.ActiveContainerIds
+	dst.Conditions = src.Conditions
+
+	dst.TotalEnergy = stringFromQuantity(src.TotalEnergyJoules, energyFormat)
+	dst.TotalCarbon = stringFromQuantity(src.TotalCarbonGrams, carbonFormat)
+}
+
+// convertTotalTo returns the quantity of a v1 total, or the restored v2 total when the v1 total is its formatted value
+func convertTotalTo(value string, restored *resource.Quantity, format string, scale resource.Scale) (*resource.Quantity, error) {
+	if restored != nil && stringFromQuantity(restored, format) == value {
+		return restored, nil
+	}
+
+	return quantityFromString(value, scale)
+}
+
+// quantityFromString parses the float strings of the v1 status into a quantity with the given scale
+func quantityFromString(value string, scale resource.Scale) (*resource.Quantity, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource.NewScaledQuantity(int64(math.Round(floatValue*math.Pow10(-int(scale)))), scale), nil
+}
+
+func stringFromQuantity(value *resource.Quantity, format string) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(format, value.AsApproximateFloat64())
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("LabelGroup Conversion", func() {
+	Context("When converting a v1 LabelGroup to v2", func() {
+		It("should parse the accumulated totals into quantities", func() {
+			src := &LabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "labelgroup", Namespace: "default"},
+				Spec:       LabelGroupSpec{Labels: []string{"app"}},
+				Status: LabelGroupStatus{
+					Phase:       Aggregating,
+					TotalEnergy: "1234.56",
+					TotalCarbon: "0.1430000000",
+				},
+			}
+
+			dst := &susqlv2.LabelGroup{}
+			Expect(src.ConvertTo(dst)).To(Succeed())
+
+			Expect(dst.Spec.Labels).To(Equal([]string{"app"}))
+			Expect(dst.Status.Phase).To(Equal(susqlv2.Aggregating))
+			Expect(dst.Status.TotalEnergyJoules.Cmp(resource.MustParse("1234.56"))).To(Equal(0))
+			Expect(dst.Status.TotalCarbonGrams.Cmp(resource.MustParse("0.143"))).To(Equal(0))
+		})
+
+		It("should leave the totals unset when they are empty", func() {
+			dst := &susqlv2.LabelGroup{}
+			Expect((&LabelGroup{}).ConvertTo(dst)).To(Succeed())
+
+			Expect(dst.Status.TotalEnergyJoules).To(BeNil())
+			Expect(dst.Status.TotalCarbonGrams).To(BeNil())
+		})
+
+		It("should fail on totals that are not numbers", func() {
+			src := &LabelGroup{Status: LabelGroupStatus{TotalEnergy: "a lot"}}
+
+			Expect(src.ConvertTo(&susqlv2.LabelGroup{})).NotTo(Succeed())
+		})
+	})
+
+	Context("When converting a v2 LabelGroup to v1", func() {
+		It("should format the quantities like the v1 controller did", func() {
+			src := &susqlv2.LabelGroup{
+				Status: susqlv2.LabelGroupStatus{
+					TotalEnergyJoules: resource.NewScaledQuantity(1234560, resource.Milli),
+					TotalCarbonGrams:  resource.NewScaledQuantity(143000000, resource.Nano),
+				},
+			}
+
+			dst := &LabelGroup{}
+			Expect(dst.ConvertFrom(src)).To(Succeed())
+
+			Expect(dst.Status.TotalEnergy).To(Equal("1234.56"))
+			Expect(dst.Status.TotalCarbon).To(Equal("0.1430000000"))
+			Expect(dst.Annotations).NotTo(HaveKey(V2SpecAnnotation))
+		})
+
+		It("should round trip without losing data", func() {
+			src := &susqlv2.LabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "labelgroup", Namespace: "default"},
+				Spec: susqlv2.LabelGroupSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+				Status: susqlv2.LabelGroupStatus{
+					Phase:              susqlv2.Aggregating,
+					TotalEnergyJoules:  resource.NewScaledQuantity(1500, resource.Milli),
+					ActiveContainerIds: map[string]float64{"container": 1.5},
+				},
+			}
+
+			v1 := &LabelGroup{}
+			Expect(v1.ConvertFrom(src)).To(Succeed())
+
+			dst := &susqlv2.LabelGroup{}
+			Expect(v1.ConvertTo(dst)).To(Succeed())
+
+			Expect(dst.Spec).To(Equal(src.Spec))
+			Expect(dst.Status.ActiveContainerIds).To(Equal(src.Status.ActiveContainerIds))
+			Expect(dst.Status.TotalEnergyJoules.Cmp(*src.Status.TotalEnergyJoules)).To(Equal(0))
+			Expect(dst.Annotations).To(BeNil())
+		})
+	})
+
+	Context("When converting a v2 LabelGroup with a status that v1 cannot represent", func() {
+		It("should keep it in the v1 status and restore it", func() {
+			now := metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+			replicas := int32(3)
+			quantity := func(value string) resource.Quantity { return resource.MustParse(value) }
+			pointer := func(value string) *resource.Quantity { q := resource.MustParse(value); return &q }
+
+			src := &susqlv2.LabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "labelgroup", Namespace: "default"},
+				Spec:       susqlv2.LabelGroupSpec{Labels: []string{"app"}},
+				Status: susqlv2.LabelGroupStatus{
+					Phase:                     susqlv2.Aggregating,
+					KubernetesLabels:          map[string]string{"susql.label/1": "app"},
+					PrometheusLabels:          map[string]string{"labelgroup_namespace": "default", "labelgroup_name": "labelgroup"},
+					TotalEnergyJoules:         pointer("1234567m"),
+					TotalDynamicEnergyJoules:  pointer("1000"),
+					TotalIdleEnergyJoules:     pointer("234567m"),
+					TotalFacilityEnergyJoules: pointer("1500"),
+					TotalCarbonGrams:          pointer("123456789n"),
+					TotalEmbodiedCarbonGrams:  pointer("5"),
+					SCI: &susqlv2.SCIStatus{GramsPerFunctionalUnit: quantity("1m"), CarbonGrams: quantity("1"),
+						FunctionalUnits: quantity("1k"), FunctionalUnit: "requests", Window: "1h", Time: now},
+					Budget: &susqlv2.BudgetStatus{PeriodStart: now, EnergyJoulesAtPeriodStart: quantity("100"), CarbonGramsAtPeriodStart: quantity("1"),
+						EnergyJoules: quantity("50"), CarbonGrams: quantity("2"), EnergyUtilization: "0.5"},
+					BudgetEnforcement: []susqlv2.BudgetEnforcementRecord{
+						{Action: susqlv2.BudgetEnforcementScaleDown, Kind: "Deployment", Namespace: "default", Name: "web", PreviousReplicas: &replicas, Time: now},
+					},
+					EnergyBreakdown:             []susqlv2.EnergyBreakdownEntry{{Namespace: "default", Pod: "web-1", EnergyJoules: quantity("10"), CarbonGrams: quantity("1m")}},
+					BreakdownContainers:         map[string]string{"container": "default/web-1"},
+					SusQLPrometheusEnergyQuery:  "susql_total_energy_joules",
+					SusQLPrometheusCarbonQuery:  "susql_total_carbon_dioxide_grams",
+					ActiveContainerIds:          map[string]float64{"container": 1.5},
+					ActiveContainerIdleCounters: map[string]float64{"container": 0.5},
+					CounterResets:               2,
+					LastAggregationTime:         &now,
+					CarbonByZone:                []susqlv2.ZoneCarbon{{Zone: "DE", EnergyJoules: quantity("10"), CarbonGrams: quantity("1m")}},
+					ContainerZones:              map[string]string{"container": "DE"},
+					CarbonLedger:                []susqlv2.CarbonLedgerEntry{{Zone: "DE", Start: now, EnergyJoules: quantity("10"), CarbonGrams: quantity("1m")}},
+					Conditions:                  []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Aggregating", LastTransitionTime: now}},
+				},
+			}
+
+			v1 := &LabelGroup{}
+			Expect(v1.ConvertFrom(src)).To(Succeed())
+			Expect(v1.Status.V2Status).NotTo(BeNil())
+			Expect(v1.Status.TotalEnergy).To(Equal("1234.57"))
+
+			dst := &susqlv2.LabelGroup{}
+			Expect(v1.ConvertTo(dst)).To(Succeed())
+			Expect(equality.Semantic.DeepEqual(dst.Status, src.Status)).To(BeTrue())
+
+			// A v1 client updates the phase and the energy
+			v1.Status.Phase = Initializing
+			v1.Status.TotalEnergy = "2000.00"
+
+			dst = &susqlv2.LabelGroup{}
+			Expect(v1.ConvertTo(dst)).To(Succeed())
+			Expect(dst.Status.Phase).To(Equal(susqlv2.Initializing))
+			Expect(dst.Status.TotalEnergyJoules.Cmp(resource.MustParse("2000"))).To(Equal(0))
+			Expect(dst.Status.TotalCarbonGrams.Cmp(*src.Status.TotalCarbonGrams)).To(Equal(0))
+			Expect(equality.Semantic.DeepEqual(dst.Status.BudgetEnforcement, src.Status.BudgetEnforcement)).To(BeTrue())
+			Expect(equality.Semantic.DeepEqual(dst.Status.CarbonLedger, src.Status.CarbonLedger)).To(BeTrue())
+		})
+
+		It("should not keep a status that v1 represents", func() {
+			src := &susqlv2.LabelGroup{Status: susqlv2.LabelGroupStatus{Phase: susqlv2.Aggregating, TotalEnergyJoules: resource.NewScaledQuantity(1500, resource.Milli)}}
+
+			v1 := &LabelGroup{}
+			Expect(v1.ConvertFrom(src)).To(Succeed())
+			Expect(v1.Status.V2Status).To(BeNil())
+		})
+	})
+
+	Context("When converting a v2 LabelGroup with fields that v1 cannot represent", func() {
+		It("should keep them in an annotation and restore them", func() {
+			src := &susqlv2.LabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "labelgroup", Namespace: "default"},
+				Spec: susqlv2.LabelGroupSpec{
+					Labels: []string{"app"},
+					EnergySource: &susqlv2.EnergySource{
+						Type:  susqlv2.EnergySourcePromQL,
+						Query: `my_energy_joules{pod=~{{.PodsRegex}}}`,
+					},
+				},
+			}
+
+			v1 := &LabelGroup{}
+			Expect(v1.ConvertFrom(src)).To(Succeed())
+			Expect(v1.Annotations).To(HaveKey(V2SpecAnnotation))
+
+			// A v1 client edits the labels
+			v1.Spec.Labels = []string{"app", "web"}
+
+			dst := &susqlv2.LabelGroup{}
+			Expect(v1.ConvertTo(dst)).To(Succeed())
+
+			Expect(dst.Spec.Labels).To(Equal([]string{"app", "web"}))
+			Expect(dst.Spec.EnergySource).To(Equal(src.Spec.EnergySource))
+			Expect(dst.Annotations).NotTo(HaveKey(V2SpecAnnotation))
+		})
+	})
+
+	Context("When converting a ClusterLabelGroup", func() {
+		It("should round trip the selectors", func() {
+			src := &susqlv2.ClusterLabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "clusterlabelgroup"},
+				Spec: susqlv2.ClusterLabelGroupSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					Selector:          metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				},
+			}
+
+			v1 := &ClusterLabelGroup{}
+			Expect(v1.ConvertFrom(src)).To(Succeed())
+
+			dst := &susqlv2.ClusterLabelGroup{}
+			Expect(v1.ConvertTo(dst)).To(Succeed())
+
+			Expect(dst.Spec).To(Equal(src.Spec))
+		})
+	})
+})
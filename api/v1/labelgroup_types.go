@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -31,8 +32,13 @@ type LabelGroupSpec struct {
 	// Do not use the most recent value stored in the database
 	DisableUsingMostRecentValue bool `json:"disableUsingMostRecentValue,omitempty"`
 
-	// List of labels to be tracked for energy measurements (up to 6)
+	// List of labels to be tracked for energy measurements
 	Labels []string `json:"labels,omitempty"`
+
+	// Label selector for the pods to be tracked for energy measurements.
+	// When set, it is used instead of the SusQL Kubernetes labels built from Labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 }
 
 // LabelGroupStatus defines the observed state of LabelGroup
@@ -63,6 +69,18 @@ type LabelGroupStatus struct {
 
 	// Active containers associated with these set of labels
 	ActiveContainerIds map[string]float64 `json:"activeContainerIds,omitempty"`
+
+	// Conditions describing why the LabelGroup is or is not aggregating
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Status of the v2 API that v1 cannot represent, e.g., the budget enforcement records, kept so that
+	// status updates made through the v1 API do not drop it
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	V2Status *runtime.RawExtension `json:"v2Status,omitempty"`
 }
 
 // LabelGroupPhase defines the label for the LabelGroupStatus
@@ -79,8 +97,27 @@ const (
 	Aggregating LabelGroupPhase = "Aggregating"
 )
 
+// Types of the conditions of the LabelGroupStatus
+const (
+	// Ready: The LabelGroup is aggregating energy and carbon for its pods
+	ConditionReady = "Ready"
+
+	// PrometheusReachable: The Prometheus servers for Kepler and SusQL can be queried
+	ConditionPrometheusReachable = "PrometheusReachable"
+
+	// PodsMatched: At least one pod matches the labels or selectors of the LabelGroup
+	ConditionPodsMatched = "PodsMatched"
+
+	// CarbonIntensityFresh: The carbon intensity applied to the energy is up to date
+	ConditionCarbonIntensityFresh = "CarbonIntensityFresh"
+
+	// SpecValid: The labels and selectors of the LabelGroup can be used
+	ConditionSpecValid = "SpecValid"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:unservedversion
 
 // LabelGroup is the Schema for the LabelGroups API
 type LabelGroup struct {
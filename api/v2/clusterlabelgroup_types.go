@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterLabelGroupSpec defines the desired state of ClusterLabelGroup
+type ClusterLabelGroupSpec struct {
+	// Do not use the most recent value stored in the database
+	DisableUsingMostRecentValue bool `json:"disableUsingMostRecentValue,omitempty"`
+
+	// Label selector for the namespaces whose pods are tracked. All namespaces are selected when it is not set.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Label selector for the pods to be tracked for energy measurements in the selected namespaces.
+	// It must not be empty, which would select all the pods of the selected namespaces.
+	// +kubebuilder:validation:XValidation:rule="(has(self.matchLabels) && size(self.matchLabels) > 0) || (has(self.matchExpressions) && size(self.matchExpressions) > 0)",message="the selector must not be empty"
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Source of the energy of the containers. The energy source of the operator is used when it is not set.
+	// PromQL energy sources can only be configured for the operator.
+	// +kubebuilder:validation:XValidation:rule="self.type != 'PromQL'",message="PromQL energy sources can only be configured for the operator"
+	// +optional
+	EnergySource *EnergySource `json:"energySource,omitempty"`
+
+	// Carbon configuration of the group, overriding the carbon configuration of the operator
+	// +optional
+	Carbon *CarbonSpec `json:"carbon,omitempty"`
+
+	// Software Carbon Intensity of the group per functional unit
+	// +optional
+	SCI *SCISpec `json:"sci,omitempty"`
+
+	// Energy and carbon budget of the group per period
+	// +optional
+	Budget *BudgetSpec `json:"budget,omitempty"`
+
+	// Breakdown of the energy and carbon of the group per pod or per container
+	// +optional
+	Breakdown *BreakdownSpec `json:"breakdown,omitempty"`
+
+	// Attribution of the idle energy of the nodes to the group. Kepler when it is not set.
+	// +optional
+	IdleEnergy IdleEnergyAttributionType `json:"idleEnergy,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Energy (J)",type=string,JSONPath=".status.totalEnergyJoules"
+// +kubebuilder:printcolumn:name="Carbon (g)",type=string,JSONPath=".status.totalCarbonGrams"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// ClusterLabelGroup is the Schema for the ClusterLabelGroups API
+type ClusterLabelGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterLabelGroupSpec `json:"spec,omitempty"`
+	Status LabelGroupStatus      `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterLabelGroupList contains a list of ClusterLabelGroup
+type ClusterLabelGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterLabelGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterLabelGroup{}, &ClusterLabelGroupList{})
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HardwareProfileSpec defines the embodied carbon of the hardware of a type of node
+type HardwareProfileSpec struct {
+	// Label selector for the nodes of this hardware type. All nodes are selected when it is not set.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Embodied emissions of the manufacturing, transport and disposal of the hardware of a node, in kilograms of
+	// carbon dioxide equivalent
+	EmbodiedCarbonKilograms resource.Quantity `json:"embodiedCarbonKilograms"`
+
+	// Expected lifetime of the hardware over which its embodied emissions are amortized, in years
+	LifetimeYears resource.Quantity `json:"lifetimeYears"`
+
+	// Weights of the resources in the share of a node reserved by a pod. The resources of the node are weighted
+	// equally when it is not set.
+	// +optional
+	ResourceWeights map[corev1.ResourceName]resource.Quantity `json:"resourceWeights,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Embodied (kg)",type=string,JSONPath=".spec.embodiedCarbonKilograms"
+// +kubebuilder:printcolumn:name="Lifetime (y)",type=string,JSONPath=".spec.lifetimeYears"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// HardwareProfile is the Schema for the HardwareProfiles API
+type HardwareProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HardwareProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// HardwareProfileList contains a list of HardwareProfile
+type HardwareProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HardwareProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HardwareProfile{}, &HardwareProfileList{})
+}
@@ -0,0 +1,575 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LabelGroupSpec defines the desired state of LabelGroup
+type LabelGroupSpec struct {
+	// Do not use the most recent value stored in the database
+	DisableUsingMostRecentValue bool `json:"disableUsingMostRecentValue,omitempty"`
+
+	// List of labels to be tracked for energy measurements
+	Labels []string `json:"labels,omitempty"`
+
+	// Label selector for the pods to be tracked for energy measurements.
+	// When set, it is used instead of the SusQL Kubernetes labels built from Labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Source of the energy of the containers. The energy source of the operator is used when it is not set.
+	// PromQL energy sources can only be configured for the operator.
+	// +kubebuilder:validation:XValidation:rule="self.type != 'PromQL'",message="PromQL energy sources can only be configured for the operator"
+	// +optional
+	EnergySource *EnergySource `json:"energySource,omitempty"`
+
+	// Carbon configuration of the group, overriding the carbon configuration of the operator
+	// +optional
+	Carbon *CarbonSpec `json:"carbon,omitempty"`
+
+	// Software Carbon Intensity of the group per functional unit
+	// +optional
+	SCI *SCISpec `json:"sci,omitempty"`
+
+	// Energy and carbon budget of the group per period
+	// +optional
+	Budget *BudgetSpec `json:"budget,omitempty"`
+
+	// Breakdown of the energy and carbon of the group per pod or per container
+	// +optional
+	Breakdown *BreakdownSpec `json:"breakdown,omitempty"`
+
+	// Attribution of the idle energy of the nodes to the group. Kepler when it is not set.
+	// +optional
+	IdleEnergy IdleEnergyAttributionType `json:"idleEnergy,omitempty"`
+}
+
+// EnergySourceType defines the kind of metrics the energy of the containers is read from
+// +kubebuilder:validation:Enum=Kepler;PromQL
+type EnergySourceType string
+
+const (
+	// Kepler: The kepler_container_joules_total counters of Kepler
+	EnergySourceKepler EnergySourceType = "Kepler"
+
+	// PromQL: Counters returned by user provided PromQL queries
+	EnergySourcePromQL EnergySourceType = "PromQL"
+)
+
+// EnergySource defines where the energy of the containers is read from
+type EnergySource struct {
+	// Type of the energy source
+	Type EnergySourceType `json:"type"`
+
+	// Name of the Kepler energy counter. The metric name of the operator is used when it is not set.
+	// Only used by the Kepler type.
+	// +optional
+	MetricName string `json:"metricName,omitempty"`
+
+	// Go template of the PromQL query returning the energy counter in joules of each container of a set of pods,
+	// identified by the container_id label. The template is given .Namespace, .Pods (list of pod names) and
+	// .PodsRegex (quoted PromQL string matching the pod names). Only used by the PromQL type.
+	// +optional
+	Query string `json:"query,omitempty"`
+
+	// Go template of the PromQL query returning the last energy counter in joules of terminated containers,
+	// identified by the container_id label. The template is given .ContainerIds, .ContainerIdsRegex (quoted
+	// PromQL string matching the container ids) and .Lookback (Prometheus duration). The energy of terminated
+	// containers since the last sample is not counted when it is not set. Only used by the PromQL type.
+	// +optional
+	LastValueQuery string `json:"lastValueQuery,omitempty"`
+}
+
+// CarbonMethodType defines how the carbon intensity of the energy of a label group is obtained
+// +kubebuilder:validation:Enum=static;dynamic
+type CarbonMethodType string
+
+const (
+	// static: A fixed carbon intensity
+	CarbonMethodStatic CarbonMethodType = "static"
+
+	// dynamic: The carbon intensities queried by the carbon intensity provider of the operator
+	CarbonMethodDynamic CarbonMethodType = "dynamic"
+)
+
+// CarbonSpec overrides the carbon configuration of the operator for a label group, e.g., for tenants with contracted
+// renewable energy or reporting against a different grid zone
+type CarbonSpec struct {
+	// Method used to obtain the carbon intensity. The method of the operator is used when it is not set.
+	// +optional
+	Method CarbonMethodType `json:"method,omitempty"`
+
+	// Carbon intensity in grams CO2 per Joule, as a decimal string. The carbon intensity of the operator is used
+	// when it is not set. Only used by the static method.
+	// +kubebuilder:validation:Pattern=`^([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	// +optional
+	Intensity string `json:"intensity,omitempty"`
+
+	// Carbon zone of the energy of the pods whose nodes are not mapped to a zone. The location of the operator is
+	// used when it is not set. Only used by the dynamic method.
+	// +optional
+	Location string `json:"location,omitempty"`
+
+	// Factor applied to the carbon intensity, as a decimal string, e.g., "0.4" when 60% of the energy is covered
+	// by contracted renewable energy. The intensity is not scaled when it is not set.
+	// +kubebuilder:validation:Pattern=`^([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	// +optional
+	IntensityFactor string `json:"intensityFactor,omitempty"`
+}
+
+// SCISpec defines the functional unit of the Software Carbon Intensity (SCI) of a label group, SCI = (E*I + M) / R:
+// the operational and embodied carbon of the group over a window divided by the functional units served
+type SCISpec struct {
+	// Name of the functional unit, e.g., "request", whose PromQL query returning the counter of the functional units
+	// of the group is configured for the operator with SCI-FUNCTIONAL-UNITS
+	FunctionalUnit string `json:"functionalUnit"`
+
+	// Prometheus duration of the window over which the SCI is computed. One hour when it is not set.
+	// +optional
+	Window string `json:"window,omitempty"`
+}
+
+// BudgetPeriodType defines the period over which the usage of a budget is accumulated
+// +kubebuilder:validation:Enum=daily;monthly;quarterly;rolling
+type BudgetPeriodType string
+
+const (
+	// daily: From midnight UTC
+	BudgetPeriodDaily BudgetPeriodType = "daily"
+
+	// monthly: From the first day of the month UTC
+	BudgetPeriodMonthly BudgetPeriodType = "monthly"
+
+	// quarterly: From the first day of the quarter UTC
+	BudgetPeriodQuarterly BudgetPeriodType = "quarterly"
+
+	// rolling: Over the window ending now
+	BudgetPeriodRolling BudgetPeriodType = "rolling"
+)
+
+// BudgetSpec defines the energy and carbon limits of a label group per period
+type BudgetSpec struct {
+	// Period over which the usage is accumulated
+	Period BudgetPeriodType `json:"period"`
+
+	// Prometheus duration of the window of the rolling period, e.g., "720h"
+	// +optional
+	Window string `json:"window,omitempty"`
+
+	// Energy limit of the period in joules
+	// +optional
+	EnergyJoules *resource.Quantity `json:"energyJoules,omitempty"`
+
+	// Carbon limit of the period in grams CO2
+	// +optional
+	CarbonGrams *resource.Quantity `json:"carbonGrams,omitempty"`
+
+	// Utilization ratio of a limit from which a warning is raised, as a decimal string. "0.8" when it is not set.
+	// +kubebuilder:validation:Pattern=`^([0-9]+\.?[0-9]*|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	// +optional
+	WarningThreshold string `json:"warningThreshold,omitempty"`
+
+	// Alerts on the utilization of the budget, generated as a PrometheusRule when it is set
+	// +optional
+	PrometheusRule *BudgetPrometheusRule `json:"prometheusRule,omitempty"`
+
+	// Actions taken on the workloads of the group while a limit of the budget is exceeded. They are only taken
+	// when budget enforcement is enabled in the operator.
+	// +optional
+	Enforcement *BudgetEnforcement `json:"enforcement,omitempty"`
+}
+
+// BudgetPrometheusRule defines the PrometheusRule generated for the alerts on a budget
+type BudgetPrometheusRule struct {
+	// Labels of the PrometheusRule, e.g., to be selected by the ruleSelector of a Prometheus
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Prometheus duration during which the utilization must stay over the threshold before an alert fires
+	// +optional
+	For string `json:"for,omitempty"`
+}
+
+// BudgetEnforcementActionType defines an action taken while the budget of a label group is exceeded
+// +kubebuilder:validation:Enum=SuspendJobs;ScaleDown;BlockNewPods
+type BudgetEnforcementActionType string
+
+const (
+	// SuspendJobs: Suspend the Jobs and CronJobs running the pods of the group
+	BudgetEnforcementSuspendJobs BudgetEnforcementActionType = "SuspendJobs"
+
+	// ScaleDown: Scale the Deployments and StatefulSets running the pods of the group down to the replicas floor
+	BudgetEnforcementScaleDown BudgetEnforcementActionType = "ScaleDown"
+
+	// BlockNewPods: Deny the creation of new pods of the group with the pod admission webhook
+	BudgetEnforcementBlockNewPods BudgetEnforcementActionType = "BlockNewPods"
+)
+
+// BudgetEnforcement defines the actions taken on the workloads of a label group while its budget is exceeded.
+// They are reversed when the usage falls back under the limits, e.g., when a new budget period starts.
+type BudgetEnforcement struct {
+	// Actions taken when a limit is exceeded
+	// +kubebuilder:validation:MinItems=1
+	Actions []BudgetEnforcementActionType `json:"actions"`
+
+	// Number of replicas the Deployments and StatefulSets are scaled down to. 0 when it is not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ReplicasFloor int32 `json:"replicasFloor,omitempty"`
+}
+
+// BudgetEnforcementRecord is an action taken on a workload, or on the label group itself, while its budget is exceeded
+type BudgetEnforcementRecord struct {
+	// Action taken
+	Action BudgetEnforcementActionType `json:"action"`
+
+	// Kind of the object the action was taken on: Job, CronJob, Deployment, StatefulSet, or the kind of the label group
+	Kind string `json:"kind"`
+
+	// Namespace of the object, empty for a ClusterLabelGroup
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object
+	Name string `json:"name"`
+
+	// Replicas of the workload before it was scaled down, restored when the action is reversed
+	// +optional
+	PreviousReplicas *int32 `json:"previousReplicas,omitempty"`
+
+	// Time at which the action was taken
+	Time metav1.Time `json:"time"`
+}
+
+// IdleEnergyAttributionType defines whether and how the idle energy of the nodes is attributed to a label group. It
+// only applies to the energy sources separating the idle energy of the containers, i.e., Kepler.
+// +kubebuilder:validation:Enum=Kepler;None
+type IdleEnergyAttributionType string
+
+const (
+	// Kepler: The idle energy Kepler attributes to each container
+	IdleEnergyKepler IdleEnergyAttributionType = "Kepler"
+
+	// None: No idle energy, only the dynamic energy of the containers is accounted
+	IdleEnergyNone IdleEnergyAttributionType = "None"
+)
+
+// BreakdownLevelType defines the granularity of the energy breakdown of a label group
+// +kubebuilder:validation:Enum=Pod;Container
+type BreakdownLevelType string
+
+const (
+	// Pod: One entry per pod
+	BreakdownLevelPod BreakdownLevelType = "Pod"
+
+	// Container: One entry per container name of each pod
+	BreakdownLevelContainer BreakdownLevelType = "Container"
+)
+
+// BreakdownSpec defines the breakdown of the energy and carbon of a label group, kept in the status and exported as
+// metrics with the pod_namespace, pod and container labels
+type BreakdownSpec struct {
+	// Granularity of the breakdown
+	Level BreakdownLevelType `json:"level"`
+
+	// Maximum number of entries. The energy and carbon of the deleted pods, and then of the smallest consumers, are
+	// folded into the "(other)" entry beyond it. 20 when it is not set.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxEntries int32 `json:"maxEntries,omitempty"`
+}
+
+// BreakdownOther is the pod name of the entry holding the energy and carbon beyond the maximum number of entries of
+// the breakdown
+const BreakdownOther = "(other)"
+
+// EnergyBreakdownEntry is the accumulated energy and carbon of a pod, or of a container of a pod, of a label group
+type EnergyBreakdownEntry struct {
+	// Namespace of the pod
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the pod, or "(other)"
+	Pod string `json:"pod"`
+
+	// Name of the container, empty for the Pod level
+	// +optional
+	Container string `json:"container,omitempty"`
+
+	// Accumulated energy measured for the containers, excluding the overhead of the nodes and of the data center
+	EnergyJoules resource.Quantity `json:"energyJoules"`
+
+	// Accumulated carbon of the energy, including the overhead of the data center (PUE)
+	CarbonGrams resource.Quantity `json:"carbonGrams"`
+}
+
+// BudgetStatus is the usage of the budget of a label group in the current period
+type BudgetStatus struct {
+	// Start of the period, or of the window of the rolling period
+	PeriodStart metav1.Time `json:"periodStart"`
+
+	// End of the period, not set for the rolling period
+	// +optional
+	PeriodEnd *metav1.Time `json:"periodEnd,omitempty"`
+
+	// Accumulated energy of the label group at the start of the period
+	EnergyJoulesAtPeriodStart resource.Quantity `json:"energyJoulesAtPeriodStart"`
+
+	// Accumulated carbon of the label group at the start of the period
+	CarbonGramsAtPeriodStart resource.Quantity `json:"carbonGramsAtPeriodStart"`
+
+	// Energy used in the period
+	EnergyJoules resource.Quantity `json:"energyJoules"`
+
+	// Carbon emitted in the period
+	CarbonGrams resource.Quantity `json:"carbonGrams"`
+
+	// Ratio of the energy used to the energy limit, as a decimal string
+	// +optional
+	EnergyUtilization string `json:"energyUtilization,omitempty"`
+
+	// Ratio of the carbon emitted to the carbon limit, as a decimal string
+	// +optional
+	CarbonUtilization string `json:"carbonUtilization,omitempty"`
+}
+
+// SCIStatus is the Software Carbon Intensity of a label group over the last window
+type SCIStatus struct {
+	// Carbon per functional unit in grams CO2, (E*I + M) / R
+	GramsPerFunctionalUnit resource.Quantity `json:"gramsPerFunctionalUnit"`
+
+	// Operational and embodied carbon of the window in grams CO2, E*I + M
+	CarbonGrams resource.Quantity `json:"carbonGrams"`
+
+	// Functional units of the window, R
+	FunctionalUnits resource.Quantity `json:"functionalUnits"`
+
+	// Name of the functional unit
+	// +optional
+	FunctionalUnit string `json:"functionalUnit,omitempty"`
+
+	// Window over which the SCI is computed
+	Window string `json:"window"`
+
+	// Time of the end of the window
+	Time metav1.Time `json:"time"`
+}
+
+// CarbonLedgerEntry is the energy consumed in a carbon zone during the period of a carbon intensity value and its carbon
+type CarbonLedgerEntry struct {
+	// Carbon zone of the energy
+	Zone string `json:"zone"`
+
+	// Start of the period of the carbon intensity value
+	Start metav1.Time `json:"start"`
+
+	// Energy consumed during the period, including the overhead of the data center (PUE)
+	EnergyJoules resource.Quantity `json:"energyJoules"`
+
+	// Carbon of the energy with the current carbon intensity value of the period
+	CarbonGrams resource.Quantity `json:"carbonGrams"`
+}
+
+// ZoneCarbon is the energy and carbon of the pods that ran on the nodes of a carbon zone
+type ZoneCarbon struct {
+	// Carbon intensity zone, as defined by the carbon intensity provider
+	Zone string `json:"zone"`
+
+	// Accumulated energy consumed in the zone, including the overhead of the data center (PUE)
+	EnergyJoules resource.Quantity `json:"energyJoules"`
+
+	// Accumulated carbon emitted in the zone
+	CarbonGrams resource.Quantity `json:"carbonGrams"`
+}
+
+// LabelGroupStatus defines the observed state of LabelGroup
+type LabelGroupStatus struct {
+	// Transition phase of the LabelGroup
+	Phase LabelGroupPhase `json:"phase,omitempty"`
+
+	// SusQL Kubernetes labels constructed from the spec
+	KubernetesLabels map[string]string `json:"kubernetesLabels,omitempty"`
+
+	// SusQL Prometheus labels constructed from the spec
+	PrometheusLabels map[string]string `json:"prometheusLabels,omitempty"`
+
+	// TotalEnergyJoules keeps track of the accumulated energy over time in joules
+	// +optional
+	TotalEnergyJoules *resource.Quantity `json:"totalEnergyJoules,omitempty"`
+
+	// TotalDynamicEnergyJoules keeps track of the part of TotalEnergyJoules driven by the activity of the containers
+	// +optional
+	TotalDynamicEnergyJoules *resource.Quantity `json:"totalDynamicEnergyJoules,omitempty"`
+
+	// TotalIdleEnergyJoules keeps track of the part of TotalEnergyJoules of the idle energy of the nodes attributed to
+	// the containers, see spec.idleEnergy
+	// +optional
+	TotalIdleEnergyJoules *resource.Quantity `json:"totalIdleEnergyJoules,omitempty"`
+
+	// TotalFacilityEnergyJoules keeps track of the accumulated energy over time in joules including the overhead of the
+	// data center (PUE), whose carbon is accounted
+	// +optional
+	TotalFacilityEnergyJoules *resource.Quantity `json:"totalFacilityEnergyJoules,omitempty"`
+
+	// TotalCarbonGrams keeps track of the accumulated grams of carbon dioxide emission over time
+	// +optional
+	TotalCarbonGrams *resource.Quantity `json:"totalCarbonGrams,omitempty"`
+
+	// TotalEmbodiedCarbonGrams keeps track of the accumulated embodied carbon dioxide equivalent over time in grams: the
+	// embodied emissions of the hardware of the nodes amortized over their lifetime, allocated by the resources the
+	// pods reserved
+	// +optional
+	TotalEmbodiedCarbonGrams *resource.Quantity `json:"totalEmbodiedCarbonGrams,omitempty"`
+
+	// Software Carbon Intensity of the last window
+	// +optional
+	SCI *SCIStatus `json:"sci,omitempty"`
+
+	// Usage of the budget in the current period
+	// +optional
+	Budget *BudgetStatus `json:"budget,omitempty"`
+
+	// Budget enforcement actions in effect, reversed when the budget is no longer exceeded
+	// +optional
+	BudgetEnforcement []BudgetEnforcementRecord `json:"budgetEnforcement,omitempty"`
+
+	// Energy and carbon per pod or per container, sorted by energy
+	// +listType=atomic
+	// +optional
+	EnergyBreakdown []EnergyBreakdownEntry `json:"energyBreakdown,omitempty"`
+
+	// Breakdown entries ('namespace/pod/container') of the active containers, attributing the energy of the
+	// containers of deleted pods
+	// +optional
+	BreakdownContainers map[string]string `json:"breakdownContainers,omitempty"`
+
+	// Prometheus query to get the total energy for this LabelGroup
+	SusQLPrometheusEnergyQuery string `json:"susqlPrometheusEnergyQuery,omitempty"`
+
+	// Prometheus query to get the total CO2 for this LabelGroup
+	SusQLPrometheusCarbonQuery string `json:"susqlPrometheusCarbonQuery,omitempty"`
+
+	// Active containers associated with these set of labels
+	ActiveContainerIds map[string]float64 `json:"activeContainerIds,omitempty"`
+
+	// Idle energy counters of the active containers, included in their counters in ActiveContainerIds
+	// +optional
+	ActiveContainerIdleCounters map[string]float64 `json:"activeContainerIdleCounters,omitempty"`
+
+	// Number of times the Kepler energy counter of a container decreased and was treated as a reset
+	// +optional
+	CounterResets int64 `json:"counterResets,omitempty"`
+
+	// Time of the last energy aggregation, the end of the interval matched to the carbon intensity series
+	// +optional
+	LastAggregationTime *metav1.Time `json:"lastAggregationTime,omitempty"`
+
+	// Energy and carbon per carbon zone
+	// +listType=map
+	// +listMapKey=zone
+	// +optional
+	CarbonByZone []ZoneCarbon `json:"carbonByZone,omitempty"`
+
+	// Carbon zones of the active containers that do not run in the default zone
+	// +optional
+	ContainerZones map[string]string `json:"containerZones,omitempty"`
+
+	// Energy and carbon per carbon zone and intensity period, kept while the carbon intensity provider may still
+	// publish a finalized intensity of the period, which corrects the carbon retroactively
+	// +listType=atomic
+	// +optional
+	CarbonLedger []CarbonLedgerEntry `json:"carbonLedger,omitempty"`
+
+	// Conditions describing why the LabelGroup is or is not aggregating
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// LabelGroupPhase defines the label for the LabelGroupStatus
+type LabelGroupPhase string
+
+const (
+	// Initializing: The LabelGroup is picked up for the first time and setup
+	Initializing LabelGroupPhase = "Initializing"
+
+	// Reloading: Use most recent value in the database if requested
+	Reloading LabelGroupPhase = "Reloading"
+
+	// Aggregating: The LabelGroup is aggregating the energy for the registered labels
+	Aggregating LabelGroupPhase = "Aggregating"
+)
+
+// Types of the conditions of the LabelGroupStatus
+const (
+	// Ready: The LabelGroup is aggregating energy and carbon for its pods
+	ConditionReady = "Ready"
+
+	// PrometheusReachable: The Prometheus servers for Kepler and SusQL can be queried
+	ConditionPrometheusReachable = "PrometheusReachable"
+
+	// PodsMatched: At least one pod matches the labels or selectors of the LabelGroup
+	ConditionPodsMatched = "PodsMatched"
+
+	// CarbonIntensityFresh: The carbon intensity applied to the energy is up to date
+	ConditionCarbonIntensityFresh = "CarbonIntensityFresh"
+
+	// SpecValid: The labels and selectors of the LabelGroup can be used
+	ConditionSpecValid = "SpecValid"
+
+	// BudgetHealthy: The usage of the budget of the LabelGroup is below its warning threshold
+	ConditionBudgetHealthy = "BudgetHealthy"
+)
+
+// BudgetBlockedLabel is set on a LabelGroup or ClusterLabelGroup whose new pods are denied by the pod admission
+// webhook while its budget is exceeded
+const BudgetBlockedLabel = "susql.ibm.com/budget-blocked"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Energy (J)",type=string,JSONPath=".status.totalEnergyJoules"
+// +kubebuilder:printcolumn:name="Carbon (g)",type=string,JSONPath=".status.totalCarbonGrams"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+
+// LabelGroup is the Schema for the LabelGroups API
+type LabelGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LabelGroupSpec   `json:"spec,omitempty"`
+	Status LabelGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LabelGroupList contains a list of LabelGroup
+type LabelGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LabelGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LabelGroup{}, &LabelGroupList{})
+}
@@ -0,0 +1,718 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2024, 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BreakdownSpec) DeepCopyInto(out *BreakdownSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BreakdownSpec.
+func (in *BreakdownSpec) DeepCopy() *BreakdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BreakdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetEnforcement) DeepCopyInto(out *BudgetEnforcement) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]BudgetEnforcementActionType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetEnforcement.
+func (in *BudgetEnforcement) DeepCopy() *BudgetEnforcement {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetEnforcement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetEnforcementRecord) DeepCopyInto(out *BudgetEnforcementRecord) {
+	*out = *in
+	if in.PreviousReplicas != nil {
+		in, out := &in.PreviousReplicas, &out.PreviousReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetEnforcementRecord.
+func (in *BudgetEnforcementRecord) DeepCopy() *BudgetEnforcementRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetEnforcementRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetPrometheusRule) DeepCopyInto(out *BudgetPrometheusRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetPrometheusRule.
+func (in *BudgetPrometheusRule) DeepCopy() *BudgetPrometheusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetPrometheusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetSpec) DeepCopyInto(out *BudgetSpec) {
+	*out = *in
+	if in.EnergyJoules != nil {
+		in, out := &in.EnergyJoules, &out.EnergyJoules
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CarbonGrams != nil {
+		in, out := &in.CarbonGrams, &out.CarbonGrams
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(BudgetPrometheusRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Enforcement != nil {
+		in, out := &in.Enforcement, &out.Enforcement
+		*out = new(BudgetEnforcement)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetSpec.
+func (in *BudgetSpec) DeepCopy() *BudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BudgetStatus) DeepCopyInto(out *BudgetStatus) {
+	*out = *in
+	in.PeriodStart.DeepCopyInto(&out.PeriodStart)
+	if in.PeriodEnd != nil {
+		in, out := &in.PeriodEnd, &out.PeriodEnd
+		*out = (*in).DeepCopy()
+	}
+	out.EnergyJoulesAtPeriodStart = in.EnergyJoulesAtPeriodStart.DeepCopy()
+	out.CarbonGramsAtPeriodStart = in.CarbonGramsAtPeriodStart.DeepCopy()
+	out.EnergyJoules = in.EnergyJoules.DeepCopy()
+	out.CarbonGrams = in.CarbonGrams.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BudgetStatus.
+func (in *BudgetStatus) DeepCopy() *BudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarbonLedgerEntry) DeepCopyInto(out *CarbonLedgerEntry) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	out.EnergyJoules = in.EnergyJoules.DeepCopy()
+	out.CarbonGrams = in.CarbonGrams.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarbonLedgerEntry.
+func (in *CarbonLedgerEntry) DeepCopy() *CarbonLedgerEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(CarbonLedgerEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CarbonSpec) DeepCopyInto(out *CarbonSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CarbonSpec.
+func (in *CarbonSpec) DeepCopy() *CarbonSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CarbonSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLabelGroup) DeepCopyInto(out *ClusterLabelGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLabelGroup.
+func (in *ClusterLabelGroup) DeepCopy() *ClusterLabelGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLabelGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterLabelGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLabelGroupList) DeepCopyInto(out *ClusterLabelGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterLabelGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLabelGroupList.
+func (in *ClusterLabelGroupList) DeepCopy() *ClusterLabelGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLabelGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterLabelGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLabelGroupSpec) DeepCopyInto(out *ClusterLabelGroupSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.EnergySource != nil {
+		in, out := &in.EnergySource, &out.EnergySource
+		*out = new(EnergySource)
+		**out = **in
+	}
+	if in.Carbon != nil {
+		in, out := &in.Carbon, &out.Carbon
+		*out = new(CarbonSpec)
+		**out = **in
+	}
+	if in.SCI != nil {
+		in, out := &in.SCI, &out.SCI
+		*out = new(SCISpec)
+		**out = **in
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(BudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Breakdown != nil {
+		in, out := &in.Breakdown, &out.Breakdown
+		*out = new(BreakdownSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLabelGroupSpec.
+func (in *ClusterLabelGroupSpec) DeepCopy() *ClusterLabelGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLabelGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergyBreakdownEntry) DeepCopyInto(out *EnergyBreakdownEntry) {
+	*out = *in
+	out.EnergyJoules = in.EnergyJoules.DeepCopy()
+	out.CarbonGrams = in.CarbonGrams.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergyBreakdownEntry.
+func (in *EnergyBreakdownEntry) DeepCopy() *EnergyBreakdownEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergyBreakdownEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnergySource) DeepCopyInto(out *EnergySource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnergySource.
+func (in *EnergySource) DeepCopy() *EnergySource {
+	if in == nil {
+		return nil
+	}
+	out := new(EnergySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfile) DeepCopyInto(out *HardwareProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfile.
+func (in *HardwareProfile) DeepCopy() *HardwareProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileList) DeepCopyInto(out *HardwareProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileList.
+func (in *HardwareProfileList) DeepCopy() *HardwareProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareProfileSpec) DeepCopyInto(out *HardwareProfileSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.EmbodiedCarbonKilograms = in.EmbodiedCarbonKilograms.DeepCopy()
+	out.LifetimeYears = in.LifetimeYears.DeepCopy()
+	if in.ResourceWeights != nil {
+		in, out := &in.ResourceWeights, &out.ResourceWeights
+		*out = make(map[corev1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareProfileSpec.
+func (in *HardwareProfileSpec) DeepCopy() *HardwareProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelGroup) DeepCopyInto(out *LabelGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelGroup.
+func (in *LabelGroup) DeepCopy() *LabelGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LabelGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelGroupList) DeepCopyInto(out *LabelGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LabelGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelGroupList.
+func (in *LabelGroupList) DeepCopy() *LabelGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LabelGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelGroupSpec) DeepCopyInto(out *LabelGroupSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnergySource != nil {
+		in, out := &in.EnergySource, &out.EnergySource
+		*out = new(EnergySource)
+		**out = **in
+	}
+	if in.Carbon != nil {
+		in, out := &in.Carbon, &out.Carbon
+		*out = new(CarbonSpec)
+		**out = **in
+	}
+	if in.SCI != nil {
+		in, out := &in.SCI, &out.SCI
+		*out = new(SCISpec)
+		**out = **in
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(BudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Breakdown != nil {
+		in, out := &in.Breakdown, &out.Breakdown
+		*out = new(BreakdownSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelGroupSpec.
+func (in *LabelGroupSpec) DeepCopy() *LabelGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelGroupStatus) DeepCopyInto(out *LabelGroupStatus) {
+	*out = *in
+	if in.KubernetesLabels != nil {
+		in, out := &in.KubernetesLabels, &out.KubernetesLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PrometheusLabels != nil {
+		in, out := &in.PrometheusLabels, &out.PrometheusLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TotalEnergyJoules != nil {
+		in, out := &in.TotalEnergyJoules, &out.TotalEnergyJoules
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalDynamicEnergyJoules != nil {
+		in, out := &in.TotalDynamicEnergyJoules, &out.TotalDynamicEnergyJoules
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalIdleEnergyJoules != nil {
+		in, out := &in.TotalIdleEnergyJoules, &out.TotalIdleEnergyJoules
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalFacilityEnergyJoules != nil {
+		in, out := &in.TotalFacilityEnergyJoules, &out.TotalFacilityEnergyJoules
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalCarbonGrams != nil {
+		in, out := &in.TotalCarbonGrams, &out.TotalCarbonGrams
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TotalEmbodiedCarbonGrams != nil {
+		in, out := &in.TotalEmbodiedCarbonGrams, &out.TotalEmbodiedCarbonGrams
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SCI != nil {
+		in, out := &in.SCI, &out.SCI
+		*out = new(SCIStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Budget != nil {
+		in, out := &in.Budget, &out.Budget
+		*out = new(BudgetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BudgetEnforcement != nil {
+		in, out := &in.BudgetEnforcement, &out.BudgetEnforcement
+		*out = make([]BudgetEnforcementRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnergyBreakdown != nil {
+		in, out := &in.EnergyBreakdown, &out.EnergyBreakdown
+		*out = make([]EnergyBreakdownEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BreakdownContainers != nil {
+		in, out := &in.BreakdownContainers, &out.BreakdownContainers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveContainerIds != nil {
+		in, out := &in.ActiveContainerIds, &out.ActiveContainerIds
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ActiveContainerIdleCounters != nil {
+		in, out := &in.ActiveContainerIdleCounters, &out.ActiveContainerIdleCounters
+		*out = make(map[string]float64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastAggregationTime != nil {
+		in, out := &in.LastAggregationTime, &out.LastAggregationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CarbonByZone != nil {
+		in, out := &in.CarbonByZone, &out.CarbonByZone
+		*out = make([]ZoneCarbon, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContainerZones != nil {
+		in, out := &in.ContainerZones, &out.ContainerZones
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CarbonLedger != nil {
+		in, out := &in.CarbonLedger, &out.CarbonLedger
+		*out = make([]CarbonLedgerEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelGroupStatus.
+func (in *LabelGroupStatus) DeepCopy() *LabelGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCISpec) DeepCopyInto(out *SCISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCISpec.
+func (in *SCISpec) DeepCopy() *SCISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SCISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SCIStatus) DeepCopyInto(out *SCIStatus) {
+	*out = *in
+	out.GramsPerFunctionalUnit = in.GramsPerFunctionalUnit.DeepCopy()
+	out.CarbonGrams = in.CarbonGrams.DeepCopy()
+	out.FunctionalUnits = in.FunctionalUnits.DeepCopy()
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SCIStatus.
+func (in *SCIStatus) DeepCopy() *SCIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SCIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneCarbon) DeepCopyInto(out *ZoneCarbon) {
+	*out = *in
+	out.EnergyJoules = in.EnergyJoules.DeepCopy()
+	out.CarbonGrams = in.CarbonGrams.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneCarbon.
+func (in *ZoneCarbon) DeepCopy() *ZoneCarbon {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneCarbon)
+	in.DeepCopyInto(out)
+	return out
+}
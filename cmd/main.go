@@ -29,6 +29,7 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"github.com/prometheus/common/model"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -43,7 +44,10 @@ import (
 
 	"github.com/operator-framework/operator-lib/leader"
 	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
 	"github.com/sustainable-computing-io/susql-operator/internal/controller"
+	webhookv1 "github.com/sustainable-computing-io/susql-operator/internal/webhook/v1"
+	webhookv2 "github.com/sustainable-computing-io/susql-operator/internal/webhook/v2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -57,6 +61,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(susqlv1.AddToScheme(scheme))
+	utilruntime.Must(susqlv2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -70,26 +75,52 @@ func getEnv(key, defval string) string {
 func main() {
 	var noopValue bool = true
 	var enableLeaderElection bool = true
+	var enableWebhooks bool = false
+	var enableBudgetEnforcement bool = false
 	var probeAddr string = ":8081"
 	var keplerPrometheusUrl string = "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091"
+	var keplerPrometheusClientConfig string = "" // Prometheus http_config YAML (TLS, authentication, headers, proxy)
 	var keplerMetricName string = "kepler_container_joules_total"
+	var keplerLookbackWindow string = "5m"
 	var susqlPrometheusMetricsUrl string = "http://0.0.0.0:8082"
 	var susqlPrometheusDatabaseUrl string = "https://thanos-querier.openshift-monitoring.svc.cluster.local:9091"
+	var susqlPrometheusClientConfig string = "" // Prometheus http_config YAML (TLS, authentication, headers, proxy)
 	var samplingRate string = "2"
 	var susqlLogLevel string = "-5"
 	// Carbon Intensity Factor in grams CO2 / Joule
-	var carbonMethod string = "static" // options: static, simpledynamic, casdk
+	var carbonMethod string = controller.CarbonMethodStatic // options: static, simpledynamic, casdk, electricitymaps, watttime, ukgrid
 	var carbonIntensity string = "0.0001158333333333"
 	var carbonIntensityUrl string = "https://api.electricitymap.org/v3/carbon-intensity/latest?zone=%s"
+	var carbonForecastUrl string = "" // URL of the carbon intensity forecasts of the casdk method, none when empty
 	var carbonLocation string = "JP-TK"
 	var carbonQueryRate string = "7200"
 	var carbonQueryFilter string = "carbonIntensity"
 	var carbonQueryConv2J string = "0.0000002777777778"
+	var carbonApiUrl string = ""                                      // Base URL of the electricitymaps, watttime and ukgrid methods, their public API when empty
+	var carbonCorrectionWindow string = "24h"                         // Time during which carbon is corrected with finalized intensities
+	var carbonApiSecret string = ""                                   // Secret holding the carbon intensity API credentials
+	var carbonZoneLabel string = ""                                   // Node label of the carbon zone, e.g., "topology.kubernetes.io/region"
+	var carbonZoneMap string = ""                                     // e.g., "us-east-1=US-MIDA-PJM,edge-node-1=JP-TK"
+	var pue string = "1.0"                                            // Power usage effectiveness of the data center
+	var pueSchedule string = ""                                       // e.g., "00:00=1.2,09:00=1.5,18:00=1.3"
+	var pueQuery string = ""                                          // PromQL query returning the power usage effectiveness
+	var nodeOverheadWatts string = "0"                                // Fixed overhead power of each node
+	var carbonAwareThreshold string = ""                              // Carbon intensity releasing the deferred pods, none when empty
+	var carbonAwareMaxDelay string = "12h"                            // Time after which the deferred pods are released anyway
+	var metricLabelMode string = controller.MetricLabelModeLabelGroup // options: labelgroup, legacy
+	var metricLabelMap string = ""                                    // e.g., "app.kubernetes.io/part-of=part_of,team=team"
+	var energySource string = string(susqlv2.EnergySourceKepler)      // options: Kepler, PromQL
+	var energyQuery string = ""                                       // PromQL energy source query template
+	var energyLastValueQuery string = ""                              // PromQL energy source query template for terminated containers
+	var sciFunctionalUnits string = ""                                // e.g., "request=sum(http_requests_total{namespace=\"{{.Namespace}}\"})", one per line
 
 	// NOTE: these can be set as env or flag, flag takes precedence over env
 	keplerPrometheusUrlEnv := getEnv("KEPLER-PROMETHEUS-URL", keplerPrometheusUrl)
+	keplerPrometheusClientConfigEnv := getEnv("KEPLER-PROMETHEUS-CLIENT-CONFIG", keplerPrometheusClientConfig)
 	keplerMetricNameEnv := getEnv("KEPLER-METRIC-NAME", keplerMetricName)
+	keplerLookbackWindowEnv := getEnv("KEPLER-LOOKBACK-WINDOW", keplerLookbackWindow)
 	susqlPrometheusDatabaseUrlEnv := getEnv("SUSQL-PROMETHEUS-DATABASE-URL", susqlPrometheusDatabaseUrl)
+	susqlPrometheusClientConfigEnv := getEnv("SUSQL-PROMETHEUS-CLIENT-CONFIG", susqlPrometheusClientConfig)
 	susqlPrometheusMetricsUrlEnv := getEnv("SUSQL-PROMETHEUS-METRICS-URL", susqlPrometheusMetricsUrl)
 	samplingRateEnv := getEnv("SAMPLING-RATE", samplingRate)
 	probeAddrEnv := getEnv("HEALTH-PROBE-BIND-ADDRESS", probeAddr)
@@ -97,19 +128,48 @@ func main() {
 	carbonMethodEnv := getEnv("CARBON-METHOD", carbonMethod)
 	carbonIntensityEnv := getEnv("CARBON-INTENSITY", carbonIntensity)
 	carbonIntensityUrlEnv := getEnv("CARBON-INTENSITY-URL", carbonIntensityUrl)
+	carbonForecastUrlEnv := getEnv("CARBON-FORECAST-URL", carbonForecastUrl)
 	carbonLocationEnv := getEnv("CARBON-LOCATION", carbonLocation)
 	carbonQueryRateEnv := getEnv("CARBON-QUERY-RATE", carbonQueryRate)
 	carbonQueryFilterEnv := getEnv("CARBON-QUERY-FILTER", carbonQueryFilter)
 	carbonQueryConv2JEnv := getEnv("CARBON-QUERY-CONV-2J", carbonQueryConv2J)
+	carbonApiUrlEnv := getEnv("CARBON-API-URL", carbonApiUrl)
+	carbonApiSecretEnv := getEnv("CARBON-API-SECRET", carbonApiSecret)
+	carbonCorrectionWindowEnv := getEnv("CARBON-CORRECTION-WINDOW", carbonCorrectionWindow)
+	carbonZoneLabelEnv := getEnv("CARBON-ZONE-LABEL", carbonZoneLabel)
+	carbonZoneMapEnv := getEnv("CARBON-ZONE-MAP", carbonZoneMap)
+	pueEnv := getEnv("PUE", pue)
+	pueScheduleEnv := getEnv("PUE-SCHEDULE", pueSchedule)
+	pueQueryEnv := getEnv("PUE-QUERY", pueQuery)
+	nodeOverheadWattsEnv := getEnv("NODE-OVERHEAD-WATTS", nodeOverheadWatts)
+	carbonAwareThresholdEnv := getEnv("CARBON-AWARE-THRESHOLD", carbonAwareThreshold)
+	carbonAwareMaxDelayEnv := getEnv("CARBON-AWARE-MAX-DELAY", carbonAwareMaxDelay)
+	metricLabelModeEnv := getEnv("SUSQL-METRIC-LABEL-MODE", metricLabelMode)
+	metricLabelMapEnv := getEnv("SUSQL-METRIC-LABEL-MAP", metricLabelMap)
+	energySourceEnv := getEnv("ENERGY-SOURCE", energySource)
+	energyQueryEnv := getEnv("ENERGY-QUERY", energyQuery)
+	energyLastValueQueryEnv := getEnv("ENERGY-LAST-VALUE-QUERY", energyLastValueQuery)
+	sciFunctionalUnitsEnv := getEnv("SCI-FUNCTIONAL-UNITS", sciFunctionalUnits)
 	enableLeaderElectionEnv, err := strconv.ParseBool(getEnv("LEADER-ELECT", strconv.FormatBool(enableLeaderElection)))
 	if err != nil {
 		enableLeaderElectionEnv = false
 	}
+	enableWebhooksEnv, err := strconv.ParseBool(getEnv("ENABLE-WEBHOOKS", strconv.FormatBool(enableWebhooks)))
+	if err != nil {
+		enableWebhooksEnv = false
+	}
+	enableBudgetEnforcementEnv, err := strconv.ParseBool(getEnv("ENABLE-BUDGET-ENFORCEMENT", strconv.FormatBool(enableBudgetEnforcement)))
+	if err != nil {
+		enableBudgetEnforcementEnv = false
+	}
 
 	flag.BoolVar(&noopValue, "noop", true, "No Operation. Does nothing.")
 	flag.StringVar(&keplerPrometheusUrl, "kepler-prometheus-url", keplerPrometheusUrlEnv, "The URL for the Prometheus server where Kepler stores the energy data")
+	flag.StringVar(&keplerPrometheusClientConfig, "kepler-prometheus-client-config", keplerPrometheusClientConfigEnv, "The HTTP client configuration (Prometheus http_config YAML) of the Prometheus server where Kepler stores the energy data")
 	flag.StringVar(&keplerMetricName, "kepler-metric-name", keplerMetricNameEnv, "The metric name to be queried in the kepler Prometheus server")
+	flag.StringVar(&keplerLookbackWindow, "kepler-lookback-window", keplerLookbackWindowEnv, "How far back to look for the last energy value of terminated containers (Prometheus duration)")
 	flag.StringVar(&susqlPrometheusDatabaseUrl, "susql-prometheus-database-url", susqlPrometheusDatabaseUrlEnv, "The URL for the Prometheus database where SusQL stores the energy data")
+	flag.StringVar(&susqlPrometheusClientConfig, "susql-prometheus-client-config", susqlPrometheusClientConfigEnv, "The HTTP client configuration (Prometheus http_config YAML) of the Prometheus database where SusQL stores the energy data")
 	flag.StringVar(&susqlPrometheusMetricsUrl, "susql-prometheus-metrics-url", susqlPrometheusMetricsUrlEnv, "The URL for the Prometheus metrics where SusQL exposes the energy data")
 	flag.StringVar(&samplingRate, "sampling-rate", samplingRateEnv, "Sampling rate in seconds")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", probeAddrEnv, "The address the probe endpoint binds to.")
@@ -117,13 +177,36 @@ func main() {
 	flag.StringVar(&carbonMethod, "carbon-method", carbonMethodEnv, "Method used to calculate CO2 emissions")
 	flag.StringVar(&carbonIntensity, "carbon-intensity", carbonIntensityEnv, "Carbon Intensity Factor in grams CO2 / Joule")
 	flag.StringVar(&carbonIntensityUrl, "carbon-intensity-url", carbonIntensityUrlEnv, "URL used to query calculate carbon intensity")
+	flag.StringVar(&carbonForecastUrl, "carbon-forecast-url", carbonForecastUrlEnv, "URL template of the Carbon Aware SDK current forecasts (e.g., http://carbon-aware-sdk:8080/emissions/forecasts/current?location=%s), none when empty")
 	flag.StringVar(&carbonLocation, "carbon-location", carbonLocationEnv, "Location identifier used in carbon intensity query")
 	flag.StringVar(&carbonQueryRate, "carbon-query-rate", carbonQueryRateEnv, "How often to query carbon intensity query (seconds)")
 	flag.StringVar(&carbonQueryFilter, "carbon-query-filter", carbonQueryFilterEnv, "Parameter to extract carbon intensity from JSON returned by query")
 	flag.StringVar(&carbonQueryConv2J, "carbon-query-conv-2j", carbonQueryConv2JEnv, "Factor to convert carbon intensity returned by query to grams CO2 / Joule")
+	flag.StringVar(&carbonApiUrl, "carbon-api-url", carbonApiUrlEnv, "Base URL of the carbon intensity API of the electricitymaps, watttime and ukgrid methods")
+	flag.StringVar(&carbonApiSecret, "carbon-api-secret", carbonApiSecretEnv, "Secret holding the carbon intensity API credentials ('token', or 'username' and 'password')")
+	flag.StringVar(&carbonCorrectionWindow, "carbon-correction-window", carbonCorrectionWindowEnv, "Time during which the carbon of past energy is corrected with the finalized carbon intensities of the provider (e.g., 24h, 0 to disable)")
+	flag.StringVar(&carbonZoneLabel, "carbon-zone-label", carbonZoneLabelEnv, "Node label whose value is the carbon zone of the node (e.g., topology.kubernetes.io/region), carbon-location when empty")
+	flag.StringVar(&carbonZoneMap, "carbon-zone-map", carbonZoneMapEnv, "Comma separated 'key=zone' pairs mapping node names or carbon-zone-label values to carbon zones")
+	flag.StringVar(&pue, "pue", pueEnv, "Power usage effectiveness of the data center, scaling the energy of the containers into facility energy")
+	flag.StringVar(&pueSchedule, "pue-schedule", pueScheduleEnv, "Comma separated 'HH:MM=pue' pairs of the power usage effectiveness from a time of the day in UTC, replacing pue")
+	flag.StringVar(&pueQuery, "pue-query", pueQueryEnv, "PromQL query to the Kepler Prometheus server returning the power usage effectiveness, replacing pue-schedule and pue")
+	flag.StringVar(&nodeOverheadWatts, "node-overhead-watts", nodeOverheadWattsEnv, "Fixed overhead power of each node in watts, shared between the running pods of the node")
+	flag.StringVar(&carbonAwareThreshold, "carbon-aware-threshold", carbonAwareThresholdEnv, "Carbon intensity in grams CO2 / Joule under which the pods deferred by the carbon-aware scheduling gate are released")
+	flag.StringVar(&carbonAwareMaxDelay, "carbon-aware-max-delay", carbonAwareMaxDelayEnv, "Time after their creation at which the pods deferred by the carbon-aware scheduling gate are released anyway (e.g., 12h)")
+	flag.StringVar(&metricLabelMode, "susql-metric-label-mode", metricLabelModeEnv, "Labels identifying the exported SusQL metrics: 'labelgroup' (LabelGroup namespace/name) or 'legacy' (susql_label_N)")
+	flag.StringVar(&metricLabelMap, "susql-metric-label-map", metricLabelMapEnv, "Comma separated 'kubernetes-label=prometheus_label' pairs of LabelGroup labels exported with the SusQL metrics")
+	flag.StringVar(&energySource, "energy-source", energySourceEnv, "Source of the energy of the containers: 'Kepler' or 'PromQL'")
+	flag.StringVar(&energyQuery, "energy-query", energyQueryEnv, "Go template of the PromQL query returning the energy counter of each container (PromQL energy source)")
+	flag.StringVar(&energyLastValueQuery, "energy-last-value-query", energyLastValueQueryEnv, "Go template of the PromQL query returning the last energy counter of terminated containers (PromQL energy source)")
+	flag.StringVar(&sciFunctionalUnits, "sci-functional-units", sciFunctionalUnitsEnv, "Newline separated 'name=query' pairs of the Go templates of the PromQL queries of the SCI functional units, given .Namespace")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", enableLeaderElectionEnv,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", enableWebhooksEnv,
+		"Serve the conversion webhook between the v1 and v2 SusQL APIs and the pod admission webhooks of the carbon-aware scheduling gate and the budget enforcement. "+
+			"Requires the webhook serving certificates to be mounted in the manager.")
+	flag.BoolVar(&enableBudgetEnforcement, "enable-budget-enforcement", enableBudgetEnforcementEnv,
+		"Take the enforcement actions of the LabelGroup budgets on their workloads (suspend Jobs, scale down Deployments and StatefulSets, block new pods)")
 
 	susqlLogLevelInt, err := strconv.Atoi(susqlLogLevel)
 	if err != nil {
@@ -141,20 +224,43 @@ func main() {
 
 	susqlLog.Info("SusQL configuration values at runtime")
 	susqlLog.Info("enableLeaderElection=" + strconv.FormatBool(enableLeaderElection))
+	susqlLog.Info("enableWebhooks=" + strconv.FormatBool(enableWebhooks))
+	susqlLog.Info("enableBudgetEnforcement=" + strconv.FormatBool(enableBudgetEnforcement))
 	susqlLog.Info("probeAddr=" + probeAddr)
 	susqlLog.Info("keplerPrometheusUrl=" + keplerPrometheusUrl)
+	susqlLog.Info("keplerPrometheusClientConfig set=" + strconv.FormatBool(keplerPrometheusClientConfig != "")) // may hold credentials
 	susqlLog.Info("keplerMetricName=" + keplerMetricName)
+	susqlLog.Info("keplerLookbackWindow=" + keplerLookbackWindow)
 	susqlLog.Info("susqlPrometheusMetricsUrl=" + susqlPrometheusMetricsUrl)
 	susqlLog.Info("susqlPrometheusDatabaseUrl=" + susqlPrometheusDatabaseUrl)
+	susqlLog.Info("susqlPrometheusClientConfig set=" + strconv.FormatBool(susqlPrometheusClientConfig != "")) // may hold credentials
 	susqlLog.Info("samplingRate=" + samplingRate)
 	susqlLog.Info("susqlLogLevel=" + susqlLogLevel)
 	susqlLog.Info("carbonMethod=" + carbonMethod)
 	susqlLog.Info("carbonIntensity=" + carbonIntensity)
 	susqlLog.Info("carbonIntensityUrl=" + carbonIntensityUrl)
+	susqlLog.Info("carbonForecastUrl=" + carbonForecastUrl)
 	susqlLog.Info("carbonLocation=" + carbonLocation)
 	susqlLog.Info("carbonQueryRate=" + carbonQueryRate)
 	susqlLog.Info("carbonQueryFilter=" + carbonQueryFilter)
 	susqlLog.Info("carbonQueryConv2J=" + carbonQueryConv2J)
+	susqlLog.Info("carbonApiUrl=" + carbonApiUrl)
+	susqlLog.Info("carbonApiSecret=" + carbonApiSecret)
+	susqlLog.Info("carbonCorrectionWindow=" + carbonCorrectionWindow)
+	susqlLog.Info("carbonZoneLabel=" + carbonZoneLabel)
+	susqlLog.Info("carbonZoneMap=" + carbonZoneMap)
+	susqlLog.Info("pue=" + pue)
+	susqlLog.Info("pueSchedule=" + pueSchedule)
+	susqlLog.Info("pueQuery=" + pueQuery)
+	susqlLog.Info("nodeOverheadWatts=" + nodeOverheadWatts)
+	susqlLog.Info("carbonAwareThreshold=" + carbonAwareThreshold)
+	susqlLog.Info("carbonAwareMaxDelay=" + carbonAwareMaxDelay)
+	susqlLog.Info("metricLabelMode=" + metricLabelMode)
+	susqlLog.Info("metricLabelMap=" + metricLabelMap)
+	susqlLog.Info("energySource=" + energySource)
+	susqlLog.Info("energyQuery=" + energyQuery)
+	susqlLog.Info("energyLastValueQuery=" + energyLastValueQuery)
+	susqlLog.Info("sciFunctionalUnits=" + sciFunctionalUnits)
 
 	// If enableLeaderElection is false, then set "Leader for Life" mode
 	if enableLeaderElection != true {
@@ -213,15 +319,58 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Validate carbonMethod
-	validCarbonMethods := map[string]bool{
-		"static":        true,
-		"simpledynamic": true,
-		"casdk":         true,
+	// Validate metricLabelMode
+	validMetricLabelModes := map[string]bool{
+		controller.MetricLabelModeLabelGroup: true,
+		controller.MetricLabelModeLegacy:     true,
+	}
+	if !validMetricLabelModes[metricLabelMode] {
+		susqlLog.Info(fmt.Sprintf("WARNING: Invalid susql-metric-label-mode '%s'. Valid options are: labelgroup, legacy. Defaulting to 'labelgroup'.", metricLabelMode))
+		metricLabelMode = controller.MetricLabelModeLabelGroup
+	}
+
+	metricLabelMapParsed, err := controller.ParseMetricLabelMap(metricLabelMap)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse susql-metric-label-map")
+		os.Exit(1)
+	}
+	if metricLabelMode == controller.MetricLabelModeLegacy && len(metricLabelMapParsed) > 0 {
+		susqlLog.Info("WARNING: susql-metric-label-map is ignored with the 'legacy' susql-metric-label-mode.")
+	}
+
+	energySourceSpec := susqlv2.EnergySource{
+		Type:           susqlv2.EnergySourceType(energySource),
+		Query:          energyQuery,
+		LastValueQuery: energyLastValueQuery,
+	}
+	if err := controller.ValidateEnergySource(&energySourceSpec); err != nil {
+		susqlLog.Error(err, "Invalid energy source")
+		os.Exit(1)
+	}
+
+	// Clients of the Prometheus endpoints, whose Secret references are read from the namespace of the operator
+	secretManager := controller.NewSecretManager(mgr.GetAPIReader(), "")
+
+	keplerPrometheusClientConfigParsed, err := controller.ParsePrometheusClientConfig(keplerPrometheusUrl, keplerPrometheusClientConfig)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse kepler-prometheus-client-config")
+		os.Exit(1)
+	}
+	keplerPrometheusRoundTripper, err := controller.NewPrometheusRoundTripper(keplerPrometheusClientConfigParsed, "kepler-prometheus", secretManager)
+	if err != nil {
+		susqlLog.Error(err, "Unable to create the Kepler Prometheus client")
+		os.Exit(1)
+	}
+
+	susqlPrometheusClientConfigParsed, err := controller.ParsePrometheusClientConfig(susqlPrometheusDatabaseUrl, susqlPrometheusClientConfig)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse susql-prometheus-client-config")
+		os.Exit(1)
 	}
-	if !validCarbonMethods[carbonMethod] {
-		susqlLog.Info(fmt.Sprintf("WARNING: Invalid carbon-method '%s'. Valid options are: static, simpledynamic, casdk. Defaulting to 'static'.", carbonMethod))
-		carbonMethod = "static"
+	susqlPrometheusRoundTripper, err := controller.NewPrometheusRoundTripper(susqlPrometheusClientConfigParsed, "susql-prometheus", secretManager)
+	if err != nil {
+		susqlLog.Error(err, "Unable to create the SusQL Prometheus client")
+		os.Exit(1)
 	}
 
 	samplingRateInteger, err := strconv.Atoi(samplingRate)
@@ -229,11 +378,68 @@ func main() {
 		samplingRateInteger = 2
 	}
 
+	if _, err := model.ParseDuration(keplerLookbackWindow); err != nil {
+		susqlLog.Error(err, "Invalid Kepler lookback window. Using 5m.")
+		keplerLookbackWindow = "5m"
+	}
+
 	carbonQueryRateInteger, err := strconv.ParseInt(carbonQueryRate, 10, 64)
-	if err != nil {
+	if err != nil || carbonQueryRateInteger <= 0 {
+		susqlLog.Error(err, "Invalid carbon query rate, expected a positive number of seconds. Using 7200.")
 		carbonQueryRateInteger = 7200
 	}
 
+	carbonCorrectionWindowDuration, err := time.ParseDuration(carbonCorrectionWindow)
+	if err != nil || carbonCorrectionWindowDuration < 0 {
+		susqlLog.Error(err, "Invalid carbon correction window. Using 24h.")
+		carbonCorrectionWindowDuration = 24 * time.Hour
+	}
+
+	carbonZoneMapParsed, err := controller.ParseCarbonZoneMap(carbonZoneMap)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse carbon-zone-map")
+		os.Exit(1)
+	}
+
+	pueFloat, err := controller.ParsePUE(pue)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse pue")
+		os.Exit(1)
+	}
+
+	pueScheduleParsed, err := controller.ParsePUESchedule(pueSchedule)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse pue-schedule")
+		os.Exit(1)
+	}
+
+	sciFunctionalUnitsParsed, err := controller.ParseSCIFunctionalUnits(sciFunctionalUnits)
+	if err != nil {
+		susqlLog.Error(err, "Unable to parse sci-functional-units")
+		os.Exit(1)
+	}
+
+	nodeOverheadWattsFloat, err := strconv.ParseFloat(nodeOverheadWatts, 64)
+	if err != nil || nodeOverheadWattsFloat < 0 {
+		susqlLog.Error(err, "Invalid node overhead watts. Using 0.")
+		nodeOverheadWattsFloat = 0
+	}
+
+	carbonAwareThresholdFloat := 0.0
+	if carbonAwareThreshold != "" {
+		carbonAwareThresholdFloat, err = strconv.ParseFloat(carbonAwareThreshold, 64)
+		if err != nil || carbonAwareThresholdFloat < 0 {
+			susqlLog.Error(err, "Invalid carbon-aware threshold. Using none.")
+			carbonAwareThresholdFloat = 0
+		}
+	}
+
+	carbonAwareMaxDelayDuration, err := time.ParseDuration(carbonAwareMaxDelay)
+	if err != nil || carbonAwareMaxDelayDuration < 0 {
+		susqlLog.Error(err, "Invalid carbon-aware max delay. Using 12h.")
+		carbonAwareMaxDelayDuration = 12 * time.Hour
+	}
+
 	carbonIntensityFloat, err := strconv.ParseFloat(carbonIntensity, 64)
 	if err != nil {
 		susqlLog.Error(err, "Unable to obtain initial carbon intensity value. Using 0.0.")
@@ -246,32 +452,127 @@ func main() {
 		carbonQueryConv2JFloat = 0.0
 	}
 
+	carbonIntensityProvider, err := controller.NewCarbonIntensityProvider(carbonMethod, controller.CarbonIntensityProviderConfig{
+		IntensityUrl: carbonIntensityUrl,
+		ForecastUrl:  carbonForecastUrl,
+		Filter:       carbonQueryFilter,
+		Conv2J:       carbonQueryConv2JFloat,
+		ApiUrl:       carbonApiUrl,
+		SecretName:   carbonApiSecret,
+		Secrets:      secretManager,
+	})
+	if err != nil {
+		susqlLog.Error(err, "Invalid carbon-method configuration. Defaulting to 'static'.")
+		carbonMethod = controller.CarbonMethodStatic
+	}
+
 	susqlLog.Info("Setting up labelGroupReconciler.")
 
-	if err = (&controller.LabelGroupReconciler{
-		Client:                        mgr.GetClient(),
-		Scheme:                        mgr.GetScheme(),
-		KeplerPrometheusUrl:           keplerPrometheusUrl,
-		KeplerMetricName:              keplerMetricName,
-		SusQLPrometheusDatabaseUrl:    susqlPrometheusDatabaseUrl,
-		SusQLPrometheusMetricsUrl:     susqlPrometheusMetricsUrl,
-		SamplingRate:                  time.Duration(samplingRateInteger) * time.Second,
-		CarbonMethod:                  carbonMethod,
-		CarbonIntensity:               carbonIntensityFloat,
-		CarbonIntensityUrl:            carbonIntensityUrl,
-		CarbonIntensityTimeStamp:      0,
-		CarbonIntensityErrorTimeStamp: 0,
-		CarbonLocation:                carbonLocation,
-		CarbonQueryRate:               carbonQueryRateInteger,
-		CarbonQueryFilter:             carbonQueryFilter,
-		CarbonQueryConv2J:             carbonQueryConv2JFloat,
-		Logger:                        susqlLog,
-	}).SetupWithManager(mgr); err != nil {
+	// The label groups are reconciled once the storage version migrator has migrated the ones stored as v1
+	storageMigrated := make(chan struct{})
+
+	labelGroupReconciler := &controller.LabelGroupReconciler{
+		Client:                       mgr.GetClient(),
+		Scheme:                       mgr.GetScheme(),
+		KeplerPrometheusUrl:          keplerPrometheusUrl,
+		KeplerPrometheusRoundTripper: keplerPrometheusRoundTripper,
+		KeplerMetricName:             keplerMetricName,
+		KeplerLookbackWindow:         keplerLookbackWindow,
+		EnergySource:                 energySourceSpec,
+		SusQLPrometheusDatabaseUrl:   susqlPrometheusDatabaseUrl,
+		SusQLPrometheusRoundTripper:  susqlPrometheusRoundTripper,
+		SusQLPrometheusMetricsUrl:    susqlPrometheusMetricsUrl,
+		SamplingRate:                 time.Duration(samplingRateInteger) * time.Second,
+		CarbonMethod:                 carbonMethod,
+		CarbonIntensity:              carbonIntensityFloat,
+		CarbonIntensityProvider:      carbonIntensityProvider,
+		CarbonLocation:               carbonLocation,
+		CarbonZoneLabel:              carbonZoneLabel,
+		CarbonZoneMap:                carbonZoneMapParsed,
+		CarbonQueryRate:              carbonQueryRateInteger,
+		CarbonCorrectionWindow:       carbonCorrectionWindowDuration,
+		PUE:                          pueFloat,
+		PUESchedule:                  pueScheduleParsed,
+		PUEQuery:                     pueQuery,
+		NodeOverheadWatts:            nodeOverheadWattsFloat,
+		SCIFunctionalUnits:           sciFunctionalUnitsParsed,
+		OperatorNamespace:            controller.OperatorNamespace(),
+		EnableBudgetEnforcement:      enableBudgetEnforcement,
+		EnableWebhooks:               enableWebhooks,
+		APIReader:                    mgr.GetAPIReader(),
+		StorageMigrated:              storageMigrated,
+		Recorder:                     mgr.GetEventRecorderFor("susql-controller"),
+		MetricLabelMode:              metricLabelMode,
+		MetricLabelMap:               metricLabelMapParsed,
+		Logger:                       susqlLog,
+	}
+	if err = labelGroupReconciler.SetupWithManager(mgr); err != nil {
 		susqlLog.Error(err, "unable to create controller", "controller", "LabelGroup")
 		os.Exit(1)
 	}
+
+	susqlLog.Info("Setting up clusterLabelGroupReconciler.")
+
+	if err = (&controller.ClusterLabelGroupReconciler{
+		LabelGroupReconciler: labelGroupReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		susqlLog.Error(err, "unable to create controller", "controller", "ClusterLabelGroup")
+		os.Exit(1)
+	}
+
+	susqlLog.Info("Setting up carbonAwareGateReconciler.")
+
+	if err = (&controller.CarbonAwareGateReconciler{
+		LabelGroupReconciler: labelGroupReconciler,
+		Threshold:            carbonAwareThresholdFloat,
+		MaxDelay:             carbonAwareMaxDelayDuration,
+	}).SetupWithManager(mgr); err != nil {
+		susqlLog.Error(err, "unable to create controller", "controller", "CarbonAwareGate")
+		os.Exit(1)
+	}
+
+	if enableWebhooks {
+		susqlLog.Info("Setting up conversion webhooks.")
+
+		if err = webhookv2.SetupLabelGroupWebhookWithManager(mgr); err != nil {
+			susqlLog.Error(err, "unable to create webhook", "webhook", "LabelGroup")
+			os.Exit(1)
+		}
+		if err = webhookv2.SetupClusterLabelGroupWebhookWithManager(mgr); err != nil {
+			susqlLog.Error(err, "unable to create webhook", "webhook", "ClusterLabelGroup")
+			os.Exit(1)
+		}
+
+		susqlLog.Info("Setting up pod admission webhook.")
+
+		if err = webhookv1.SetupPodWebhookWithManager(mgr); err != nil {
+			susqlLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	susqlLog.Info("Setting up storage version migrator.")
+
+	if err = mgr.Add(&controller.StorageVersionMigrator{
+		Client: mgr.GetClient(),
+		Reader: mgr.GetAPIReader(),
+		Done:   storageMigrated,
+		Logger: susqlLog,
+	}); err != nil {
+		susqlLog.Error(err, "unable to set up storage version migrator")
+		os.Exit(1)
+	}
+
+	if carbonIntensityProvider != nil {
+		susqlLog.Info("Setting up carbon intensity refresher.")
+
+		if err = mgr.Add(controller.NewCarbonIntensityRefresher(labelGroupReconciler)); err != nil {
+			susqlLog.Error(err, "unable to set up carbon intensity refresher")
+			os.Exit(1)
+		}
+	}
+
 	susqlLog.Info("Adding healthz check.")
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -282,6 +583,12 @@ func main() {
 		susqlLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err := mgr.AddReadyzCheck("webhook", mgr.GetWebhookServer().StartedChecker()); err != nil {
+			susqlLog.Error(err, "unable to set up webhook ready check")
+			os.Exit(1)
+		}
+	}
 
 	susqlLog.Info("Starting manager.")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -0,0 +1,121 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+)
+
+// accumulateContainerEnergy returns the energy consumed by each container since the previous sample, given the last
+// counter value of the active containers and the current counter values queried from Kepler.
+//
+// The counters are handled the way Prometheus increase() does: a counter that is lower than its previous
+// value has been reset, e.g., because Kepler restarted, and its whole current value is the energy consumed
+// since the reset. The ids of these containers are returned so that the resets can be reported.
+//
+// activeContainers is updated with the current values, and containers that are not reported anymore are removed.
+func accumulateContainerEnergy(activeContainers map[string]float64, metricValues map[string]float64) (map[string]float64, []string) {
+	increases := make(map[string]float64, len(metricValues))
+	var resets []string
+
+	for containerId := range activeContainers {
+		if _, found := metricValues[containerId]; !found {
+			// Delete inactive container since it doesn't appear in queried containers
+			delete(activeContainers, containerId)
+		}
+	}
+
+	for containerId, newValue := range metricValues {
+		if oldValue, found := activeContainers[containerId]; found {
+			increase, reset := counterIncrease(oldValue, newValue)
+			increases[containerId] = increase
+			if reset {
+				resets = append(resets, containerId)
+			}
+		} else {
+			// New container
+			increases[containerId] = newValue
+		}
+
+		activeContainers[containerId] = newValue
+	}
+
+	sort.Strings(resets)
+
+	return increases, resets
+}
+
+// vanishedContainers returns the ids of the active containers that are not reported anymore
+func vanishedContainers(activeContainers map[string]float64, metricValues map[string]float64) []string {
+	var containerIds []string
+
+	for containerId := range activeContainers {
+		if _, found := metricValues[containerId]; !found {
+			containerIds = append(containerIds, containerId)
+		}
+	}
+
+	sort.Strings(containerIds)
+
+	return containerIds
+}
+
+// accumulateContainerTailEnergy returns the energy consumed by each terminated container between the previous sample
+// and its termination, given the last counter values found for them. These containers are removed from
+// activeContainers, and the ids of the ones whose counter was reset are returned.
+func accumulateContainerTailEnergy(activeContainers map[string]float64, lastValues map[string]float64) (map[string]float64, []string) {
+	increases := make(map[string]float64, len(lastValues))
+	var resets []string
+
+	for containerId, lastValue := range lastValues {
+		oldValue, found := activeContainers[containerId]
+		if !found {
+			continue
+		}
+
+		increase, reset := counterIncrease(oldValue, lastValue)
+		increases[containerId] = increase
+		if reset {
+			resets = append(resets, containerId)
+		}
+
+		delete(activeContainers, containerId)
+	}
+
+	sort.Strings(resets)
+
+	return increases, resets
+}
+
+// counterIncrease returns the increase of a counter from oldValue to newValue, and whether it was reset
+func counterIncrease(oldValue float64, newValue float64) (float64, bool) {
+	if newValue < oldValue {
+		return newValue, true
+	}
+
+	return newValue - oldValue, false
+}
+
+// sumValues returns the sum of the values of a map
+func sumValues(values map[string]float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Feed a series of Kepler counter samples to accumulateContainerEnergy, returning the accumulated energy and resets
+func accumulateSeries(series []map[string]float64) (float64, []string) {
+	activeContainers := map[string]float64{}
+
+	var totalEnergy float64
+	var totalResets []string
+
+	for _, sample := range series {
+		increases, resets := accumulateContainerEnergy(activeContainers, sample)
+		totalEnergy += sumValues(increases)
+		totalResets = append(totalResets, resets...)
+	}
+
+	return totalEnergy, totalResets
+}
+
+var _ = Describe("Energy Aggregation", func() {
+	Context("When the Kepler counters only increase", func() {
+		It("should accumulate the increase of each container", func() {
+			energy, resets := accumulateSeries([]map[string]float64{
+				{"a": 10},
+				{"a": 15, "b": 5},
+				{"a": 30, "b": 5},
+			})
+
+			Expect(energy).To(BeNumerically("~", 35))
+			Expect(resets).To(BeEmpty())
+		})
+	})
+
+	Context("When a Kepler counter decreases", func() {
+		It("should treat the decrease as a counter reset", func() {
+			energy, resets := accumulateSeries([]map[string]float64{
+				{"a": 100, "b": 50},
+				{"a": 120, "b": 60},
+				// Kepler restarted
+				{"a": 4, "b": 70},
+				{"a": 10, "b": 80},
+			})
+
+			// a: 100 + 20 + 4 + 6, b: 50 + 10 + 10 + 10
+			Expect(energy).To(BeNumerically("~", 210))
+			Expect(resets).To(Equal([]string{"a"}))
+		})
+
+		It("should report every reset of the counters", func() {
+			energy, resets := accumulateSeries([]map[string]float64{
+				{"a": 10, "b": 10},
+				{"a": 2, "b": 3},
+				{"a": 1, "b": 5},
+			})
+
+			// a: 10 + 2 + 1, b: 10 + 3 + 2
+			Expect(energy).To(BeNumerically("~", 28))
+			Expect(resets).To(Equal([]string{"a", "b", "a"}))
+		})
+
+		It("should never decrease the accumulated energy", func() {
+			activeContainers := map[string]float64{"a": 1000}
+
+			increases, resets := accumulateContainerEnergy(activeContainers, map[string]float64{"a": 0})
+
+			Expect(increases["a"]).To(BeNumerically(">=", 0))
+			Expect(resets).To(Equal([]string{"a"}))
+			Expect(activeContainers).To(Equal(map[string]float64{"a": 0}))
+		})
+	})
+
+	Context("When containers come and go", func() {
+		It("should forget the containers that are not reported anymore", func() {
+			activeContainers := map[string]float64{"a": 10, "b": 20}
+
+			increases, resets := accumulateContainerEnergy(activeContainers, map[string]float64{"b": 25, "c": 3})
+
+			Expect(increases).To(Equal(map[string]float64{"b": 5, "c": 3}))
+			Expect(resets).To(BeEmpty())
+			Expect(activeContainers).To(Equal(map[string]float64{"b": 25, "c": 3}))
+		})
+	})
+
+	Context("When containers terminate between two samples", func() {
+		It("should credit the tail energy of the terminated containers before forgetting them", func() {
+			activeContainers := map[string]float64{"a": 10, "b": 20}
+			metricValues := map[string]float64{"b": 25}
+
+			vanished := vanishedContainers(activeContainers, metricValues)
+			Expect(vanished).To(Equal([]string{"a"}))
+
+			tailIncreases, resets := accumulateContainerTailEnergy(activeContainers, map[string]float64{"a": 14})
+			Expect(tailIncreases).To(Equal(map[string]float64{"a": 4}))
+			Expect(resets).To(BeEmpty())
+			Expect(activeContainers).NotTo(HaveKey("a"))
+
+			increases, _ := accumulateContainerEnergy(activeContainers, metricValues)
+			Expect(increases).To(Equal(map[string]float64{"b": 5}))
+		})
+
+		It("should forget the terminated containers without a last value", func() {
+			activeContainers := map[string]float64{"a": 10}
+
+			tailIncreases, _ := accumulateContainerTailEnergy(activeContainers, nil)
+			Expect(tailIncreases).To(BeEmpty())
+
+			increases, _ := accumulateContainerEnergy(activeContainers, map[string]float64{})
+			Expect(increases).To(BeEmpty())
+			Expect(activeContainers).To(BeEmpty())
+		})
+
+		It("should treat a lower last value as a counter reset", func() {
+			activeContainers := map[string]float64{"a": 10}
+
+			tailIncreases, resets := accumulateContainerTailEnergy(activeContainers, map[string]float64{"a": 3})
+			Expect(tailIncreases).To(Equal(map[string]float64{"a": 3}))
+			Expect(resets).To(Equal([]string{"a"}))
+		})
+	})
+})
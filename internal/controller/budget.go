@@ -0,0 +1,252 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	defaultBudgetWarningThreshold = 0.8         // Utilization ratio from which a warning is raised by default
+	budgetRollingRefreshInterval  = time.Minute // Minimum time between two queries of the start of the window of a rolling budget
+)
+
+// Resources of a budget, exported as the resource label of the utilization metric
+const (
+	budgetResourceEnergy = "energy"
+	budgetResourceCarbon = "carbon"
+)
+
+// ValidateBudgetSpec checks the budget of a label group
+func ValidateBudgetSpec(spec *susqlv2.BudgetSpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	switch spec.Period {
+	case susqlv2.BudgetPeriodDaily, susqlv2.BudgetPeriodMonthly, susqlv2.BudgetPeriodQuarterly:
+		if spec.Window != "" {
+			return fmt.Errorf("the budget window is only used by the '%s' period", susqlv2.BudgetPeriodRolling)
+		}
+	case susqlv2.BudgetPeriodRolling:
+		window, err := model.ParseDuration(spec.Window)
+		if err != nil {
+			return fmt.Errorf("invalid budget window '%s': %w", spec.Window, err)
+		}
+		if window <= 0 {
+			return fmt.Errorf("invalid budget window '%s': it must be positive", spec.Window)
+		}
+	default:
+		return fmt.Errorf("unknown budget period '%s'", spec.Period)
+	}
+
+	if spec.EnergyJoules == nil && spec.CarbonGrams == nil {
+		return fmt.Errorf("the budget sets neither an energy nor a carbon limit")
+	}
+	if (spec.EnergyJoules != nil && spec.EnergyJoules.Sign() <= 0) || (spec.CarbonGrams != nil && spec.CarbonGrams.Sign() <= 0) {
+		return fmt.Errorf("the budget limits must be positive")
+	}
+
+	if threshold, err := budgetWarningThreshold(spec); err != nil || math.IsNaN(threshold) || threshold <= 0 || threshold > 1 {
+		return fmt.Errorf("invalid budget warning threshold '%s', expected a decimal in (0, 1]", spec.WarningThreshold)
+	}
+
+	if spec.PrometheusRule != nil && spec.PrometheusRule.For != "" {
+		if _, err := model.ParseDuration(spec.PrometheusRule.For); err != nil {
+			return fmt.Errorf("invalid budget alert duration '%s': %w", spec.PrometheusRule.For, err)
+		}
+	}
+
+	return nil
+}
+
+// Warning threshold of a budget
+func budgetWarningThreshold(spec *susqlv2.BudgetSpec) (float64, error) {
+	if spec.WarningThreshold == "" {
+		return defaultBudgetWarningThreshold, nil
+	}
+
+	return strconv.ParseFloat(spec.WarningThreshold, 64)
+}
+
+// Start and end of the period of a budget containing a time, in UTC
+func budgetPeriod(spec *susqlv2.BudgetSpec, now time.Time) (time.Time, *time.Time) {
+	now = now.UTC()
+
+	var start, end time.Time
+	switch spec.Period {
+	case susqlv2.BudgetPeriodDaily:
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 0, 1)
+	case susqlv2.BudgetPeriodMonthly:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, 0)
+	case susqlv2.BudgetPeriodQuarterly:
+		start = time.Date(now.Year(), (now.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 3, 0)
+	default:
+		window, _ := model.ParseDuration(spec.Window)
+		return now.Add(-time.Duration(window)), nil
+	}
+
+	return start, &end
+}
+
+// Whether the accumulated totals at the start of the period of a budget need to be queried again: at the start of
+// a new period, or when the window of a rolling period moved
+func budgetPeriodChanged(spec *susqlv2.BudgetSpec, budget *susqlv2.BudgetStatus, start time.Time) bool {
+	if budget == nil {
+		return true
+	}
+	if spec.Period == susqlv2.BudgetPeriodRolling {
+		return start.Sub(budget.PeriodStart.Time) >= budgetRollingRefreshInterval
+	}
+
+	return !start.Equal(budget.PeriodStart.Time)
+}
+
+// Utilization ratio of a budget limit, zero without a limit
+func budgetUtilization(usage float64, limit *resource.Quantity) float64 {
+	if limit == nil || limit.Sign() <= 0 {
+		return 0
+	}
+
+	return usage / quantityToFloat(limit)
+}
+
+// Format a utilization ratio for the status, empty without a limit
+func formatUtilization(utilization float64, limit *resource.Quantity) string {
+	if limit == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(utilization, 'f', 4, 64)
+}
+
+// Accumulated value of a SusQL metric at a time, zero when the series did not exist yet
+func (r *LabelGroupReconciler) susqlValueAt(ctx context.Context, susqlPrometheusQuery string, at time.Time) (float64, error) {
+	susqlAPI, err := r.susqlAPI()
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := queryValue(ctx, susqlAPI, fmt.Sprintf("max_over_time(%s[%s])", susqlPrometheusQuery, maxQueryTime), at)
+	if errors.Is(err, errNoValue) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+// evaluateBudget updates the usage of the budget of a label group in the current period from its accumulated totals,
+// and sets its BudgetHealthy condition. It returns the utilization ratios of the limits of the budget.
+func (r *LabelGroupReconciler) evaluateBudget(ctx context.Context, labelGroup labelGroupObject, now time.Time) map[string]float64 {
+	spec := labelGroup.budgetSpec()
+	status := labelGroup.groupStatus()
+
+	if spec == nil {
+		status.Budget = nil
+		meta.RemoveStatusCondition(&status.Conditions, susqlv2.ConditionBudgetHealthy)
+		return nil
+	}
+
+	totalEnergy := quantityToFloat(status.TotalEnergyJoules)
+	totalCarbon := quantityToFloat(status.TotalCarbonGrams)
+	budget := status.Budget
+	start, end := budgetPeriod(spec, now)
+
+	if budgetPeriodChanged(spec, budget, start) {
+		energyAtStart, energyErr := r.susqlValueAt(ctx, status.SusQLPrometheusEnergyQuery, start)
+		carbonAtStart, carbonErr := r.susqlValueAt(ctx, status.SusQLPrometheusCarbonQuery, start)
+
+		if err := errors.Join(energyErr, carbonErr); err != nil {
+			r.Logger.V(0).Error(err, fmt.Sprintf("[evaluateBudget] Couldn't query the totals of %s at the start of its budget period.", labelGroup.describe()))
+			if budget != nil && spec.Period == susqlv2.BudgetPeriodRolling {
+				// Keep the previous window until the query succeeds
+				energyAtStart, carbonAtStart = quantityToFloat(&budget.EnergyJoulesAtPeriodStart), quantityToFloat(&budget.CarbonGramsAtPeriodStart)
+				start = budget.PeriodStart.Time
+			} else {
+				// Count the usage from now on
+				energyAtStart, carbonAtStart = totalEnergy, totalCarbon
+			}
+		}
+
+		budget = &susqlv2.BudgetStatus{
+			PeriodStart:               metav1.Time{Time: start},
+			EnergyJoulesAtPeriodStart: *energyQuantity(min(energyAtStart, totalEnergy)),
+			CarbonGramsAtPeriodStart:  *carbonQuantity(min(carbonAtStart, totalCarbon)),
+		}
+		if end != nil {
+			budget.PeriodEnd = &metav1.Time{Time: *end}
+		}
+	}
+
+	energy := totalEnergy - quantityToFloat(&budget.EnergyJoulesAtPeriodStart)
+	carbon := totalCarbon - quantityToFloat(&budget.CarbonGramsAtPeriodStart)
+	budget.EnergyJoules = *energyQuantity(energy)
+	budget.CarbonGrams = *carbonQuantity(carbon)
+
+	utilizations := make(map[string]float64)
+	if spec.EnergyJoules != nil {
+		utilizations[budgetResourceEnergy] = budgetUtilization(energy, spec.EnergyJoules)
+	}
+	if spec.CarbonGrams != nil {
+		utilizations[budgetResourceCarbon] = budgetUtilization(carbon, spec.CarbonGrams)
+	}
+	budget.EnergyUtilization = formatUtilization(utilizations[budgetResourceEnergy], spec.EnergyJoules)
+	budget.CarbonUtilization = formatUtilization(utilizations[budgetResourceCarbon], spec.CarbonGrams)
+	status.Budget = budget
+
+	r.setBudgetCondition(labelGroup, spec, utilizations)
+
+	return utilizations
+}
+
+// Set the BudgetHealthy condition from the highest utilization ratio of the limits of a budget
+func (r *LabelGroupReconciler) setBudgetCondition(labelGroup labelGroupObject, spec *susqlv2.BudgetSpec, utilizations map[string]float64) bool {
+	threshold, _ := budgetWarningThreshold(spec)
+
+	resourceName, highest := "", 0.0
+	for _, name := range []string{budgetResourceEnergy, budgetResourceCarbon} {
+		if utilization, found := utilizations[name]; found && (resourceName == "" || utilization > highest) {
+			resourceName, highest = name, utilization
+		}
+	}
+
+	message := fmt.Sprintf("%.1f%% of the %s budget of the %s period is used", highest*100, resourceName, spec.Period)
+
+	switch {
+	case highest >= 1:
+		return r.setCondition(labelGroup, susqlv2.ConditionBudgetHealthy, metav1.ConditionFalse, reasonBudgetExceeded, message)
+	case highest >= threshold:
+		return r.setCondition(labelGroup, susqlv2.ConditionBudgetHealthy, metav1.ConditionFalse, reasonBudgetWarning, message)
+	default:
+		return r.setCondition(labelGroup, susqlv2.ConditionBudgetHealthy, metav1.ConditionTrue, reasonBudgetAvailable, message)
+	}
+}
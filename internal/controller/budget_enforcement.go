@@ -0,0 +1,576 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	budgetEnforcementFinalizer = "susql.ibm.com/budget-enforcement" // Holds the deletion of a label group until its budget enforcement actions are reversed
+)
+
+// Kinds of the workloads the budget enforcement actions are taken on
+const (
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+	kindReplicaSet  = "ReplicaSet"
+	kindJob         = "Job"
+	kindCronJob     = "CronJob"
+)
+
+// workloadRef identifies a workload controlling pods of a label group
+type workloadRef struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// Kinds of the workloads an enforcement action is taken on
+func budgetActionKinds(action susqlv2.BudgetEnforcementActionType) []string {
+	switch action {
+	case susqlv2.BudgetEnforcementSuspendJobs:
+		return []string{kindJob, kindCronJob}
+	case susqlv2.BudgetEnforcementScaleDown:
+		return []string{kindDeployment, kindStatefulSet}
+	default:
+		return nil
+	}
+}
+
+// Whether a limit of a budget is exceeded
+func budgetExceeded(utilizations map[string]float64) bool {
+	for _, utilization := range utilizations {
+		if utilization >= 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Kind of a label group, recorded for the actions taken on the label group itself
+func labelGroupKind(labelGroup labelGroupObject) string {
+	if _, ok := labelGroup.(*clusterLabelGroup); ok {
+		return "ClusterLabelGroup"
+	}
+
+	return "LabelGroup"
+}
+
+// Reader of the workloads of the label groups, which are read directly from the API server so that the manager
+// does not cache all the workloads of the cluster
+func (r *LabelGroupReconciler) workloadReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+
+	return r.Client
+}
+
+// New empty object of a workload kind
+func newWorkloadObject(kind string) client.Object {
+	switch kind {
+	case kindDeployment:
+		return &appsv1.Deployment{}
+	case kindStatefulSet:
+		return &appsv1.StatefulSet{}
+	case kindJob:
+		return &batchv1.Job{}
+	case kindCronJob:
+		return &batchv1.CronJob{}
+	default:
+		return nil
+	}
+}
+
+// Replicas field of a Deployment or StatefulSet, nil for the other workloads
+func workloadReplicas(object client.Object) **int32 {
+	switch workload := object.(type) {
+	case *appsv1.Deployment:
+		return &workload.Spec.Replicas
+	case *appsv1.StatefulSet:
+		return &workload.Spec.Replicas
+	default:
+		return nil
+	}
+}
+
+// Suspend field of a Job or CronJob, nil for the other workloads
+func workloadSuspend(object client.Object) **bool {
+	switch workload := object.(type) {
+	case *batchv1.Job:
+		return &workload.Spec.Suspend
+	case *batchv1.CronJob:
+		return &workload.Spec.Suspend
+	default:
+		return nil
+	}
+}
+
+// Number of replicas of a workload, 1 when it is not set
+func replicasOf(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+
+	return *replicas
+}
+
+// Controller of an object when it is of a kind of an API group
+func controllerOf(object metav1.Object, group string, kind string) string {
+	owner := metav1.GetControllerOf(object)
+	if owner == nil || owner.Kind != kind {
+		return ""
+	}
+
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil || gv.Group != group {
+		return ""
+	}
+
+	return owner.Name
+}
+
+// podWorkloads resolves the Deployments, StatefulSets, Jobs and CronJobs controlling a list of pods, through their
+// ReplicaSets and Jobs
+func (r *LabelGroupReconciler) podWorkloads(ctx context.Context, podsByNamespace map[string][]corev1.Pod) ([]workloadRef, error) {
+	var workloads []workloadRef
+	found := make(map[workloadRef]bool)
+	resolved := make(map[workloadRef]bool)
+
+	add := func(workload workloadRef) {
+		if workload.name != "" && !found[workload] {
+			found[workload] = true
+			workloads = append(workloads, workload)
+		}
+	}
+
+	// Get the controller of a ReplicaSet or Job controlling pods, only once per ReplicaSet or Job
+	ownerOf := func(owned workloadRef, object client.Object, group string, kind string) (string, error) {
+		if resolved[owned] {
+			return "", nil
+		}
+		resolved[owned] = true
+
+		if err := r.workloadReader().Get(ctx, types.NamespacedName{Namespace: owned.namespace, Name: owned.name}, object); err != nil {
+			return "", client.IgnoreNotFound(err)
+		}
+
+		return controllerOf(object, group, kind), nil
+	}
+
+	for _, namespace := range slices.Sorted(maps.Keys(podsByNamespace)) {
+		for _, pod := range podsByNamespace[namespace] {
+			if name := controllerOf(&pod, appsv1.GroupName, kindStatefulSet); name != "" {
+				add(workloadRef{kind: kindStatefulSet, namespace: namespace, name: name})
+			}
+
+			if name := controllerOf(&pod, appsv1.GroupName, kindReplicaSet); name != "" {
+				deployment, err := ownerOf(workloadRef{kind: kindReplicaSet, namespace: namespace, name: name}, &appsv1.ReplicaSet{}, appsv1.GroupName, kindDeployment)
+				if err != nil {
+					return nil, err
+				}
+				add(workloadRef{kind: kindDeployment, namespace: namespace, name: deployment})
+			}
+
+			if name := controllerOf(&pod, batchv1.GroupName, kindJob); name != "" {
+				job := workloadRef{kind: kindJob, namespace: namespace, name: name}
+				cronJob, err := ownerOf(job, &batchv1.Job{}, batchv1.GroupName, kindCronJob)
+				if err != nil {
+					return nil, err
+				}
+				add(job)
+				add(workloadRef{kind: kindCronJob, namespace: namespace, name: cronJob})
+			}
+		}
+	}
+
+	return workloads, nil
+}
+
+// pendingEnforcement is an enforcement action whose record is stored before its workload is patched
+type pendingEnforcement struct {
+	record susqlv2.BudgetEnforcementRecord
+	object client.Object // Workload to patch, nil for the actions taken on the label group itself
+	patch  client.Patch
+}
+
+// Prepare an enforcement action on a workload, which is taken by patching the returned object. It returns nil when
+// the workload does not exist anymore, is already suspended, or already runs no more replicas than the floor.
+func (r *LabelGroupReconciler) prepareEnforcement(ctx context.Context, action susqlv2.BudgetEnforcementActionType, workload workloadRef,
+	replicasFloor int32, now time.Time) (*pendingEnforcement, error) {
+	object := newWorkloadObject(workload.kind)
+	if err := r.workloadReader().Get(ctx, types.NamespacedName{Namespace: workload.namespace, Name: workload.name}, object); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+
+	record := susqlv2.BudgetEnforcementRecord{
+		Action:    action,
+		Kind:      workload.kind,
+		Namespace: workload.namespace,
+		Name:      workload.name,
+		Time:      metav1.Time{Time: now},
+	}
+
+	switch action {
+	case susqlv2.BudgetEnforcementSuspendJobs:
+		suspend := workloadSuspend(object)
+		if *suspend != nil && **suspend {
+			return nil, nil
+		}
+		suspended := true
+		*suspend = &suspended
+	case susqlv2.BudgetEnforcementScaleDown:
+		replicas := workloadReplicas(object)
+		previous := replicasOf(*replicas)
+		if previous <= replicasFloor {
+			return nil, nil
+		}
+		record.PreviousReplicas = &previous
+		*replicas = &replicasFloor
+	}
+
+	return &pendingEnforcement{record: record, object: object, patch: patch}, nil
+}
+
+// Reverse an enforcement action on a workload, unless the workload was resumed or scaled up since
+func (r *LabelGroupReconciler) reverseOnWorkload(ctx context.Context, record susqlv2.BudgetEnforcementRecord) error {
+	object := newWorkloadObject(record.Kind)
+	if object == nil {
+		return nil
+	}
+
+	if err := r.workloadReader().Get(ctx, types.NamespacedName{Namespace: record.Namespace, Name: record.Name}, object); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+
+	switch record.Action {
+	case susqlv2.BudgetEnforcementSuspendJobs:
+		suspend := workloadSuspend(object)
+		if suspend == nil || *suspend == nil || !**suspend {
+			return nil
+		}
+		resumed := false
+		*suspend = &resumed
+	case susqlv2.BudgetEnforcementScaleDown:
+		replicas := workloadReplicas(object)
+		if replicas == nil || record.PreviousReplicas == nil || replicasOf(*replicas) >= *record.PreviousReplicas {
+			return nil
+		}
+		previous := *record.PreviousReplicas
+		*replicas = &previous
+	default:
+		return nil
+	}
+
+	return r.Patch(ctx, object, patch)
+}
+
+// Set the budget blocked label and the budget enforcement finalizer of a label group. The metadata is patched on
+// a copy so that the status of the label group, which may not be updated yet, is not overwritten.
+func (r *LabelGroupReconciler) patchBudgetEnforcementMetadata(ctx context.Context, labelGroup labelGroupObject, blocked bool, finalizer bool) error {
+	object := labelGroup.object()
+	patched := object.DeepCopyObject().(client.Object)
+
+	objectLabels := patched.GetLabels()
+	changed := false
+
+	if _, found := objectLabels[susqlv2.BudgetBlockedLabel]; found != blocked {
+		if blocked {
+			if objectLabels == nil {
+				objectLabels = make(map[string]string)
+			}
+			objectLabels[susqlv2.BudgetBlockedLabel] = "true"
+		} else {
+			delete(objectLabels, susqlv2.BudgetBlockedLabel)
+		}
+		patched.SetLabels(objectLabels)
+		changed = true
+	}
+
+	if finalizer {
+		changed = controllerutil.AddFinalizer(patched, budgetEnforcementFinalizer) || changed
+	} else {
+		changed = controllerutil.RemoveFinalizer(patched, budgetEnforcementFinalizer) || changed
+	}
+
+	if !changed {
+		return nil
+	}
+
+	if err := r.Patch(ctx, patched, client.MergeFrom(object)); err != nil {
+		return err
+	}
+
+	object.SetLabels(patched.GetLabels())
+	object.SetFinalizers(patched.GetFinalizers())
+	object.SetResourceVersion(patched.GetResourceVersion())
+
+	return nil
+}
+
+// reconcileBudgetEnforcement takes the enforcement actions of the budget of a label group on the workloads of its
+// pods while a limit is exceeded, and reverses them otherwise, e.g., once a new budget period started. The actions
+// in effect are recorded in the status, which is updated by the caller. The records of new actions on workloads are
+// stored in the status before the workloads are patched, so that their previous state is never lost.
+func (r *LabelGroupReconciler) reconcileBudgetEnforcement(ctx context.Context, labelGroup labelGroupObject, podsByNamespace map[string][]corev1.Pod,
+	utilizations map[string]float64, now time.Time) error {
+	spec := labelGroup.budgetSpec()
+	status := labelGroup.groupStatus()
+	object := labelGroup.object()
+
+	var enforcement *susqlv2.BudgetEnforcement
+	if r.EnableBudgetEnforcement && object.GetDeletionTimestamp().IsZero() && spec != nil && spec.Enforcement != nil && budgetExceeded(utilizations) {
+		enforcement = spec.Enforcement
+	}
+
+	// New pods are only blocked by the pod admission webhook, which is not served without the webhooks
+	if enforcement != nil && !r.EnableWebhooks && slices.Contains(enforcement.Actions, susqlv2.BudgetEnforcementBlockNewPods) {
+		enforcement = enforcement.DeepCopy()
+		enforcement.Actions = slices.DeleteFunc(enforcement.Actions, func(action susqlv2.BudgetEnforcementActionType) bool {
+			return action == susqlv2.BudgetEnforcementBlockNewPods
+		})
+		if condition := meta.FindStatusCondition(status.Conditions, susqlv2.ConditionBudgetHealthy); condition != nil {
+			r.setCondition(labelGroup, susqlv2.ConditionBudgetHealthy, condition.Status, condition.Reason,
+				condition.Message+", new pods are not blocked as the pod admission webhook is not served (ENABLE-WEBHOOKS)")
+		}
+	}
+
+	var errs []error
+
+	// Reverse the actions that are not in effect anymore
+	var records []susqlv2.BudgetEnforcementRecord
+	for _, record := range status.BudgetEnforcement {
+		if enforcement != nil && slices.Contains(enforcement.Actions, record.Action) {
+			records = append(records, record)
+			continue
+		}
+
+		if err := r.reverseOnWorkload(ctx, record); err != nil {
+			errs = append(errs, fmt.Errorf("couldn't reverse %s of %s '%s': %w", record.Action, record.Kind, record.Name, err))
+			records = append(records, record)
+			continue
+		}
+
+		r.Logger.V(1).Info(fmt.Sprintf("[reconcileBudgetEnforcement] Reversed %s of %s '%s' for %s.", record.Action, record.Kind, record.Name, labelGroup.describe()))
+		if r.Recorder != nil {
+			r.Recorder.Event(object, corev1.EventTypeNormal, reasonBudgetEnforcementReversed,
+				fmt.Sprintf("Reversed %s of %s '%s'", record.Action, record.Kind, record.Name))
+		}
+	}
+
+	// Take the actions on the workloads they were not taken on yet
+	if enforcement != nil {
+		var pendings []pendingEnforcement
+		taken := make(map[workloadRef]bool, len(records))
+		for _, record := range records {
+			taken[workloadRef{kind: record.Kind, namespace: record.Namespace, name: record.Name}] = true
+		}
+
+		var workloads []workloadRef
+		if slices.ContainsFunc(enforcement.Actions, func(action susqlv2.BudgetEnforcementActionType) bool { return budgetActionKinds(action) != nil }) {
+			var err error
+			if workloads, err = r.podWorkloads(ctx, podsByNamespace); err != nil {
+				errs = append(errs, fmt.Errorf("couldn't resolve the workloads of the pods: %w", err))
+			}
+		}
+
+		for _, action := range enforcement.Actions {
+			if action == susqlv2.BudgetEnforcementBlockNewPods {
+				self := workloadRef{kind: labelGroupKind(labelGroup), namespace: object.GetNamespace(), name: object.GetName()}
+				if !taken[self] {
+					taken[self] = true
+					pendings = append(pendings, pendingEnforcement{
+						record: susqlv2.BudgetEnforcementRecord{Action: action, Kind: self.kind, Namespace: self.namespace, Name: self.name, Time: metav1.Time{Time: now}},
+					})
+				}
+				continue
+			}
+
+			for _, workload := range workloads {
+				if taken[workload] || !slices.Contains(budgetActionKinds(action), workload.kind) {
+					continue
+				}
+
+				pending, err := r.prepareEnforcement(ctx, action, workload, enforcement.ReplicasFloor, now)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("couldn't take %s on %s '%s': %w", action, workload.kind, workload.name, err))
+					continue
+				}
+				if pending == nil {
+					continue
+				}
+
+				taken[workload] = true
+				pendings = append(pendings, *pending)
+			}
+		}
+
+		records = r.takeEnforcement(ctx, labelGroup, records, pendings, &errs)
+	}
+
+	status.BudgetEnforcement = records
+
+	blocked := slices.ContainsFunc(records, func(record susqlv2.BudgetEnforcementRecord) bool {
+		return record.Action == susqlv2.BudgetEnforcementBlockNewPods
+	})
+	if err := r.patchBudgetEnforcementMetadata(ctx, labelGroup, blocked, len(records) > 0); err != nil {
+		errs = append(errs, fmt.Errorf("couldn't update the budget enforcement label and finalizer: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// takeEnforcement stores the records of the pending actions in the status of a label group, and then patches their
+// workloads. The actions are not taken when the status can't be updated, and the records of the actions whose
+// workload couldn't be patched are dropped. It returns the records of the actions in effect.
+func (r *LabelGroupReconciler) takeEnforcement(ctx context.Context, labelGroup labelGroupObject, records []susqlv2.BudgetEnforcementRecord,
+	pendings []pendingEnforcement, errs *[]error) []susqlv2.BudgetEnforcementRecord {
+	if len(pendings) == 0 {
+		return records
+	}
+
+	status := labelGroup.groupStatus()
+	object := labelGroup.object()
+
+	status.BudgetEnforcement = slices.Clone(records)
+	for _, pending := range pendings {
+		status.BudgetEnforcement = append(status.BudgetEnforcement, pending.record)
+	}
+	if err := r.Status().Update(ctx, object); err != nil {
+		*errs = append(*errs, fmt.Errorf("couldn't record the budget enforcement actions before taking them: %w", err))
+		return records
+	}
+
+	for _, pending := range pendings {
+		record := pending.record
+		if pending.object == nil {
+			records = append(records, record)
+			continue
+		}
+
+		if err := r.Patch(ctx, pending.object, pending.patch); err != nil {
+			*errs = append(*errs, fmt.Errorf("couldn't take %s on %s '%s': %w", record.Action, record.Kind, record.Name, err))
+			continue
+		}
+
+		records = append(records, record)
+
+		r.Logger.V(1).Info(fmt.Sprintf("[reconcileBudgetEnforcement] Took %s on %s '%s' for %s.", record.Action, record.Kind, record.Name, labelGroup.describe()))
+		if r.Recorder != nil {
+			r.Recorder.Event(object, corev1.EventTypeWarning, reasonBudgetEnforced,
+				fmt.Sprintf("Took %s on %s '%s' as the budget is exceeded", record.Action, record.Kind, record.Name))
+		}
+	}
+
+	return records
+}
+
+// finalizeBudgetEnforcement reverses the budget enforcement actions of a label group being deleted, and releases
+// its deletion
+func (r *LabelGroupReconciler) finalizeBudgetEnforcement(ctx context.Context, labelGroup labelGroupObject) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(labelGroup.object(), budgetEnforcementFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileBudgetEnforcement(ctx, labelGroup, nil, nil, time.Now()); err != nil {
+		r.Logger.V(0).Error(err, fmt.Sprintf("[finalizeBudgetEnforcement] Couldn't reverse the budget enforcement of %s.", labelGroup.describe()))
+
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil && !apierrors.IsNotFound(err) {
+			r.Logger.V(0).Error(err, "[finalizeBudgetEnforcement] Couldn't update the budget enforcement actions.")
+		}
+
+		return ctrl.Result{RequeueAfter: errorDelay}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// BudgetBlockingLabelGroup returns the description of a LabelGroup or ClusterLabelGroup whose budget enforcement
+// blocks the creation of a pod, or an empty string when the pod can be created
+func BudgetBlockingLabelGroup(ctx context.Context, reader client.Reader, namespace string, podLabels map[string]string) (string, error) {
+	blocked := client.HasLabels{susqlv2.BudgetBlockedLabel}
+
+	labelGroups := &susqlv2.LabelGroupList{}
+	if err := reader.List(ctx, labelGroups, client.InNamespace(namespace), blocked); err != nil {
+		return "", err
+	}
+
+	for i := range labelGroups.Items {
+		labelGroup := &namespacedLabelGroup{&labelGroups.Items[i]}
+		if labelGroup.Spec.Selector == nil && len(labelGroup.Status.KubernetesLabels) == 0 {
+			// The SusQL Kubernetes labels are not constructed yet
+			continue
+		}
+		podSelector, err := podSelectorForLabelGroup(labelGroup.LabelGroup)
+		if err != nil {
+			continue
+		}
+		if podSelector.Matches(labels.Set(podLabels)) {
+			return labelGroup.describe(), nil
+		}
+	}
+
+	clusterLabelGroups := &susqlv2.ClusterLabelGroupList{}
+	if err := reader.List(ctx, clusterLabelGroups, blocked); err != nil {
+		return "", err
+	}
+	if len(clusterLabelGroups.Items) == 0 {
+		return "", nil
+	}
+
+	podNamespace := &corev1.Namespace{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: namespace}, podNamespace); err != nil {
+		return "", err
+	}
+
+	for i := range clusterLabelGroups.Items {
+		clusterLabelGroup := &clusterLabelGroup{&clusterLabelGroups.Items[i]}
+		namespaceSelector, podSelector, err := clusterLabelGroup.selectors()
+		if err != nil {
+			continue
+		}
+		if namespaceSelector.Matches(labels.Set(podNamespace.Labels)) && podSelector.Matches(labels.Set(podLabels)) {
+			return clusterLabelGroup.describe(), nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Budget Enforcement", func() {
+	ownedBy := func(apiVersion string, kind string, name string) []metav1.OwnerReference {
+		isController := true
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID(name), Controller: &isController}}
+	}
+
+	newPod := func(name string, owners []metav1.OwnerReference) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "shop"}, OwnerReferences: owners}}
+	}
+
+	var reconciler *LabelGroupReconciler
+	var labelGroup *namespacedLabelGroup
+	var podsByNamespace map[string][]corev1.Pod
+	ctx := context.Background()
+	now := time.Date(2026, 8, 17, 15, 30, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		replicas, suspended := int32(3), false
+		limit := resource.MustParse("40")
+		statefulSetReplicas := int32(2)
+
+		labelGroup = &namespacedLabelGroup{&susqlv2.LabelGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+			Spec: susqlv2.LabelGroupSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+				Budget: &susqlv2.BudgetSpec{
+					Period:      susqlv2.BudgetPeriodDaily,
+					CarbonGrams: &limit,
+					Enforcement: &susqlv2.BudgetEnforcement{Actions: []susqlv2.BudgetEnforcementActionType{
+						susqlv2.BudgetEnforcementSuspendJobs, susqlv2.BudgetEnforcementScaleDown, susqlv2.BudgetEnforcementBlockNewPods,
+					}},
+				},
+			},
+		}}
+
+		reconciler = &LabelGroupReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				labelGroup.LabelGroup,
+				&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"}, Spec: appsv1.DeploymentSpec{Replicas: &replicas}},
+				&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f", Namespace: "shop", OwnerReferences: ownedBy("apps/v1", "Deployment", "web")}},
+				&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "shop"}, Spec: appsv1.StatefulSetSpec{Replicas: &statefulSetReplicas}},
+				&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "shop"}, Spec: batchv1.CronJobSpec{Suspend: &suspended}},
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "report-2960", Namespace: "shop", OwnerReferences: ownedBy("batch/v1", "CronJob", "report")}},
+			).WithStatusSubresource(labelGroup.LabelGroup).Build(),
+			EnableBudgetEnforcement: true,
+			EnableWebhooks:          true,
+			Logger:                  logr.Discard(),
+		}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(labelGroup.LabelGroup), labelGroup.LabelGroup)).To(Succeed())
+
+		podsByNamespace = map[string][]corev1.Pod{"shop": {
+			newPod("web-5d8f-a", ownedBy("apps/v1", "ReplicaSet", "web-5d8f")),
+			newPod("web-5d8f-b", ownedBy("apps/v1", "ReplicaSet", "web-5d8f")),
+			newPod("db-0", ownedBy("apps/v1", "StatefulSet", "db")),
+			newPod("report-2960-x", ownedBy("batch/v1", "Job", "report-2960")),
+			newPod("debug", nil),
+		}}
+	})
+
+	It("should resolve the workloads controlling the pods", func() {
+		workloads, err := reconciler.podWorkloads(ctx, podsByNamespace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workloads).To(ConsistOf(
+			workloadRef{kind: kindDeployment, namespace: "shop", name: "web"},
+			workloadRef{kind: kindStatefulSet, namespace: "shop", name: "db"},
+			workloadRef{kind: kindJob, namespace: "shop", name: "report-2960"},
+			workloadRef{kind: kindCronJob, namespace: "shop", name: "report"},
+		))
+	})
+
+	It("should take the actions while the budget is exceeded and reverse them afterwards", func() {
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, map[string]float64{budgetResourceCarbon: 1.2}, now)).To(Succeed())
+
+		records := labelGroup.Status.BudgetEnforcement
+		Expect(records).To(HaveLen(5))
+		Expect(records[0]).To(Equal(susqlv2.BudgetEnforcementRecord{Action: susqlv2.BudgetEnforcementSuspendJobs, Kind: kindJob, Namespace: "shop", Name: "report-2960", Time: metav1.Time{Time: now}}))
+
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "web"}, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(BeZero())
+		cronJob := &batchv1.CronJob{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "report"}, cronJob)).To(Succeed())
+		Expect(*cronJob.Spec.Suspend).To(BeTrue())
+
+		stored := &susqlv2.LabelGroup{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(labelGroup.LabelGroup), stored)).To(Succeed())
+		Expect(stored.Labels).To(HaveKey(susqlv2.BudgetBlockedLabel))
+		Expect(stored.Finalizers).To(ContainElement(budgetEnforcementFinalizer))
+		Expect(labelGroup.ResourceVersion).To(Equal(stored.ResourceVersion))
+
+		// New pods of the group are blocked
+		Expect(BudgetBlockingLabelGroup(ctx, reconciler.Client, "shop", map[string]string{"app": "shop"})).To(Equal(labelGroup.describe()))
+		Expect(BudgetBlockingLabelGroup(ctx, reconciler.Client, "shop", map[string]string{"app": "other"})).To(BeEmpty())
+		Expect(BudgetBlockingLabelGroup(ctx, reconciler.Client, "other", map[string]string{"app": "shop"})).To(BeEmpty())
+
+		// The actions are only taken once, and workloads without pods keep their records
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, nil, map[string]float64{budgetResourceCarbon: 1.3}, now.Add(time.Minute))).To(Succeed())
+		Expect(labelGroup.Status.BudgetEnforcement).To(Equal(records))
+
+		// The StatefulSet is scaled up by hand in the meantime
+		statefulSet := &appsv1.StatefulSet{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "db"}, statefulSet)).To(Succeed())
+		replicas := int32(5)
+		statefulSet.Spec.Replicas = &replicas
+		Expect(reconciler.Update(ctx, statefulSet)).To(Succeed())
+
+		// A new period starts
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, nil, map[string]float64{budgetResourceCarbon: 0.01}, now.Add(24*time.Hour))).To(Succeed())
+		Expect(labelGroup.Status.BudgetEnforcement).To(BeEmpty())
+
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "web"}, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "db"}, statefulSet)).To(Succeed())
+		Expect(*statefulSet.Spec.Replicas).To(Equal(int32(5)))
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "report"}, cronJob)).To(Succeed())
+		Expect(*cronJob.Spec.Suspend).To(BeFalse())
+
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(labelGroup.LabelGroup), stored)).To(Succeed())
+		Expect(stored.Labels).NotTo(HaveKey(susqlv2.BudgetBlockedLabel))
+		Expect(stored.Finalizers).To(BeEmpty())
+		Expect(BudgetBlockingLabelGroup(ctx, reconciler.Client, "shop", map[string]string{"app": "shop"})).To(BeEmpty())
+	})
+
+	It("should only take the actions when the budget enforcement is enabled", func() {
+		reconciler.EnableBudgetEnforcement = false
+
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, map[string]float64{budgetResourceCarbon: 1.2}, now)).To(Succeed())
+		Expect(labelGroup.Status.BudgetEnforcement).To(BeEmpty())
+		Expect(labelGroup.Finalizers).To(BeEmpty())
+	})
+
+	It("should not block the new pods without the pod admission webhook", func() {
+		reconciler.EnableWebhooks = false
+		reconciler.setBudgetCondition(labelGroup, labelGroup.Spec.Budget, map[string]float64{budgetResourceCarbon: 1.2})
+
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, map[string]float64{budgetResourceCarbon: 1.2}, now)).To(Succeed())
+		Expect(labelGroup.Status.BudgetEnforcement).To(HaveLen(4))
+		Expect(labelGroup.Status.BudgetEnforcement).NotTo(ContainElement(HaveField("Action", susqlv2.BudgetEnforcementBlockNewPods)))
+
+		stored := &susqlv2.LabelGroup{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(labelGroup.LabelGroup), stored)).To(Succeed())
+		Expect(stored.Labels).NotTo(HaveKey(susqlv2.BudgetBlockedLabel))
+
+		condition := meta.FindStatusCondition(labelGroup.Status.Conditions, susqlv2.ConditionBudgetHealthy)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(reasonBudgetExceeded))
+		Expect(condition.Message).To(ContainSubstring("new pods are not blocked as the pod admission webhook is not served"))
+	})
+
+	It("should record the actions in the status before taking them", func() {
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, map[string]float64{budgetResourceCarbon: 1.2}, now)).To(Succeed())
+
+		stored := &susqlv2.LabelGroup{}
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(labelGroup.LabelGroup), stored)).To(Succeed())
+		Expect(stored.Status.BudgetEnforcement).To(HaveLen(5))
+		Expect(*stored.Status.BudgetEnforcement[2].PreviousReplicas).To(Equal(int32(3)))
+	})
+
+	It("should not take the actions when their records can't be stored", func() {
+		reconciler.Client = interceptor.NewClient(reconciler.Client.(client.WithWatch), interceptor.Funcs{
+			SubResourceUpdate: func(context.Context, client.Client, string, client.Object, ...client.SubResourceUpdateOption) error {
+				return errors.New("conflict")
+			},
+		})
+
+		Expect(reconciler.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, map[string]float64{budgetResourceCarbon: 1.2}, now)).NotTo(Succeed())
+
+		Expect(labelGroup.Status.BudgetEnforcement).To(BeEmpty())
+
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "web"}, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(3)))
+		cronJob := &batchv1.CronJob{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "shop", Name: "report"}, cronJob)).To(Succeed())
+		Expect(*cronJob.Spec.Suspend).To(BeFalse())
+	})
+})
@@ -0,0 +1,151 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var (
+	prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"} // Prometheus operator alerting rules
+)
+
+// Name of the PrometheusRule of the budget of a label group, in the namespace of a LabelGroup or of the operator for
+// a ClusterLabelGroup
+func (r *LabelGroupReconciler) budgetRuleName(labelGroup labelGroupObject) (types.NamespacedName, error) {
+	object := labelGroup.object()
+	if object.GetNamespace() != "" {
+		return types.NamespacedName{Namespace: object.GetNamespace(), Name: "susql-budget-" + object.GetName()}, nil
+	}
+
+	if r.OperatorNamespace == "" {
+		return types.NamespacedName{}, fmt.Errorf("the namespace of the operator is unknown")
+	}
+
+	return types.NamespacedName{Namespace: r.OperatorNamespace, Name: "susql-budget-cluster-" + object.GetName()}, nil
+}
+
+// Rule groups of the PrometheusRule of a budget, alerting on its exported utilization ratios
+func budgetRuleGroups(labelGroup labelGroupObject, spec *susqlv2.BudgetSpec, prometheusLabels map[string]string) []interface{} {
+	threshold, _ := budgetWarningThreshold(spec)
+	utilization := buildSusQLPrometheusQuery(susqlBudgetMetricName, prometheusLabels)
+
+	newRule := func(alert string, expr string, severity string, summary string) map[string]interface{} {
+		rule := map[string]interface{}{
+			"alert":       alert,
+			"expr":        expr,
+			"labels":      map[string]interface{}{"severity": severity},
+			"annotations": map[string]interface{}{"summary": summary},
+		}
+		if spec.PrometheusRule.For != "" {
+			rule["for"] = spec.PrometheusRule.For
+		}
+		return rule
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"name": "susql-budget",
+			"rules": []interface{}{
+				newRule("SusQLBudgetWarning", fmt.Sprintf("%s >= %s < 1", utilization, strconv.FormatFloat(threshold, 'f', -1, 64)), "warning",
+					fmt.Sprintf("{{ $labels.resource }} budget of %s at {{ $value | humanizePercentage }}", labelGroup.describe())),
+				newRule("SusQLBudgetExceeded", fmt.Sprintf("%s >= 1", utilization), "critical",
+					fmt.Sprintf("{{ $labels.resource }} budget of %s exceeded at {{ $value | humanizePercentage }}", labelGroup.describe())),
+			},
+		},
+	}
+}
+
+// reconcileBudgetRule creates, updates or deletes the PrometheusRule of the budget of a label group. The rule is
+// owned by the label group, and only applied when it changed since it was last applied by this operator.
+func (r *LabelGroupReconciler) reconcileBudgetRule(ctx context.Context, labelGroup labelGroupObject) error {
+	spec := labelGroup.budgetSpec()
+	status := labelGroup.groupStatus()
+	key := labelGroup.object().GetUID()
+
+	name, err := r.budgetRuleName(labelGroup)
+	if err != nil {
+		return err
+	}
+
+	rule := &unstructured.Unstructured{}
+	rule.SetGroupVersionKind(prometheusRuleGVK)
+	rule.SetNamespace(name.Namespace)
+	rule.SetName(name.Name)
+
+	if spec == nil || spec.PrometheusRule == nil {
+		if applied, found := r.budgetRules.Load(key); found && applied == "" {
+			return nil
+		}
+
+		// Only the rule of the label group is deleted, and there is nothing to delete when rules can't be read
+		if err := r.Get(ctx, name, rule); err != nil {
+			if !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !meta.IsNoMatchError(err) {
+				return err
+			}
+		} else if metav1.IsControlledBy(rule, labelGroup.object()) {
+			if err := r.Delete(ctx, rule); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			r.Logger.V(2).Info(fmt.Sprintf("[reconcileBudgetRule] Deleted the PrometheusRule %s of %s.", name, labelGroup.describe()))
+		}
+		r.budgetRules.Store(key, "")
+
+		return nil
+	}
+
+	groups := budgetRuleGroups(labelGroup, spec, status.PrometheusLabels)
+	ruleLabels := map[string]string{"app.kubernetes.io/managed-by": "susql-operator"}
+	maps.Copy(ruleLabels, spec.PrometheusRule.Labels)
+
+	fingerprint, err := json.Marshal([]interface{}{name, groups, ruleLabels})
+	if err != nil {
+		return err
+	}
+	if applied, found := r.budgetRules.Load(key); found && applied == string(fingerprint) {
+		return nil
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, rule, func() error {
+		rule.SetLabels(ruleLabels)
+		if err := unstructured.SetNestedSlice(rule.Object, groups, "spec", "groups"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(labelGroup.object(), rule, r.Scheme)
+	}); err != nil {
+		return err
+	}
+
+	r.budgetRules.Store(key, string(fingerprint))
+	r.Logger.V(2).Info(fmt.Sprintf("[reconcileBudgetRule] Applied the PrometheusRule %s of %s.", name, labelGroup.describe()))
+
+	return nil
+}
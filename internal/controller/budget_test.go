@@ -0,0 +1,231 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Budget", func() {
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+
+	It("should validate the budget", func() {
+		Expect(ValidateBudgetSpec(nil)).To(Succeed())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodMonthly, CarbonGrams: quantity("1000")})).To(Succeed())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodRolling, Window: "720h", EnergyJoules: quantity("1M")})).To(Succeed())
+
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodMonthly})).To(HaveOccurred())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodRolling, CarbonGrams: quantity("1")})).To(HaveOccurred())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: "weekly", CarbonGrams: quantity("1")})).To(HaveOccurred())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily, CarbonGrams: quantity("0")})).To(HaveOccurred())
+		Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily, CarbonGrams: quantity("1"), WarningThreshold: "1.5"})).To(HaveOccurred())
+		for _, threshold := range []string{"NaN", "Inf", "-Inf"} {
+			Expect(ValidateBudgetSpec(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily, CarbonGrams: quantity("1"), WarningThreshold: threshold})).To(HaveOccurred(), threshold)
+		}
+	})
+
+	It("should compute the periods in UTC", func() {
+		now := time.Date(2026, 8, 17, 15, 30, 0, 0, time.UTC)
+
+		start, end := budgetPeriod(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily}, now)
+		Expect(start).To(Equal(time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC)))
+		Expect(*end).To(Equal(time.Date(2026, 8, 18, 0, 0, 0, 0, time.UTC)))
+
+		start, end = budgetPeriod(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodMonthly}, now)
+		Expect(start).To(Equal(time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(*end).To(Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)))
+
+		start, end = budgetPeriod(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodQuarterly}, now)
+		Expect(start).To(Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(*end).To(Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)))
+
+		start, end = budgetPeriod(&susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodRolling, Window: "1d"}, now)
+		Expect(start).To(Equal(now.Add(-24 * time.Hour)))
+		Expect(end).To(BeNil())
+	})
+
+	Context("when evaluating the usage", func() {
+		var queries []string
+		var server *httptest.Server
+		var reconciler *LabelGroupReconciler
+		var labelGroup *namespacedLabelGroup
+
+		BeforeEach(func() {
+			queries = nil
+			// The accumulated totals of the group at the start of the period
+			server = newFakePrometheus(&queries, `[{"metric":{},"value":[1786000000,"100"]}]`)
+			reconciler = &LabelGroupReconciler{SusQLPrometheusDatabaseUrl: server.URL, Logger: logr.Discard()}
+			labelGroup = &namespacedLabelGroup{&susqlv2.LabelGroup{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"},
+				Spec: susqlv2.LabelGroupSpec{Budget: &susqlv2.BudgetSpec{
+					Period:       susqlv2.BudgetPeriodDaily,
+					EnergyJoules: quantity("100"),
+					CarbonGrams:  quantity("40"),
+				}},
+				Status: susqlv2.LabelGroupStatus{
+					TotalEnergyJoules:          energyQuantity(150),
+					TotalCarbonGrams:           carbonQuantity(130),
+					SusQLPrometheusEnergyQuery: `susql_total_energy_joules{labelgroup_name="shop",labelgroup_namespace="shop"}`,
+					SusQLPrometheusCarbonQuery: `susql_total_carbon_dioxide_grams{labelgroup_name="shop",labelgroup_namespace="shop"}`,
+				},
+			}}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should report the utilization of the limits from the start of the period", func() {
+			now := time.Date(2026, 8, 17, 15, 30, 0, 0, time.UTC)
+
+			utilizations := reconciler.evaluateBudget(context.Background(), labelGroup, now)
+			Expect(utilizations).To(HaveKeyWithValue(budgetResourceEnergy, BeNumerically("~", 0.5, 1e-9)))
+			Expect(utilizations).To(HaveKeyWithValue(budgetResourceCarbon, BeNumerically("~", 0.75, 1e-9)))
+			Expect(queries).To(HaveLen(2))
+
+			budget := labelGroup.Status.Budget
+			Expect(budget.PeriodStart.Time).To(Equal(time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC)))
+			Expect(budget.EnergyUtilization).To(Equal("0.5000"))
+			Expect(budget.CarbonUtilization).To(Equal("0.7500"))
+			Expect(meta.IsStatusConditionTrue(labelGroup.Status.Conditions, susqlv2.ConditionBudgetHealthy)).To(BeTrue())
+
+			// The totals at the start of the period are only queried once
+			labelGroup.Status.TotalCarbonGrams = carbonQuantity(135)
+			reconciler.evaluateBudget(context.Background(), labelGroup, now.Add(time.Hour))
+			Expect(queries).To(HaveLen(2))
+			Expect(meta.FindStatusCondition(labelGroup.Status.Conditions, susqlv2.ConditionBudgetHealthy).Reason).To(Equal(reasonBudgetWarning))
+
+			labelGroup.Status.TotalCarbonGrams = carbonQuantity(145)
+			reconciler.evaluateBudget(context.Background(), labelGroup, now.Add(2*time.Hour))
+			Expect(meta.FindStatusCondition(labelGroup.Status.Conditions, susqlv2.ConditionBudgetHealthy).Reason).To(Equal(reasonBudgetExceeded))
+
+			// A new period starts from the totals at its start
+			reconciler.evaluateBudget(context.Background(), labelGroup, now.Add(24*time.Hour))
+			Expect(queries).To(HaveLen(4))
+			Expect(labelGroup.Status.Budget.PeriodStart.Time).To(Equal(time.Date(2026, 8, 18, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("should count the usage from now on when the totals at the start of the period are unknown", func() {
+			reconciler.SusQLPrometheusDatabaseUrl = "http://127.0.0.1:1"
+
+			utilizations := reconciler.evaluateBudget(context.Background(), labelGroup, time.Now())
+			Expect(utilizations[budgetResourceCarbon]).To(BeZero())
+		})
+
+		It("should remove the budget status without a budget", func() {
+			reconciler.evaluateBudget(context.Background(), labelGroup, time.Now())
+			labelGroup.Spec.Budget = nil
+
+			Expect(reconciler.evaluateBudget(context.Background(), labelGroup, time.Now())).To(BeNil())
+			Expect(labelGroup.Status.Budget).To(BeNil())
+			Expect(meta.FindStatusCondition(labelGroup.Status.Conditions, susqlv2.ConditionBudgetHealthy)).To(BeNil())
+		})
+	})
+
+	It("should alert on the utilization of the budget", func() {
+		labelGroup := &namespacedLabelGroup{&susqlv2.LabelGroup{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop"}}}
+		spec := &susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily, CarbonGrams: quantity("40"), WarningThreshold: "0.9",
+			PrometheusRule: &susqlv2.BudgetPrometheusRule{For: "10m"}}
+
+		groups := budgetRuleGroups(labelGroup, spec, map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop"})
+		rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+		Expect(rules).To(HaveLen(2))
+		Expect(rules[0]).To(HaveKeyWithValue("expr", `susql_budget_utilization_ratio{labelgroup_name="shop",labelgroup_namespace="shop"} >= 0.9 < 1`))
+		Expect(rules[0]).To(HaveKeyWithValue("for", "10m"))
+		Expect(rules[1]).To(HaveKeyWithValue("expr", `susql_budget_utilization_ratio{labelgroup_name="shop",labelgroup_namespace="shop"} >= 1`))
+
+		name, err := (&LabelGroupReconciler{}).budgetRuleName(labelGroup)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name.String()).To(Equal("shop/susql-budget-shop"))
+	})
+
+	It("should only delete the PrometheusRule of the label group", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		labelGroup := &namespacedLabelGroup{&susqlv2.LabelGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop", UID: "shop-uid"},
+			Spec: susqlv2.LabelGroupSpec{Budget: &susqlv2.BudgetSpec{Period: susqlv2.BudgetPeriodDaily, CarbonGrams: quantity("40"),
+				PrometheusRule: &susqlv2.BudgetPrometheusRule{}}},
+		}}
+		ruleKey := client.ObjectKey{Namespace: "shop", Name: "susql-budget-shop"}
+		ruleExists := func(k8sClient client.Client) bool {
+			rule := &unstructured.Unstructured{}
+			rule.SetGroupVersionKind(prometheusRuleGVK)
+			err := k8sClient.Get(ctx, ruleKey, rule)
+			Expect(client.IgnoreNotFound(err)).To(Succeed())
+			return err == nil
+		}
+
+		// The rule of the label group is deleted once its budget has no rule
+		reconciler := &LabelGroupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme, Logger: logr.Discard()}
+		Expect(reconciler.reconcileBudgetRule(ctx, labelGroup)).To(Succeed())
+		Expect(ruleExists(reconciler.Client)).To(BeTrue())
+
+		labelGroup.Spec.Budget = nil
+		Expect(reconciler.reconcileBudgetRule(ctx, labelGroup)).To(Succeed())
+		Expect(ruleExists(reconciler.Client)).To(BeFalse())
+
+		// A rule of the same name that the label group doesn't own is kept
+		foreign := &unstructured.Unstructured{}
+		foreign.SetGroupVersionKind(prometheusRuleGVK)
+		foreign.SetNamespace(ruleKey.Namespace)
+		foreign.SetName(ruleKey.Name)
+		reconciler = &LabelGroupReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreign).Build(), Scheme: scheme, Logger: logr.Discard()}
+		Expect(reconciler.reconcileBudgetRule(ctx, labelGroup)).To(Succeed())
+		Expect(ruleExists(reconciler.Client)).To(BeTrue())
+
+		// Without the permission to read the rules there is nothing to delete, which is only checked once
+		gets := 0
+		reconciler = &LabelGroupReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+					gets++
+					return apierrors.NewForbidden(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "prometheusrules"}, ruleKey.Name, errors.New("forbidden"))
+				},
+			}).Build(),
+			Scheme: scheme,
+			Logger: logr.Discard(),
+		}
+		Expect(reconciler.reconcileBudgetRule(ctx, labelGroup)).To(Succeed())
+		Expect(reconciler.reconcileBudgetRule(ctx, labelGroup)).To(Succeed())
+		Expect(gets).To(Equal(1))
+	})
+})
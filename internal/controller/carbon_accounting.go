@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	carbonSeriesRetention   = 48 * time.Hour // Time the carbon intensity samples are kept, beyond the correction window
+	carbonRoundingTolerance = 1e-9           // Grams, the precision of the carbon quantities
+)
+
+// CarbonIntensitySeries is a time-indexed series of carbon intensities, each applying from its time until the time
+// of the next one
+type CarbonIntensitySeries struct {
+	mutex   sync.RWMutex
+	samples []CarbonIntensitySample // Sorted by time
+}
+
+// Add inserts samples in the series, replacing the samples of the same time, e.g., when a provider publishes the
+// finalized intensity of a period
+func (s *CarbonIntensitySeries) Add(now time.Time, samples ...CarbonIntensitySample) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, sample := range samples {
+		// Times are kept with the precision of the status timestamps
+		sample.Time = sample.Time.Truncate(time.Second)
+		index := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].Time.Before(sample.Time) })
+		if index < len(s.samples) && s.samples[index].Time.Equal(sample.Time) {
+			s.samples[index] = sample
+			continue
+		}
+		s.samples = append(s.samples, CarbonIntensitySample{})
+		copy(s.samples[index+1:], s.samples[index:])
+		s.samples[index] = sample
+	}
+
+	// Forget the old samples, keeping the one that still applies at the retention cutoff
+	cutoff := now.Add(-carbonSeriesRetention)
+	first := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Time.After(cutoff) })
+	if first > 1 {
+		s.samples = append(s.samples[:0], s.samples[first-1:]...)
+	}
+}
+
+// empty returns whether the series has no sample
+func (s *CarbonIntensitySeries) empty() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.samples) == 0
+}
+
+// Value returns the intensity of the sample starting at the given time
+func (s *CarbonIntensitySeries) Value(start time.Time) (float64, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	index := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].Time.Before(start) })
+	if index < len(s.samples) && s.samples[index].Time.Equal(start) {
+		return s.samples[index].GramsPerJoule, true
+	}
+
+	return 0.0, false
+}
+
+// intensitySegment is the part of an interval during which one carbon intensity applies
+type intensitySegment struct {
+	start         time.Time // Time of the sample of the intensity, zero for the fallback intensity
+	duration      time.Duration
+	gramsPerJoule float64
+}
+
+// segments splits the interval [from, to] in the periods of the samples of the series. The intensity before the
+// first sample is the one of the first sample, or the fallback intensity when the series is empty. An empty interval
+// is a single segment with the intensity that applies at its time.
+func (s *CarbonIntensitySeries) segments(from time.Time, to time.Time, fallback float64) []intensitySegment {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.samples) == 0 {
+		return []intensitySegment{{duration: to.Sub(from), gramsPerJoule: fallback}}
+	}
+
+	if !from.Before(to) {
+		from = to
+	}
+
+	// Index of the sample that applies at 'from'
+	index := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Time.After(from) }) - 1
+	if index < 0 {
+		index = 0
+	}
+
+	var segments []intensitySegment
+	for segmentFrom := from; ; index++ {
+		segmentTo := to
+		if index+1 < len(s.samples) && s.samples[index+1].Time.Before(to) {
+			segmentTo = s.samples[index+1].Time
+		}
+
+		segments = append(segments, intensitySegment{
+			start:         s.samples[index].Time,
+			duration:      segmentTo.Sub(segmentFrom),
+			gramsPerJoule: s.samples[index].GramsPerJoule,
+		})
+
+		if !segmentTo.Before(to) {
+			return segments
+		}
+		segmentFrom = segmentTo
+	}
+}
+
+// accountCarbon returns the carbon of the energy consumed in a zone during [from, to], spreading it evenly over the
+// interval and applying the intensity of each period of the series of the zone, scaled by factor. The energy and
+// carbon of the periods that start after ledgerStart are recorded in the ledger so that they can be corrected later.
+func accountCarbon(series *CarbonIntensitySeries, zone string, ledger []susqlv2.CarbonLedgerEntry, from time.Time, to time.Time,
+	energy float64, fallback float64, factor float64, ledgerStart time.Time) (float64, []susqlv2.CarbonLedgerEntry) {
+	segments := series.segments(from, to, fallback)
+	interval := to.Sub(from)
+
+	var carbon float64
+	for _, segment := range segments {
+		segmentEnergy := energy
+		if len(segments) > 1 && interval > 0 {
+			segmentEnergy = energy * float64(segment.duration) / float64(interval)
+		}
+		segmentCarbon := segmentEnergy * segment.gramsPerJoule * factor
+		carbon += segmentCarbon
+
+		if segment.start.IsZero() || segment.start.Before(ledgerStart) {
+			continue
+		}
+
+		ledger = addToLedger(ledger, zone, segment.start, segmentEnergy, segmentCarbon)
+	}
+
+	return carbon, ledger
+}
+
+func addToLedger(ledger []susqlv2.CarbonLedgerEntry, zone string, start time.Time, energy float64, carbon float64) []susqlv2.CarbonLedgerEntry {
+	for i := range ledger {
+		if ledger[i].Zone == zone && ledger[i].Start.Time.Equal(start) {
+			ledger[i].EnergyJoules = *energyQuantity(quantityToFloat(&ledger[i].EnergyJoules) + energy)
+			ledger[i].CarbonGrams = *carbonQuantity(quantityToFloat(&ledger[i].CarbonGrams) + carbon)
+			return ledger
+		}
+	}
+
+	return append(ledger, susqlv2.CarbonLedgerEntry{
+		Zone:         zone,
+		Start:        metav1.NewTime(start),
+		EnergyJoules: *energyQuantity(energy),
+		CarbonGrams:  *carbonQuantity(carbon),
+	})
+}
+
+// correctCarbon recomputes the carbon of the ledger periods whose intensity changed in the series of their zone,
+// scaled by factor, returning the carbon differences by zone, and drops the periods that started before ledgerStart,
+// whose intensity is final
+func correctCarbon(seriesOf func(zone string) *CarbonIntensitySeries, ledger []susqlv2.CarbonLedgerEntry,
+	factor float64, ledgerStart time.Time) (map[string]float64, []susqlv2.CarbonLedgerEntry) {
+	corrections := make(map[string]float64)
+	kept := ledger[:0]
+
+	for _, entry := range ledger {
+		if entry.Start.Time.Before(ledgerStart) {
+			continue
+		}
+
+		if gramsPerJoule, ok := seriesOf(entry.Zone).Value(entry.Start.Time); ok {
+			oldCarbon := quantityToFloat(&entry.CarbonGrams)
+			newCarbon := quantityToFloat(&entry.EnergyJoules) * gramsPerJoule * factor
+			// Ignore the differences due to the rounding of the quantities
+			if math.Abs(newCarbon-oldCarbon) > carbonRoundingTolerance {
+				corrections[entry.Zone] += newCarbon - oldCarbon
+				entry.CarbonGrams = *carbonQuantity(newCarbon)
+			}
+		}
+
+		kept = append(kept, entry)
+	}
+
+	if len(kept) == 0 {
+		return corrections, nil
+	}
+
+	return corrections, kept
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Carbon Accounting", func() {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	var series *CarbonIntensitySeries
+	seriesOf := func(zone string) *CarbonIntensitySeries { return series }
+
+	BeforeEach(func() {
+		series = &CarbonIntensitySeries{}
+		series.Add(start.Add(time.Hour),
+			CarbonIntensitySample{Time: start.Add(30 * time.Minute), GramsPerJoule: 3e-4},
+			CarbonIntensitySample{Time: start, GramsPerJoule: 1e-4})
+	})
+
+	It("should use the fallback intensity without samples", func() {
+		carbon, ledger := accountCarbon(&CarbonIntensitySeries{}, "DE", nil, start, start.Add(time.Minute), 100, 2e-4, 1, start)
+		Expect(carbon).To(BeNumerically("~", 0.02, 1e-12))
+		Expect(ledger).To(BeEmpty())
+	})
+
+	It("should apply the intensity of the period of the energy", func() {
+		carbon, ledger := accountCarbon(series, "DE", nil, start.Add(10*time.Minute), start.Add(20*time.Minute), 100, 2e-4, 1, start)
+		Expect(carbon).To(BeNumerically("~", 0.01, 1e-12))
+		Expect(ledger).To(HaveLen(1))
+		Expect(ledger[0].Start.Time).To(Equal(start))
+		Expect(quantityToFloat(&ledger[0].EnergyJoules)).To(BeNumerically("~", 100, 1e-3))
+	})
+
+	It("should split the energy of intervals spanning several periods", func() {
+		carbon, ledger := accountCarbon(series, "DE", nil, start.Add(20*time.Minute), start.Add(40*time.Minute), 100, 2e-4, 1, start)
+		Expect(carbon).To(BeNumerically("~", 50*1e-4+50*3e-4, 1e-12))
+		Expect(ledger).To(HaveLen(2))
+
+		// Energy before the first sample uses the intensity of the first sample
+		carbon, _ = accountCarbon(series, "DE", nil, start.Add(-time.Hour), start.Add(-time.Minute), 100, 2e-4, 1, start)
+		Expect(carbon).To(BeNumerically("~", 0.01, 1e-12))
+	})
+
+	It("should correct the carbon of finalized periods", func() {
+		_, ledger := accountCarbon(series, "DE", nil, start.Add(10*time.Minute), start.Add(20*time.Minute), 100, 2e-4, 1, start)
+
+		corrections, ledger := correctCarbon(seriesOf, ledger, 1, start)
+		Expect(corrections).To(BeEmpty())
+
+		series.Add(start.Add(time.Hour), CarbonIntensitySample{Time: start, GramsPerJoule: 1.5e-4})
+		corrections, ledger = correctCarbon(seriesOf, ledger, 1, start)
+		Expect(corrections).To(HaveLen(1))
+		Expect(corrections["DE"]).To(BeNumerically("~", 0.005, 1e-9))
+		Expect(quantityToFloat(&ledger[0].CarbonGrams)).To(BeNumerically("~", 0.015, 1e-9))
+
+		// Periods out of the correction window are final
+		corrections, ledger = correctCarbon(seriesOf, ledger, 1, start.Add(time.Minute))
+		Expect(corrections).To(BeEmpty())
+		Expect(ledger).To(BeEmpty())
+	})
+
+	It("should forget the samples out of the retention", func() {
+		series.Add(start.Add(carbonSeriesRetention+time.Hour), CarbonIntensitySample{Time: start.Add(carbonSeriesRetention + time.Hour), GramsPerJoule: 5e-4})
+
+		_, ok := series.Value(start)
+		Expect(ok).To(BeFalse())
+		value, ok := series.Value(start.Add(30 * time.Minute))
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(3e-4))
+	})
+})
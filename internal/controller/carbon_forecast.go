@@ -0,0 +1,347 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// CarbonIntensityForecastProvider is implemented by the providers that publish the forecast carbon intensities of a
+// zone for the coming hours
+type CarbonIntensityForecastProvider interface {
+	// Forecast carbon intensities of a zone from now on, sorted by time
+	CarbonIntensityForecast(ctx context.Context, zone string) ([]CarbonIntensitySample, error)
+}
+
+const (
+	carbonForecastHorizon = 48 * time.Hour // Forecast intensities kept after now
+	carbonWindowPath      = "/carbon/best-window"
+)
+
+// Horizons of the exported forecast carbon intensities
+var carbonForecastHorizons = []int{1, 3, 6, 12, 24}
+
+// CarbonWindow is a period of the forecast over which the average carbon intensity is the lowest
+type CarbonWindow struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	GramsPerJoule float64   `json:"gramsPerJoule"` // Average forecast carbon intensity of the period
+}
+
+func (c *CarbonAwareSDKProvider) CarbonIntensityForecast(ctx context.Context, zone string) ([]CarbonIntensitySample, error) {
+	if c.forecastUrl == "" {
+		return nil, nil
+	}
+	queryUrl := fmt.Sprintf(c.forecastUrl, zone)
+
+	responseData, err := getCarbonAPI(ctx, c.client, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The current forecasts of /emissions/forecasts/current hold one forecast per requested location
+	return parseCarbonIntensityHistory(responseData, "0.forecastData", "timestamp", time.RFC3339, "value", c.conv2J,
+		time.Time{}, time.Now().Add(carbonForecastHorizon)), nil
+}
+
+func (e *ElectricityMapsProvider) CarbonIntensityForecast(ctx context.Context, zone string) ([]CarbonIntensitySample, error) {
+	headers := make(http.Header)
+	if e.credentials.secretName != "" {
+		token, err := e.credentials.get(ctx, "token")
+		if err != nil {
+			return nil, err
+		}
+		headers.Set("auth-token", token)
+	}
+
+	queryUrl := fmt.Sprintf("%s/v3/carbon-intensity/forecast?zone=%s", e.url, url.QueryEscape(zone))
+
+	responseData, err := getCarbonAPI(ctx, e.client, queryUrl, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCarbonIntensityHistory(responseData, "forecast", "datetime", time.RFC3339, "carbonIntensity", gramsPerKWhToGramsPerJoule,
+		time.Time{}, time.Now().Add(carbonForecastHorizon)), nil
+}
+
+func (w *WattTimeProvider) CarbonIntensityForecast(ctx context.Context, zone string) ([]CarbonIntensitySample, error) {
+	queryUrl := fmt.Sprintf("%s/v3/forecast?region=%s&signal_type=co2_moer&horizon_hours=%d", w.url, url.QueryEscape(zone),
+		int(carbonForecastHorizon.Hours()))
+
+	responseData, err := w.get(ctx, queryUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCarbonIntensityHistory(responseData, "data", "point_time", time.RFC3339, "value", poundsPerMWhToGramsPerJoule,
+		time.Time{}, time.Now().Add(carbonForecastHorizon)), nil
+}
+
+func (u *UKGridProvider) CarbonIntensityForecast(ctx context.Context, zone string) ([]CarbonIntensitySample, error) {
+	from := time.Now().UTC().Format(ukGridTimeLayout)
+
+	queryUrl := fmt.Sprintf("%s/intensity/%s/fw48h", u.url, from)
+	intervalsPath := "data"
+	if zone != "" && zone != "GB" {
+		if _, err := strconv.Atoi(zone); err != nil {
+			return nil, fmt.Errorf("invalid UK National Grid zone '%s', expected 'GB' or a region id", zone)
+		}
+		queryUrl = fmt.Sprintf("%s/regional/intensity/%s/fw48h/regionid/%s", u.url, from, zone)
+		intervalsPath = "data.data"
+	}
+
+	responseData, err := getCarbonAPI(ctx, u.client, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCarbonIntensityHistory(responseData, intervalsPath, "from", ukGridTimeLayout, "intensity.forecast", gramsPerKWhToGramsPerJoule,
+		time.Time{}, time.Now().Add(carbonForecastHorizon)), nil
+}
+
+// lastTime returns the time of the last sample of the series, zero when it is empty
+func (s *CarbonIntensitySeries) lastTime() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.samples) == 0 {
+		return time.Time{}
+	}
+
+	return s.samples[len(s.samples)-1].Time
+}
+
+// sampleTimes returns the times of the samples of the series in (from, to]
+func (s *CarbonIntensitySeries) sampleTimes(from time.Time, to time.Time) []time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var times []time.Time
+	for index := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].Time.After(from) }); index < len(s.samples); index++ {
+		if s.samples[index].Time.After(to) {
+			break
+		}
+		times = append(times, s.samples[index].Time)
+	}
+
+	return times
+}
+
+// averageIntensity returns the time-weighted average carbon intensity of the series over [from, to]
+func (s *CarbonIntensitySeries) averageIntensity(from time.Time, to time.Time) float64 {
+	segments := s.segments(from, to, 0)
+	if !from.Before(to) {
+		return segments[0].gramsPerJoule
+	}
+
+	var weighted float64
+	for _, segment := range segments {
+		weighted += segment.gramsPerJoule * segment.duration.Seconds()
+	}
+
+	return weighted / to.Sub(from).Seconds()
+}
+
+// bestCarbonWindow returns the period of a duration starting between from and latestStart over which the average
+// forecast carbon intensity is the lowest, the earliest one among equals. The periods start at from or at the times
+// of the forecast samples, and the last forecast intensity applies after the end of the forecast.
+func bestCarbonWindow(forecast *CarbonIntensitySeries, from time.Time, latestStart time.Time, duration time.Duration) (CarbonWindow, bool) {
+	if forecast == nil || forecast.empty() {
+		return CarbonWindow{}, false
+	}
+	if latestStart.Before(from) {
+		latestStart = from
+	}
+
+	best := CarbonWindow{Start: from, End: from.Add(duration), GramsPerJoule: forecast.averageIntensity(from, from.Add(duration))}
+	for _, start := range forecast.sampleTimes(from, latestStart) {
+		if average := forecast.averageIntensity(start, start.Add(duration)); average < best.GramsPerJoule {
+			best = CarbonWindow{Start: start, End: start.Add(duration), GramsPerJoule: average}
+		}
+	}
+
+	return best, true
+}
+
+// refreshForecast ingests the forecast of a zone when the provider publishes forecasts, keeping the previous one when
+// the query fails until it ends
+func (r *LabelGroupReconciler) refreshForecast(ctx context.Context, zone string, now time.Time) {
+	forecastProvider, ok := r.CarbonIntensityProvider.(CarbonIntensityForecastProvider)
+	if !ok {
+		return
+	}
+
+	samples, err := forecastProvider.CarbonIntensityForecast(ctx, zone)
+	if err != nil {
+		r.Logger.V(0).Error(err, fmt.Sprintf("[refreshForecast] Unable to query the carbon intensity forecast of zone '%s'.", zone))
+		r.expireForecast(zone, now)
+		return
+	}
+	if len(samples) == 0 {
+		r.expireForecast(zone, now)
+		return
+	}
+
+	series := &CarbonIntensitySeries{}
+	series.Add(now, samples...)
+	if series.lastTime().Before(now) {
+		r.Logger.V(1).Info(fmt.Sprintf("[refreshForecast] Ignored the forecast of zone '%s', which ended at %s.", zone, series.lastTime().UTC().Format(time.RFC3339)))
+		r.expireForecast(zone, now)
+		return
+	}
+
+	r.carbonMutex.Lock()
+	if r.carbonForecasts == nil {
+		r.carbonForecasts = make(map[string]*CarbonIntensitySeries)
+	}
+	r.carbonForecasts[zone] = series
+	r.carbonMutex.Unlock()
+
+	r.Logger.V(5).Info(fmt.Sprintf("[refreshForecast] Obtained %d forecast carbon intensities for zone '%s'.", len(samples), zone)) // trace
+	recordCarbonIntensityForecast(zone, series, now)
+}
+
+// carbonForecastOf returns the last forecast ingested for a zone, nil when there is none or when it ended before now
+func (r *LabelGroupReconciler) carbonForecastOf(zone string, now time.Time) *CarbonIntensitySeries {
+	r.carbonMutex.RLock()
+	defer r.carbonMutex.RUnlock()
+
+	forecast := r.carbonForecasts[zone]
+	if forecast == nil || forecast.lastTime().Before(now) {
+		return nil
+	}
+
+	return forecast
+}
+
+// expireForecast drops the forecast of a zone once it ended, and stops exporting its forecast carbon intensities
+func (r *LabelGroupReconciler) expireForecast(zone string, now time.Time) {
+	r.carbonMutex.Lock()
+	forecast, found := r.carbonForecasts[zone]
+	expired := found && forecast.lastTime().Before(now)
+	if expired {
+		delete(r.carbonForecasts, zone)
+	}
+	r.carbonMutex.Unlock()
+
+	if expired {
+		r.Logger.V(1).Info(fmt.Sprintf("[expireForecast] Dropped the forecast of zone '%s', which ended at %s.", zone, forecast.lastTime().UTC().Format(time.RFC3339)))
+		deleteCarbonIntensityForecast(zone)
+	}
+}
+
+// Stop exporting the forecast carbon intensities of a zone
+func deleteCarbonIntensityForecast(zone string) {
+	if susqlMetrics == nil {
+		return
+	}
+
+	susqlMetrics.carbonIntensityForecast.DeletePartialMatch(map[string]string{"zone": zone})
+}
+
+// Export the forecast carbon intensities of a zone at the horizons covered by its forecast
+func recordCarbonIntensityForecast(zone string, forecast *CarbonIntensitySeries, now time.Time) {
+	if susqlMetrics == nil {
+		return
+	}
+
+	deleteCarbonIntensityForecast(zone)
+	for _, hours := range carbonForecastHorizons {
+		horizonTime := now.Add(time.Duration(hours) * time.Hour)
+		if horizonTime.After(forecast.lastTime()) {
+			break
+		}
+		susqlMetrics.carbonIntensityForecast.WithLabelValues(zone, fmt.Sprintf("%dh", hours)).Set(forecast.averageIntensity(horizonTime, horizonTime))
+	}
+}
+
+// BestCarbonWindow returns the period of a duration within the coming period of a zone over which the average
+// forecast carbon intensity is the lowest, e.g., to schedule a job of that duration
+func (r *LabelGroupReconciler) BestCarbonWindow(zone string, within time.Duration, duration time.Duration, now time.Time) (CarbonWindow, error) {
+	if duration <= 0 || duration > within {
+		return CarbonWindow{}, fmt.Errorf("the duration %s must be positive and no longer than %s", duration, within)
+	}
+
+	window, ok := bestCarbonWindow(r.carbonForecastOf(zone, now), now, now.Add(within-duration), duration)
+	if !ok {
+		return CarbonWindow{}, fmt.Errorf("no carbon intensity forecast for zone '%s'", zone)
+	}
+
+	return window, nil
+}
+
+// serveBestCarbonWindow answers the best window of the 'zone' (default carbon location by default) query parameter for
+// a job of 'duration' (default 1h) within the next 'within' (default 24h, at most the forecast horizon), both
+// Prometheus durations. It is served without authentication by the SusQL metrics server, like its metrics.
+func (r *LabelGroupReconciler) serveBestCarbonWindow(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	zone := query.Get("zone")
+	if zone == "" {
+		zone = r.CarbonLocation
+	}
+
+	parseDuration := func(name string, defaultDuration time.Duration) (time.Duration, error) {
+		value := query.Get(name)
+		if value == "" {
+			return defaultDuration, nil
+		}
+		duration, err := model.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s '%s': %w", name, value, err)
+		}
+		return time.Duration(duration), nil
+	}
+
+	within, err := parseDuration("within", 24*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// No forecast is kept beyond the horizon
+	within = min(within, carbonForecastHorizon)
+	duration, err := parseDuration("duration", time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if duration <= 0 || duration > within {
+		http.Error(w, fmt.Sprintf("the duration %s must be positive and no longer than %s", duration, within), http.StatusBadRequest)
+		return
+	}
+
+	window, err := r.BestCarbonWindow(zone, within, duration, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Zone string `json:"zone"`
+		CarbonWindow
+	}{Zone: zone, CarbonWindow: window})
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeCarbonIntensityForecastProvider also returns a fixed forecast
+type fakeCarbonIntensityForecastProvider struct {
+	fakeCarbonIntensityProvider
+	forecast []CarbonIntensitySample
+}
+
+func (p *fakeCarbonIntensityForecastProvider) CarbonIntensityForecast(_ context.Context, _ string) ([]CarbonIntensitySample, error) {
+	return p.forecast, nil
+}
+
+var _ = Describe("Carbon Intensity Forecast", func() {
+	var requests []*http.Request
+	var server *httptest.Server
+	var responses map[string]string
+
+	BeforeEach(func() {
+		requests = nil
+		responses = make(map[string]string)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			for path, response := range responses {
+				if strings.HasPrefix(req.URL.Path, path) {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(response))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should read the current forecasts of the Carbon Aware SDK", func() {
+		responses["/emissions/forecasts/current"] = `[{"location":"eastus","forecastData":[
+			{"location":"eastus","timestamp":"2026-01-01T10:00:00Z","duration":5,"value":360},
+			{"location":"eastus","timestamp":"2026-01-01T10:05:00Z","duration":5,"value":180}
+		]}]`
+
+		provider, err := NewCarbonIntensityProvider(CarbonMethodCASDK, CarbonIntensityProviderConfig{
+			IntensityUrl: server.URL + "/emissions/bylocation?location=%s",
+			ForecastUrl:  server.URL + "/emissions/forecasts/current?location=%s",
+			Filter:       "rating",
+			Conv2J:       gramsPerKWhToGramsPerJoule,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		samples, err := provider.(CarbonIntensityForecastProvider).CarbonIntensityForecast(context.Background(), "eastus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(2))
+		Expect(samples[0].GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+		Expect(samples[1].Time).To(Equal(time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC)))
+		Expect(requests[0].URL.Query().Get("location")).To(Equal("eastus"))
+
+		// No forecast without a forecast URL
+		provider, err = NewCarbonIntensityProvider(CarbonMethodCASDK, CarbonIntensityProviderConfig{IntensityUrl: server.URL + "/emissions/bylocation?location=%s"})
+		Expect(err).NotTo(HaveOccurred())
+		samples, err = provider.(CarbonIntensityForecastProvider).CarbonIntensityForecast(context.Background(), "eastus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(BeEmpty())
+	})
+
+	It("should read the forecasts of Electricity Maps and WattTime", func() {
+		responses["/v3/carbon-intensity/forecast"] = `{"zone":"DE","forecast":[{"carbonIntensity":720,"datetime":"2026-01-01T10:00:00.000Z"}]}`
+		responses["/login"] = `{"token":"wt-token"}`
+		responses["/v3/forecast"] = `{"data":[{"point_time":"2026-01-01T10:00:00+00:00","value":1000},{"point_time":"2026-01-01T10:05:00+00:00","value":500}]}`
+
+		samples, err := (&ElectricityMapsProvider{client: server.Client(), url: server.URL, credentials: &carbonAPICredentials{}}).
+			CarbonIntensityForecast(context.Background(), "DE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(1))
+		Expect(samples[0].GramsPerJoule).To(BeNumerically("~", 2e-4, 1e-12))
+		Expect(requests[0].URL.Query().Get("zone")).To(Equal("DE"))
+
+		wattTime := &WattTimeProvider{client: server.Client(), url: server.URL, token: "wt-token", tokenExpiry: time.Now().Add(time.Hour)}
+		samples, err = wattTime.CarbonIntensityForecast(context.Background(), "CAISO_NORTH")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(2))
+		Expect(samples[1].GramsPerJoule).To(BeNumerically("~", 500*poundsPerMWhToGramsPerJoule, 1e-12))
+		Expect(requests[1].URL.Query().Get("horizon_hours")).To(Equal("48"))
+	})
+
+	It("should read the UK National Grid forecast", func() {
+		responses["/regional/"] = `{"data":{"regionid":13,"data":[{"from":"2026-08-17T15:00Z","intensity":{"forecast":360}}]}}`
+		responses["/intensity/"] = `{"data":[
+			{"from":"2026-08-17T15:00Z","intensity":{"forecast":180,"actual":null}},
+			{"from":"2026-08-17T15:30Z","intensity":{"forecast":360,"actual":null}}
+		]}`
+
+		provider := &UKGridProvider{client: server.Client(), url: server.URL}
+		samples, err := provider.CarbonIntensityForecast(context.Background(), "GB")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(2))
+		Expect(samples[0].GramsPerJoule).To(BeNumerically("~", 5e-5, 1e-12))
+		Expect(samples[1].Time).To(Equal(time.Date(2026, 8, 17, 15, 30, 0, 0, time.UTC)))
+
+		samples, err = provider.CarbonIntensityForecast(context.Background(), "13")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(1))
+		Expect(requests[0].URL.Path).To(HaveSuffix("/fw48h"))
+		Expect(requests[1].URL.Path).To(HaveSuffix("/fw48h/regionid/13"))
+
+		_, err = provider.CarbonIntensityForecast(context.Background(), "London")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should ingest the forecasts with the carbon intensity and answer the best window", func() {
+		now := time.Now().Truncate(time.Second)
+		provider := &fakeCarbonIntensityForecastProvider{
+			fakeCarbonIntensityProvider: fakeCarbonIntensityProvider{intensities: map[string]float64{"JP-TK": 3e-4}},
+			forecast: []CarbonIntensitySample{
+				{Time: now, GramsPerJoule: 3e-4},
+				{Time: now.Add(2 * time.Hour), GramsPerJoule: 1e-4},
+				{Time: now.Add(4 * time.Hour), GramsPerJoule: 2e-4},
+			},
+		}
+		reconciler := &LabelGroupReconciler{
+			CarbonIntensityProvider: provider,
+			CarbonLocation:          "JP-TK",
+			CarbonQueryRate:         3600,
+			Logger:                  logr.Discard(),
+		}
+		Expect(reconciler.carbonForecastOf("JP-TK", now)).To(BeNil())
+
+		NewCarbonIntensityRefresher(reconciler).refresh(context.Background(), now)
+		Expect(reconciler.carbonForecastOf("JP-TK", now)).NotTo(BeNil())
+
+		window, err := reconciler.BestCarbonWindow("JP-TK", 12*time.Hour, time.Hour, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window.Start).To(Equal(now.Add(2 * time.Hour)))
+		Expect(window.GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+
+		// The window must end within the period
+		window, err = reconciler.BestCarbonWindow("JP-TK", 2*time.Hour, time.Hour, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(window.Start).To(Equal(now))
+
+		_, err = reconciler.BestCarbonWindow("JP-TK", time.Hour, 2*time.Hour, now)
+		Expect(err).To(HaveOccurred())
+		_, err = reconciler.BestCarbonWindow("DE", 12*time.Hour, time.Hour, now)
+		Expect(err).To(HaveOccurred())
+
+		response := httptest.NewRecorder()
+		reconciler.serveBestCarbonWindow(response, httptest.NewRequest(http.MethodGet, carbonWindowPath+"?within=12h&duration=90m", nil))
+		Expect(response.Code).To(Equal(http.StatusOK))
+		var answer struct {
+			Zone  string    `json:"zone"`
+			Start time.Time `json:"start"`
+			End   time.Time `json:"end"`
+		}
+		Expect(json.Unmarshal(response.Body.Bytes(), &answer)).To(Succeed())
+		Expect(answer.Zone).To(Equal("JP-TK"))
+		Expect(answer.Start).To(BeTemporally("~", now.Add(2*time.Hour), time.Second))
+		Expect(answer.End.Sub(answer.Start)).To(Equal(90 * time.Minute))
+
+		response = httptest.NewRecorder()
+		reconciler.serveBestCarbonWindow(response, httptest.NewRequest(http.MethodGet, carbonWindowPath+"?duration=soon", nil))
+		Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+		// The period is capped at the forecast horizon
+		response = httptest.NewRecorder()
+		reconciler.serveBestCarbonWindow(response, httptest.NewRequest(http.MethodGet, carbonWindowPath+"?within=10000h&duration=49h", nil))
+		Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+		response = httptest.NewRecorder()
+		reconciler.serveBestCarbonWindow(response, httptest.NewRequest(http.MethodGet, carbonWindowPath+"?zone=DE", nil))
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should drop the forecasts that ended", func() {
+		previousMetrics := susqlMetrics
+		susqlMetrics = newSusqlMetrics(nil)
+		defer func() { susqlMetrics = previousMetrics }()
+		gauges := func() int {
+			registry := prometheus.NewRegistry()
+			Expect(registry.Register(susqlMetrics.carbonIntensityForecast)).To(Succeed())
+			families, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			if len(families) == 0 {
+				return 0
+			}
+			return len(families[0].GetMetric())
+		}
+
+		now := time.Now().Truncate(time.Second)
+		provider := &fakeCarbonIntensityForecastProvider{
+			fakeCarbonIntensityProvider: fakeCarbonIntensityProvider{intensities: map[string]float64{"JP-TK": 3e-4}},
+			forecast: []CarbonIntensitySample{
+				{Time: now, GramsPerJoule: 3e-4},
+				{Time: now.Add(2 * time.Hour), GramsPerJoule: 1e-4},
+			},
+		}
+		reconciler := &LabelGroupReconciler{
+			CarbonIntensityProvider: provider,
+			CarbonLocation:          "JP-TK",
+			Logger:                  logr.Discard(),
+		}
+
+		reconciler.refreshForecast(context.Background(), "JP-TK", now)
+		Expect(reconciler.carbonForecastOf("JP-TK", now)).NotTo(BeNil())
+		Expect(gauges()).To(Equal(1))
+
+		// The forecast is kept until it ends
+		Expect(reconciler.carbonForecastOf("JP-TK", now.Add(3*time.Hour))).To(BeNil())
+		_, err := reconciler.BestCarbonWindow("JP-TK", 12*time.Hour, time.Hour, now.Add(3*time.Hour))
+		Expect(err).To(HaveOccurred())
+
+		// A forecast that ended is not ingested, and the previous one is dropped with its intensities
+		reconciler.refreshForecast(context.Background(), "JP-TK", now.Add(time.Hour))
+		Expect(reconciler.carbonForecastOf("JP-TK", now.Add(time.Hour))).NotTo(BeNil())
+		reconciler.refreshForecast(context.Background(), "JP-TK", now.Add(3*time.Hour))
+		Expect(reconciler.carbonForecasts).NotTo(HaveKey("JP-TK"))
+		Expect(gauges()).To(BeZero())
+	})
+})
@@ -0,0 +1,202 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Label, scheduling gate and annotations of the pods deferred by the carbon-aware scheduling gate
+const (
+	CarbonAwareLabel               = "susql.ibm.com/carbon-aware"                   // "true" on the pods to defer
+	CarbonAwareSchedulingGate      = "susql.ibm.com/carbon-aware"                   // Scheduling gate holding the deferred pods
+	CarbonAwareThresholdAnnotation = "susql.ibm.com/carbon-intensity-threshold"     // Carbon intensity in grams CO2 per Joule under which the pod is released
+	CarbonAwareDeadlineAnnotation  = "susql.ibm.com/carbon-aware-deadline"          // RFC 3339 time at which the pod is released anyway
+	CarbonAwareDurationAnnotation  = "susql.ibm.com/carbon-aware-expected-duration" // Prometheus duration of the pod, over which the forecast intensity is averaged
+)
+
+const (
+	defaultCarbonAwareDuration = time.Hour   // Expected duration of the deferred pods that do not set one
+	carbonGateMinCheckInterval = time.Minute // Minimum time between two checks of a deferred pod
+	reasonCarbonAwareReleased  = "CarbonAwareReleased"
+)
+
+// CarbonAwareGateReconciler removes the carbon-aware scheduling gate of the deferred pods once the carbon intensity of
+// the default carbon zone is under their threshold, at the start of the window of lowest forecast carbon intensity
+// before their deadline when the provider publishes forecasts, or at their deadline. The pods are deferred by their
+// carbon-aware label and created with the gate, e.g., by the pod admission webhook. Their energy is accounted by the
+// label groups selecting them like the one of the other pods.
+type CarbonAwareGateReconciler struct {
+	*LabelGroupReconciler
+	Threshold float64       // Carbon intensity under which the pods that do not set a threshold are released, none when 0
+	MaxDelay  time.Duration // Time after their creation at which the pods that do not set a deadline are released
+}
+
+// carbonGatePolicy is when a deferred pod is released
+type carbonGatePolicy struct {
+	threshold float64 // Carbon intensity under which the pod is released, none when 0
+	deadline  time.Time
+	duration  time.Duration // Expected duration of the pod
+}
+
+// carbonGateDecision is whether a deferred pod is released now, or when to check it again
+type carbonGateDecision struct {
+	release    bool
+	reason     string
+	checkAfter time.Duration
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch
+
+// Whether a pod is deferred by the carbon-aware scheduling gate
+func carbonAwareGated(pod *corev1.Pod) bool {
+	return pod.Labels[CarbonAwareLabel] == "true" && slices.ContainsFunc(pod.Spec.SchedulingGates, func(gate corev1.PodSchedulingGate) bool {
+		return gate.Name == CarbonAwareSchedulingGate
+	})
+}
+
+// Policy of a deferred pod from its annotations and the defaults of the reconciler
+func (r *CarbonAwareGateReconciler) carbonGatePolicy(pod *corev1.Pod) (carbonGatePolicy, error) {
+	policy := carbonGatePolicy{
+		threshold: r.Threshold,
+		deadline:  pod.CreationTimestamp.Add(r.MaxDelay),
+		duration:  defaultCarbonAwareDuration,
+	}
+
+	if value, found := pod.Annotations[CarbonAwareThresholdAnnotation]; found {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold < 0 {
+			return policy, fmt.Errorf("invalid carbon intensity threshold '%s'", value)
+		}
+		policy.threshold = threshold
+	}
+
+	if value, found := pod.Annotations[CarbonAwareDeadlineAnnotation]; found {
+		deadline, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid carbon-aware deadline '%s': %w", value, err)
+		}
+		policy.deadline = deadline
+	}
+
+	if value, found := pod.Annotations[CarbonAwareDurationAnnotation]; found {
+		duration, err := model.ParseDuration(value)
+		if err != nil {
+			return policy, fmt.Errorf("invalid carbon-aware expected duration '%s': %w", value, err)
+		}
+		policy.duration = time.Duration(duration)
+	}
+
+	return policy, nil
+}
+
+// decideCarbonGate decides whether a deferred pod is released from the current carbon intensity, when it is known,
+// and the forecast, when the provider publishes one
+func decideCarbonGate(policy carbonGatePolicy, intensity float64, intensityKnown bool, forecast *CarbonIntensitySeries,
+	now time.Time, checkInterval time.Duration) carbonGateDecision {
+	if !now.Before(policy.deadline) {
+		return carbonGateDecision{release: true, reason: "the deadline was reached"}
+	}
+
+	if intensityKnown && policy.threshold > 0 && intensity <= policy.threshold {
+		return carbonGateDecision{release: true, reason: fmt.Sprintf("the carbon intensity of %.10f g/J is under the threshold of %.10f g/J", intensity, policy.threshold)}
+	}
+
+	checkAfter := min(checkInterval, policy.deadline.Sub(now))
+
+	// Run in the window of lowest forecast intensity before the deadline, rather than at the deadline when the
+	// intensity does not fall under the threshold
+	if window, ok := bestCarbonWindow(forecast, now, policy.deadline, policy.duration); ok {
+		if !window.Start.After(now) {
+			return carbonGateDecision{release: true, reason: fmt.Sprintf("the forecast carbon intensity of %.10f g/J until %s is the lowest before the deadline",
+				window.GramsPerJoule, window.End.UTC().Format(time.RFC3339))}
+		}
+		checkAfter = min(checkAfter, window.Start.Sub(now))
+	}
+
+	return carbonGateDecision{checkAfter: checkAfter}
+}
+
+// Reconcile releases a deferred pod when its carbon-aware policy allows it, and checks it again later otherwise.
+func (r *CarbonAwareGateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !carbonAwareGated(pod) {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	decision := carbonGateDecision{release: true}
+
+	policy, err := r.carbonGatePolicy(pod)
+	if err != nil {
+		// Never hold a pod that can't be released
+		r.Logger.V(0).Error(err, fmt.Sprintf("[CarbonAwareGate] Invalid carbon-aware policy of pod '%s' in namespace '%s'.", pod.Name, pod.Namespace))
+		decision.reason = fmt.Sprintf("its carbon-aware policy is invalid: %v", err)
+	} else {
+		state := r.currentCarbonIntensity()
+		intensityKnown := r.CarbonIntensityProvider == nil || (!state.updated.IsZero() && !r.carbonIntensityStale(state, now))
+
+		forecast := r.carbonForecastOf(r.CarbonLocation, now)
+		checkInterval := max(time.Duration(r.CarbonQueryRate)*time.Second, carbonGateMinCheckInterval)
+		decision = decideCarbonGate(policy, state.gramsPerJoule, intensityKnown, forecast, now, checkInterval)
+	}
+
+	if !decision.release {
+		r.Logger.V(5).Info(fmt.Sprintf("[CarbonAwareGate] Holding pod '%s' in namespace '%s' for %s.", pod.Name, pod.Namespace, decision.checkAfter)) // trace
+		return ctrl.Result{RequeueAfter: decision.checkAfter}, nil
+	}
+
+	base := pod.DeepCopy()
+	pod.Spec.SchedulingGates = slices.DeleteFunc(pod.Spec.SchedulingGates, func(gate corev1.PodSchedulingGate) bool {
+		return gate.Name == CarbonAwareSchedulingGate
+	})
+	if err := r.Patch(ctx, pod, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	r.Logger.V(1).Info(fmt.Sprintf("[CarbonAwareGate] Released pod '%s' in namespace '%s': %s.", pod.Name, pod.Namespace, decision.reason))
+	if r.Recorder != nil {
+		r.Recorder.Event(pod, corev1.EventTypeNormal, reasonCarbonAwareReleased, fmt.Sprintf("Released the carbon-aware scheduling gate: %s", decision.reason))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller of the deferred pods with the Manager.
+func (r *CarbonAwareGateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			pod, ok := object.(*corev1.Pod)
+			return ok && carbonAwareGated(pod)
+		}))).
+		Named("susql-carbon-aware-gate").
+		Complete(r)
+}
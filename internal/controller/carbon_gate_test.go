@@ -0,0 +1,152 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Carbon Aware Gate", func() {
+	now := time.Date(2026, 8, 17, 15, 0, 0, 0, time.UTC)
+
+	// Forecast of 3, 1 and 2 g/J from now on, by steps of 30 minutes
+	newForecast := func() *CarbonIntensitySeries {
+		forecast := &CarbonIntensitySeries{}
+		forecast.Add(now,
+			CarbonIntensitySample{Time: now, GramsPerJoule: 3},
+			CarbonIntensitySample{Time: now.Add(30 * time.Minute), GramsPerJoule: 1},
+			CarbonIntensitySample{Time: now.Add(time.Hour), GramsPerJoule: 2},
+		)
+		return forecast
+	}
+
+	newGatedPod := func(annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "train", Namespace: "ml", Labels: map[string]string{CarbonAwareLabel: "true"}, Annotations: annotations,
+				CreationTimestamp: metav1.NewTime(now),
+			},
+			Spec: corev1.PodSpec{SchedulingGates: []corev1.PodSchedulingGate{{Name: CarbonAwareSchedulingGate}, {Name: "example.com/other"}}},
+		}
+	}
+
+	It("should find the window of lowest average forecast intensity", func() {
+		window, ok := bestCarbonWindow(newForecast(), now, now.Add(2*time.Hour), 30*time.Minute)
+		Expect(ok).To(BeTrue())
+		Expect(window.Start).To(Equal(now.Add(30 * time.Minute)))
+		Expect(window.End).To(Equal(now.Add(time.Hour)))
+		Expect(window.GramsPerJoule).To(BeNumerically("~", 1, 1e-12))
+
+		// The averages over one hour are 2, 1.5 and 2
+		window, ok = bestCarbonWindow(newForecast(), now, now.Add(2*time.Hour), time.Hour)
+		Expect(ok).To(BeTrue())
+		Expect(window.Start).To(Equal(now.Add(30 * time.Minute)))
+		Expect(window.GramsPerJoule).To(BeNumerically("~", 1.5, 1e-12))
+
+		// The windows can't start after the latest start
+		window, ok = bestCarbonWindow(newForecast(), now, now.Add(10*time.Minute), 30*time.Minute)
+		Expect(ok).To(BeTrue())
+		Expect(window.Start).To(Equal(now))
+		Expect(window.GramsPerJoule).To(BeNumerically("~", 3, 1e-12))
+
+		_, ok = bestCarbonWindow(nil, now, now.Add(time.Hour), time.Hour)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should release the pods at their deadline, under their threshold or in the best window", func() {
+		policy := carbonGatePolicy{threshold: 0.5, deadline: now.Add(2 * time.Hour), duration: 30 * time.Minute}
+
+		decision := decideCarbonGate(policy, 0.4, true, nil, now, 5*time.Minute)
+		Expect(decision.release).To(BeTrue())
+
+		// An unknown intensity never releases the pod before its deadline
+		decision = decideCarbonGate(policy, 0.4, false, nil, now, 5*time.Minute)
+		Expect(decision.release).To(BeFalse())
+		Expect(decision.checkAfter).To(Equal(5 * time.Minute))
+
+		decision = decideCarbonGate(policy, 0.4, false, nil, now.Add(2*time.Hour), 5*time.Minute)
+		Expect(decision.release).To(BeTrue())
+		Expect(decision.reason).To(ContainSubstring("deadline"))
+
+		// The best window starts in 30 minutes
+		decision = decideCarbonGate(policy, 3, true, newForecast(), now, time.Hour)
+		Expect(decision.release).To(BeFalse())
+		Expect(decision.checkAfter).To(Equal(30 * time.Minute))
+
+		decision = decideCarbonGate(policy, 1, true, newForecast(), now.Add(30*time.Minute), time.Hour)
+		Expect(decision.release).To(BeTrue())
+		Expect(decision.reason).To(ContainSubstring("forecast"))
+	})
+
+	It("should read the policy of the pods from their annotations", func() {
+		reconciler := &CarbonAwareGateReconciler{Threshold: 0.1, MaxDelay: 12 * time.Hour}
+
+		policy, err := reconciler.carbonGatePolicy(newGatedPod(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(carbonGatePolicy{threshold: 0.1, deadline: now.Add(12 * time.Hour), duration: defaultCarbonAwareDuration}))
+
+		policy, err = reconciler.carbonGatePolicy(newGatedPod(map[string]string{
+			CarbonAwareThresholdAnnotation: "0.00005",
+			CarbonAwareDeadlineAnnotation:  "2026-08-17T20:00:00Z",
+			CarbonAwareDurationAnnotation:  "90m",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy.threshold).To(Equal(0.00005))
+		Expect(policy.deadline).To(Equal(now.Add(5 * time.Hour)))
+		Expect(policy.duration).To(Equal(90 * time.Minute))
+
+		_, err = reconciler.carbonGatePolicy(newGatedPod(map[string]string{CarbonAwareDeadlineAnnotation: "tomorrow"}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should remove the gate of the released pods only", func() {
+		pod := newGatedPod(map[string]string{CarbonAwareThresholdAnnotation: "0.0002"})
+		pod.CreationTimestamp = metav1.NewTime(time.Now())
+		k8sClient := fake.NewClientBuilder().WithObjects(pod).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &CarbonAwareGateReconciler{
+			LabelGroupReconciler: &LabelGroupReconciler{Client: k8sClient, Logger: logr.Discard(), Recorder: recorder, CarbonQueryRate: 300, CarbonIntensity: 0.0003},
+			MaxDelay:             12 * time.Hour,
+		}
+		request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ml", Name: "train"}}
+
+		result, err := reconciler.Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(5 * time.Minute))
+
+		reconciler.CarbonIntensity = 0.0001
+		result, err = reconciler.Reconcile(context.Background(), request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		released := &corev1.Pod{}
+		Expect(k8sClient.Get(context.Background(), request.NamespacedName, released)).To(Succeed())
+		Expect(released.Spec.SchedulingGates).To(Equal([]corev1.PodSchedulingGate{{Name: "example.com/other"}}))
+		Expect(recorder.Events).To(Receive(ContainSubstring(reasonCarbonAwareReleased)))
+	})
+})
@@ -0,0 +1,518 @@
+/*
+Copyright 2023, 2024, 2025, 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/config"
+	"github.com/tidwall/gjson"
+)
+
+// Carbon intensity calculation methods
+const (
+	CarbonMethodStatic          = "static"          // Static carbon intensity
+	CarbonMethodSimpleDynamic   = "simpledynamic"   // Generic JSON web API
+	CarbonMethodCASDK           = "casdk"           // Green Software Foundation Carbon Aware SDK
+	CarbonMethodElectricityMaps = "electricitymaps" // Electricity Maps API
+	CarbonMethodWattTime        = "watttime"        // WattTime API (marginal operating emissions rate)
+	CarbonMethodUKGrid          = "ukgrid"          // UK National Grid carbon intensity API
+)
+
+const (
+	gramsPerKWhToGramsPerJoule    = 1.0 / 3.6e6              // 1 kWh = 3.6e6 J
+	poundsPerMWhToGramsPerJoule   = 453.59237 / 3.6e9        // 1 lb = 453.59237 g, 1 MWh = 3.6e9 J
+	carbonIntensityRequestTimeout = 30 * time.Second         // Timeout of the requests to the carbon intensity APIs
+	wattTimeTokenLifetime         = 25 * time.Minute         // WattTime tokens expire after 30 minutes
+	ukGridTimeLayout              = "2006-01-02T15:04Z07:00" // Time format of the UK National Grid API
+)
+
+// Public APIs of the native carbon intensity providers
+const (
+	defaultElectricityMapsUrl = "https://api.electricitymap.org"
+	defaultWattTimeUrl        = "https://api.watttime.org"
+	defaultUKGridUrl          = "https://api.carbonintensity.org.uk"
+)
+
+// CarbonIntensitySample is a carbon intensity value and the time it applies to
+type CarbonIntensitySample struct {
+	Time          time.Time
+	GramsPerJoule float64
+}
+
+// CarbonIntensityProvider reads the carbon intensity of the electricity of a grid zone from a web API
+type CarbonIntensityProvider interface {
+	// Most recent carbon intensity of a zone, whose identifiers are defined by the provider
+	CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error)
+}
+
+// CarbonIntensityHistoryProvider is implemented by the providers that publish the past carbon intensities of a
+// zone, whose finalized values replace the values read when they were the most recent ones
+type CarbonIntensityHistoryProvider interface {
+	// Carbon intensities of a zone between start and end, sorted by time
+	CarbonIntensityHistory(ctx context.Context, zone string, start time.Time, end time.Time) ([]CarbonIntensitySample, error)
+}
+
+// CarbonIntensityProviderConfig configures the provider of a carbon intensity calculation method
+type CarbonIntensityProviderConfig struct {
+	IntensityUrl string               // URL template ('%s' is the zone) of the simpledynamic and casdk methods
+	ForecastUrl  string               // URL template ('%s' is the zone) of the current forecasts of the casdk method, none when empty
+	Filter       string               // gjson path of the carbon intensity in the responses of the simpledynamic and casdk methods
+	Conv2J       float64              // Conversion factor to grams of CO2 per joule of the simpledynamic and casdk methods
+	ApiUrl       string               // Base URL of the native providers, their public API when empty
+	SecretName   string               // Secret holding the API credentials ('token', or 'username' and 'password')
+	Secrets      config.SecretManager // Reads the Secret holding the API credentials
+	Client       *http.Client         // HTTP client, with a default timeout when nil
+}
+
+// NewCarbonIntensityProvider creates the provider of a carbon intensity calculation method, which is nil for the
+// static method
+func NewCarbonIntensityProvider(method string, providerConfig CarbonIntensityProviderConfig) (CarbonIntensityProvider, error) {
+	if providerConfig.Client == nil {
+		providerConfig.Client = &http.Client{Timeout: carbonIntensityRequestTimeout}
+	}
+	apiUrl := func(defaultUrl string) string {
+		if providerConfig.ApiUrl == "" {
+			return defaultUrl
+		}
+		return strings.TrimSuffix(providerConfig.ApiUrl, "/")
+	}
+	credentials := &carbonAPICredentials{secretName: providerConfig.SecretName, secrets: providerConfig.Secrets}
+
+	switch method {
+	case CarbonMethodStatic:
+		return nil, nil
+
+	case CarbonMethodSimpleDynamic:
+		return &JSONCarbonIntensityProvider{client: providerConfig.Client, url: providerConfig.IntensityUrl,
+			filter: providerConfig.Filter, conv2J: providerConfig.Conv2J}, nil
+
+	case CarbonMethodCASDK:
+		return &CarbonAwareSDKProvider{client: providerConfig.Client, url: providerConfig.IntensityUrl, forecastUrl: providerConfig.ForecastUrl,
+			filter: providerConfig.Filter, conv2J: providerConfig.Conv2J}, nil
+
+	case CarbonMethodElectricityMaps:
+		return &ElectricityMapsProvider{client: providerConfig.Client, url: apiUrl(defaultElectricityMapsUrl), credentials: credentials}, nil
+
+	case CarbonMethodWattTime:
+		if providerConfig.SecretName == "" {
+			return nil, fmt.Errorf("the %s carbon method requires a secret with the WattTime username and password", method)
+		}
+		return &WattTimeProvider{client: providerConfig.Client, url: apiUrl(defaultWattTimeUrl), credentials: credentials}, nil
+
+	case CarbonMethodUKGrid:
+		return &UKGridProvider{client: providerConfig.Client, url: apiUrl(defaultUKGridUrl)}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown carbon method '%s'", method)
+	}
+}
+
+// JSONCarbonIntensityProvider reads the carbon intensity from a JSON object returned by a generic web API
+type JSONCarbonIntensityProvider struct {
+	client *http.Client
+	url    string
+	filter string
+	conv2J float64
+}
+
+func (j *JSONCarbonIntensityProvider) CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error) {
+	queryUrl := fmt.Sprintf(j.url, zone)
+
+	responseData, err := getCarbonAPI(ctx, j.client, queryUrl, nil)
+	if err != nil {
+		return CarbonIntensitySample{}, err
+	}
+
+	carbonIntensity, err := parseCarbonIntensity(responseData, j.filter)
+	if err != nil {
+		return CarbonIntensitySample{}, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+
+	return CarbonIntensitySample{Time: time.Now(), GramsPerJoule: carbonIntensity * j.conv2J}, nil
+}
+
+// CarbonAwareSDKProvider reads the last carbon intensity of the emissions returned by a Carbon Aware SDK WebAPI
+type CarbonAwareSDKProvider struct {
+	client      *http.Client
+	url         string
+	forecastUrl string
+	filter      string
+	conv2J      float64
+}
+
+func (c *CarbonAwareSDKProvider) CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error) {
+	queryUrl := fmt.Sprintf(c.url, zone)
+
+	responseData, err := getCarbonAPI(ctx, c.client, queryUrl, nil)
+	if err != nil {
+		return CarbonIntensitySample{}, err
+	}
+
+	index := strconv.FormatInt(gjson.GetBytes(responseData, "#").Int()-1, 10)
+
+	carbonIntensity, err := parseCarbonIntensity(responseData, index+"."+c.filter)
+	if err != nil {
+		return CarbonIntensitySample{}, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+
+	return CarbonIntensitySample{
+		Time:          parseTimeOrNow(time.RFC3339, gjson.GetBytes(responseData, index+".time").String()),
+		GramsPerJoule: carbonIntensity * c.conv2J,
+	}, nil
+}
+
+// ElectricityMapsProvider reads the latest carbon intensity of a zone from the Electricity Maps API
+type ElectricityMapsProvider struct {
+	client      *http.Client
+	url         string
+	credentials *carbonAPICredentials
+}
+
+func (e *ElectricityMapsProvider) CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error) {
+	headers := make(http.Header)
+	if e.credentials.secretName != "" {
+		token, err := e.credentials.get(ctx, "token")
+		if err != nil {
+			return CarbonIntensitySample{}, err
+		}
+		headers.Set("auth-token", token)
+	}
+
+	queryUrl := fmt.Sprintf("%s/v3/carbon-intensity/latest?zone=%s", e.url, url.QueryEscape(zone))
+
+	responseData, err := getCarbonAPI(ctx, e.client, queryUrl, headers)
+	if err != nil {
+		return CarbonIntensitySample{}, err
+	}
+
+	carbonIntensity, err := parseCarbonIntensity(responseData, "carbonIntensity")
+	if err != nil {
+		return CarbonIntensitySample{}, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+
+	return CarbonIntensitySample{
+		Time:          parseTimeOrNow(time.RFC3339, gjson.GetBytes(responseData, "datetime").String()),
+		GramsPerJoule: carbonIntensity * gramsPerKWhToGramsPerJoule,
+	}, nil
+}
+
+func (e *ElectricityMapsProvider) CarbonIntensityHistory(ctx context.Context, zone string, start time.Time, end time.Time) ([]CarbonIntensitySample, error) {
+	headers := make(http.Header)
+	if e.credentials.secretName != "" {
+		token, err := e.credentials.get(ctx, "token")
+		if err != nil {
+			return nil, err
+		}
+		headers.Set("auth-token", token)
+	}
+
+	// The history holds the intensities of the last 24 hours
+	queryUrl := fmt.Sprintf("%s/v3/carbon-intensity/history?zone=%s", e.url, url.QueryEscape(zone))
+
+	responseData, err := getCarbonAPI(ctx, e.client, queryUrl, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCarbonIntensityHistory(responseData, "history", "datetime", time.RFC3339, "carbonIntensity", gramsPerKWhToGramsPerJoule, start, end), nil
+}
+
+// WattTimeProvider reads the marginal operating emissions rate (MOER) of a region from the WattTime API, logging in
+// with the username and password of the credentials Secret
+type WattTimeProvider struct {
+	client      *http.Client
+	url         string
+	credentials *carbonAPICredentials
+	mutex       sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func (w *WattTimeProvider) CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error) {
+	queryUrl := fmt.Sprintf("%s/v3/forecast?region=%s&signal_type=co2_moer&horizon_hours=0", w.url, url.QueryEscape(zone))
+
+	responseData, err := w.get(ctx, queryUrl)
+	if err != nil {
+		return CarbonIntensitySample{}, err
+	}
+
+	carbonIntensity, err := parseCarbonIntensity(responseData, "data.0.value")
+	if err != nil {
+		return CarbonIntensitySample{}, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+
+	return CarbonIntensitySample{
+		Time:          parseTimeOrNow(time.RFC3339, gjson.GetBytes(responseData, "data.0.point_time").String()),
+		GramsPerJoule: carbonIntensity * poundsPerMWhToGramsPerJoule,
+	}, nil
+}
+
+func (w *WattTimeProvider) CarbonIntensityHistory(ctx context.Context, zone string, start time.Time, end time.Time) ([]CarbonIntensitySample, error) {
+	queryUrl := fmt.Sprintf("%s/v3/historical?region=%s&signal_type=co2_moer&start=%s&end=%s", w.url, url.QueryEscape(zone),
+		url.QueryEscape(start.UTC().Format(time.RFC3339)), url.QueryEscape(end.UTC().Format(time.RFC3339)))
+
+	responseData, err := w.get(ctx, queryUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCarbonIntensityHistory(responseData, "data", "point_time", time.RFC3339, "value", poundsPerMWhToGramsPerJoule, start, end), nil
+}
+
+// Send an authenticated request, logging in again once when the token was rejected
+func (w *WattTimeProvider) get(ctx context.Context, queryUrl string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		token, err := w.login(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		responseData, err := getCarbonAPI(ctx, w.client, queryUrl, http.Header{"Authorization": {"Bearer " + token}})
+		if statusErr, ok := err.(*carbonAPIStatusError); ok && statusErr.statusCode == http.StatusUnauthorized && attempt == 0 {
+			w.mutex.Lock()
+			w.token = ""
+			w.mutex.Unlock()
+			continue
+		}
+
+		return responseData, err
+	}
+}
+
+// Return the current token, logging in when it is missing or about to expire
+func (w *WattTimeProvider) login(ctx context.Context) (string, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.token != "" && time.Now().Before(w.tokenExpiry) {
+		return w.token, nil
+	}
+
+	username, err := w.credentials.get(ctx, "username")
+	if err != nil {
+		return "", err
+	}
+	password, err := w.credentials.get(ctx, "password")
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, w.url+"/login", nil)
+	if err != nil {
+		return "", err
+	}
+	request.SetBasicAuth(username, password)
+
+	responseData, err := doCarbonAPI(w.client, request)
+	if err != nil {
+		return "", fmt.Errorf("WattTime login failed: %w", err)
+	}
+
+	token := gjson.GetBytes(responseData, "token").String()
+	if token == "" {
+		return "", fmt.Errorf("WattTime login failed: no token in the response")
+	}
+
+	w.token = token
+	w.tokenExpiry = time.Now().Add(wattTimeTokenLifetime)
+
+	return token, nil
+}
+
+// UKGridProvider reads the carbon intensity of Great Britain ('GB' or empty zone) or of one of its regions (numeric
+// region id zone) from the UK National Grid carbon intensity API, preferring actual values to forecasts
+type UKGridProvider struct {
+	client *http.Client
+	url    string
+}
+
+func (u *UKGridProvider) CarbonIntensity(ctx context.Context, zone string) (CarbonIntensitySample, error) {
+	queryUrl := u.url + "/intensity"
+	intervalPath := "data.0"
+	if zone != "" && zone != "GB" {
+		if _, err := strconv.Atoi(zone); err != nil {
+			return CarbonIntensitySample{}, fmt.Errorf("invalid UK National Grid zone '%s', expected 'GB' or a region id", zone)
+		}
+		queryUrl = fmt.Sprintf("%s/regional/regionid/%s", u.url, zone)
+		intervalPath = "data.0.data.0"
+	}
+
+	responseData, err := getCarbonAPI(ctx, u.client, queryUrl, nil)
+	if err != nil {
+		return CarbonIntensitySample{}, err
+	}
+
+	intensityPath := intervalPath + ".intensity.actual"
+	if gjson.GetBytes(responseData, intensityPath).Type != gjson.Number {
+		intensityPath = intervalPath + ".intensity.forecast"
+	}
+
+	carbonIntensity, err := parseCarbonIntensity(responseData, intensityPath)
+	if err != nil {
+		return CarbonIntensitySample{}, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+
+	return CarbonIntensitySample{
+		Time:          parseTimeOrNow(ukGridTimeLayout, gjson.GetBytes(responseData, intervalPath+".from").String()),
+		GramsPerJoule: carbonIntensity * gramsPerKWhToGramsPerJoule,
+	}, nil
+}
+
+func (u *UKGridProvider) CarbonIntensityHistory(ctx context.Context, zone string, start time.Time, end time.Time) ([]CarbonIntensitySample, error) {
+	from := start.UTC().Format(ukGridTimeLayout)
+	to := end.UTC().Format(ukGridTimeLayout)
+
+	queryUrl := fmt.Sprintf("%s/intensity/%s/%s", u.url, from, to)
+	intervalsPath := "data"
+	if zone != "" && zone != "GB" {
+		if _, err := strconv.Atoi(zone); err != nil {
+			return nil, fmt.Errorf("invalid UK National Grid zone '%s', expected 'GB' or a region id", zone)
+		}
+		queryUrl = fmt.Sprintf("%s/regional/intensity/%s/%s/regionid/%s", u.url, from, to, zone)
+		intervalsPath = "data.data"
+	}
+
+	responseData, err := getCarbonAPI(ctx, u.client, queryUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []CarbonIntensitySample
+	for _, interval := range gjson.GetBytes(responseData, intervalsPath).Array() {
+		intensity := interval.Get("intensity.actual")
+		if intensity.Type != gjson.Number {
+			intensity = interval.Get("intensity.forecast")
+		}
+		intervalTime, err := time.Parse(ukGridTimeLayout, interval.Get("from").String())
+		if intensity.Type != gjson.Number || err != nil {
+			continue
+		}
+		samples = append(samples, CarbonIntensitySample{Time: intervalTime, GramsPerJoule: intensity.Float() * gramsPerKWhToGramsPerJoule})
+	}
+
+	return samples, nil
+}
+
+// carbonAPICredentials reads the keys of the Secret holding the credentials of a carbon intensity API
+type carbonAPICredentials struct {
+	secretName string
+	secrets    config.SecretManager
+}
+
+func (c *carbonAPICredentials) get(ctx context.Context, key string) (string, error) {
+	if c.secretName == "" || c.secrets == nil {
+		return "", fmt.Errorf("no secret is configured for the carbon intensity API credentials")
+	}
+
+	return c.secrets.Fetch(ctx, c.secretName+"/"+key)
+}
+
+// carbonAPIStatusError is returned when a carbon intensity API answers with an error status
+type carbonAPIStatusError struct {
+	statusCode int
+	url        string
+}
+
+func (e *carbonAPIStatusError) Error() string {
+	return fmt.Sprintf("HTTP request failed with status %d\nURL=%s", e.statusCode, e.url)
+}
+
+func getCarbonAPI(ctx context.Context, client *http.Client, queryUrl string, headers http.Header) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, queryUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w\nURL=%s", err, queryUrl)
+	}
+	for name, values := range headers {
+		request.Header[name] = values
+	}
+
+	return doCarbonAPI(client, request)
+}
+
+func doCarbonAPI(client *http.Client, request *http.Request) ([]byte, error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w\nURL=%s", err, request.URL.Redacted())
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, &carbonAPIStatusError{statusCode: response.StatusCode, url: request.URL.Redacted()}
+	}
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w\nURL=%s", err, request.URL.Redacted())
+	}
+
+	return responseData, nil
+}
+
+func parseCarbonIntensity(responseData []byte, filter string) (float64, error) {
+	carbonIntensityString := gjson.GetBytes(responseData, filter).String()
+
+	carbonIntensity, err := strconv.ParseFloat(carbonIntensityString, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("%w\nresponse=%s\nfilter=%s\nresult=%s", err, string(responseData), filter, carbonIntensityString)
+	}
+
+	return carbonIntensity, nil
+}
+
+// Parse the samples of an array of a response between start and end, skipping the invalid ones
+func parseCarbonIntensityHistory(responseData []byte, arrayPath string, timePath string, timeLayout string, valuePath string,
+	conversion float64, start time.Time, end time.Time) []CarbonIntensitySample {
+	var samples []CarbonIntensitySample
+
+	for _, result := range gjson.GetBytes(responseData, arrayPath).Array() {
+		sampleTime, err := time.Parse(timeLayout, result.Get(timePath).String())
+		value := result.Get(valuePath)
+		if err != nil || value.Type != gjson.Number || sampleTime.Before(start) || sampleTime.After(end) {
+			continue
+		}
+		samples = append(samples, CarbonIntensitySample{Time: sampleTime, GramsPerJoule: value.Float() * conversion})
+	}
+
+	return samples
+}
+
+// Parse the time of a carbon intensity, which is now when the provider does not return a valid one
+func parseTimeOrNow(layout string, value string) time.Time {
+	if parsedTime, err := time.Parse(layout, value); err == nil {
+		return parsedTime
+	}
+
+	return time.Now()
+}
+
+// Time during which the carbon of past energy can be corrected, zero when the provider does not publish past intensities
+func (r *LabelGroupReconciler) carbonCorrectionWindow() time.Duration {
+	if _, ok := r.CarbonIntensityProvider.(CarbonIntensityHistoryProvider); !ok {
+		return 0
+	}
+
+	return r.CarbonCorrectionWindow
+}
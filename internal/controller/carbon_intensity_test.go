@@ -0,0 +1,188 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Carbon Intensity Providers", func() {
+	var requests []*http.Request
+	var server *httptest.Server
+	var responses map[string]string
+	var secretManager *SecretManager
+
+	BeforeEach(func() {
+		requests = nil
+		responses = make(map[string]string)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			response, ok := responses[req.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(response))
+		}))
+		secretManager = NewSecretManager(fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "carbon-api", Namespace: "susql"},
+			Data: map[string][]byte{
+				"token":    []byte("em-token"),
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		}).Build(), "susql")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newProvider := func(method string) CarbonIntensityProvider {
+		provider, err := NewCarbonIntensityProvider(method, CarbonIntensityProviderConfig{
+			IntensityUrl: server.URL + "/intensity/%s",
+			Filter:       "rating",
+			Conv2J:       gramsPerKWhToGramsPerJoule,
+			ApiUrl:       server.URL,
+			SecretName:   "carbon-api",
+			Secrets:      secretManager,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return provider
+	}
+
+	It("should not create a provider for the static method", func() {
+		provider, err := NewCarbonIntensityProvider(CarbonMethodStatic, CarbonIntensityProviderConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider).To(BeNil())
+
+		_, err = NewCarbonIntensityProvider("unknown", CarbonIntensityProviderConfig{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the filtered value of generic JSON APIs", func() {
+		responses["/intensity/JP-TK"] = `{"zone":"JP-TK","rating":360}`
+
+		sample, err := newProvider(CarbonMethodSimpleDynamic).CarbonIntensity(context.Background(), "JP-TK")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+	})
+
+	It("should read the last emissions of the Carbon Aware SDK", func() {
+		responses["/intensity/eastus"] = `[
+			{"location":"eastus","time":"2026-01-01T10:00:00Z","rating":100},
+			{"location":"eastus","time":"2026-01-01T10:05:00Z","rating":360}
+		]`
+
+		sample, err := newProvider(CarbonMethodCASDK).CarbonIntensity(context.Background(), "eastus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+		Expect(sample.Time).To(Equal(time.Date(2026, 1, 1, 10, 5, 0, 0, time.UTC)))
+	})
+
+	It("should send the Electricity Maps token", func() {
+		responses["/v3/carbon-intensity/latest"] = `{"zone":"DE","carbonIntensity":720,"datetime":"2026-01-01T10:00:00.000Z"}`
+
+		sample, err := newProvider(CarbonMethodElectricityMaps).CarbonIntensity(context.Background(), "DE")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 2e-4, 1e-12))
+		Expect(sample.Time).To(Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)))
+		Expect(requests[0].URL.Query().Get("zone")).To(Equal("DE"))
+		Expect(requests[0].Header.Get("auth-token")).To(Equal("em-token"))
+	})
+
+	It("should log in to WattTime and read the MOER", func() {
+		responses["/login"] = `{"token":"wt-token"}`
+		responses["/v3/forecast"] = `{"data":[{"point_time":"2026-01-01T10:00:00+00:00","value":1000}],"meta":{"units":"lbs_co2_per_mwh"}}`
+
+		provider := newProvider(CarbonMethodWattTime)
+		sample, err := provider.CarbonIntensity(context.Background(), "CAISO_NORTH")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 453.59237/3.6e6, 1e-12))
+
+		_, err = provider.CarbonIntensity(context.Background(), "CAISO_NORTH")
+		Expect(err).NotTo(HaveOccurred())
+
+		// The token is reused by the second query
+		Expect(requests).To(HaveLen(3))
+		username, password, ok := requests[0].BasicAuth()
+		Expect(ok).To(BeTrue())
+		Expect(username).To(Equal("user"))
+		Expect(password).To(Equal("pass"))
+		Expect(requests[1].URL.Query().Get("region")).To(Equal("CAISO_NORTH"))
+		Expect(requests[1].Header.Get("Authorization")).To(Equal("Bearer wt-token"))
+		Expect(requests[2].Header.Get("Authorization")).To(Equal("Bearer wt-token"))
+	})
+
+	It("should read the UK National Grid national and regional intensities", func() {
+		responses["/intensity"] = `{"data":[{"from":"2026-01-01T10:00Z","to":"2026-01-01T10:30Z","intensity":{"forecast":200,"actual":180,"index":"moderate"}}]}`
+		responses["/regional/regionid/13"] = `{"data":[{"regionid":13,"shortname":"London","data":[{"from":"2026-01-01T10:00Z","to":"2026-01-01T10:30Z","intensity":{"forecast":360,"index":"moderate"}}]}]}`
+
+		provider := newProvider(CarbonMethodUKGrid)
+
+		sample, err := provider.CarbonIntensity(context.Background(), "GB")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 5e-5, 1e-12))
+		Expect(sample.Time).To(Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)))
+
+		sample, err = provider.CarbonIntensity(context.Background(), "13")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample.GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+
+		_, err = provider.CarbonIntensity(context.Background(), "London")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the finalized intensities of the past periods", func() {
+		start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+		responses["/intensity/2026-01-01T09:00Z/2026-01-01T10:00Z"] = `{"data":[
+			{"from":"2026-01-01T09:00Z","to":"2026-01-01T09:30Z","intensity":{"forecast":200,"actual":180,"index":"moderate"}},
+			{"from":"2026-01-01T09:30Z","to":"2026-01-01T10:00Z","intensity":{"forecast":360,"actual":null,"index":"moderate"}}
+		]}`
+		responses["/v3/carbon-intensity/history"] = `{"zone":"DE","history":[
+			{"carbonIntensity":360,"datetime":"2026-01-01T08:00:00.000Z"},
+			{"carbonIntensity":720,"datetime":"2026-01-01T09:00:00.000Z"}
+		]}`
+
+		samples, err := newProvider(CarbonMethodUKGrid).(CarbonIntensityHistoryProvider).CarbonIntensityHistory(context.Background(), "GB", start, start.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(2))
+		Expect(samples[0].Time).To(Equal(start))
+		Expect(samples[0].GramsPerJoule).To(BeNumerically("~", 5e-5, 1e-12))
+		Expect(samples[1].GramsPerJoule).To(BeNumerically("~", 1e-4, 1e-12))
+
+		samples, err = newProvider(CarbonMethodElectricityMaps).(CarbonIntensityHistoryProvider).CarbonIntensityHistory(context.Background(), "DE", start, start.Add(time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(samples).To(HaveLen(1))
+		Expect(samples[0].GramsPerJoule).To(BeNumerically("~", 2e-4, 1e-12))
+	})
+
+	It("should report API errors", func() {
+		_, err := newProvider(CarbonMethodElectricityMaps).CarbonIntensity(context.Background(), "DE")
+		Expect(err).To(HaveOccurred())
+	})
+})
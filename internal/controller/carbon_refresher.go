@@ -0,0 +1,236 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	carbonRetryMinDelay   = 30 * time.Second // Delay before retrying a failed carbon intensity query, doubled at each failure
+	carbonRetryMaxDelay   = 5 * time.Minute  // Maximum delay between the retries of failed carbon intensity queries
+	carbonRefreshJitter   = 0.1              // Fraction of the delays added at random, so that replicas do not query in lockstep
+	carbonStaleQueryCount = 2                // Number of query periods after which the carbon intensity is stale
+)
+
+// carbonIntensityState is the carbon intensity of the default zone published by the refresher
+type carbonIntensityState struct {
+	gramsPerJoule float64
+	updated       time.Time            // Last successful query of the default zone, zero before the first one
+	zoneUpdated   map[string]time.Time // Last successful query of each zone, absent before the first one
+	failed        time.Time // Last failed query of any zone, zero once all the zones were queried successfully
+	err           error     // Error of the last failed query
+}
+
+// CarbonIntensityRefresher queries the carbon intensity of the carbon zones on its own schedule, independently of the
+// reconciliation of the label groups, and publishes it to the reconciler. Each zone is queried every CarbonQueryRate
+// seconds, and retried with an exponential backoff after a failure. Zones that were never queried, e.g., the zone of
+// a node that was just mapped, are queried right away.
+type CarbonIntensityRefresher struct {
+	reconciler *LabelGroupReconciler
+	nextQuery  map[string]time.Time // Time of the next query of each zone
+	failures   map[string]int       // Number of consecutive failed queries of each zone
+	zoneErrors map[string]error     // Error of the last query of each zone, nil after a successful one
+}
+
+// NewCarbonIntensityRefresher creates the refresher of the carbon intensity used by a reconciler
+func NewCarbonIntensityRefresher(reconciler *LabelGroupReconciler) *CarbonIntensityRefresher {
+	reconciler.carbonRefreshTrigger = make(chan struct{}, 1)
+
+	return &CarbonIntensityRefresher{
+		reconciler: reconciler,
+		nextQuery:  make(map[string]time.Time),
+		failures:   make(map[string]int),
+		zoneErrors: make(map[string]error),
+	}
+}
+
+// Start refreshes the carbon intensity until the context is cancelled
+func (f *CarbonIntensityRefresher) Start(ctx context.Context) error {
+	r := f.reconciler
+	r.Logger.V(2).Info(fmt.Sprintf("[CarbonIntensityRefresher] Refreshing the '%s' carbon intensity every %d seconds.", r.CarbonMethod, r.CarbonQueryRate))
+
+	for {
+		delay := f.refresh(ctx, time.Now())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-r.carbonRefreshTrigger:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false so that standby replicas keep their carbon intensity fresh for a failover
+func (f *CarbonIntensityRefresher) NeedLeaderElection() bool {
+	return false
+}
+
+// refresh queries the zones whose query is due, publishes the carbon intensity and returns the delay until the next
+// query
+func (f *CarbonIntensityRefresher) refresh(ctx context.Context, now time.Time) time.Duration {
+	r := f.reconciler
+	queryRate := time.Duration(r.CarbonQueryRate) * time.Second
+	state := r.currentCarbonIntensity()
+	state.zoneUpdated = maps.Clone(state.zoneUpdated) // The published state is shared with the readers
+	if state.zoneUpdated == nil {
+		state.zoneUpdated = make(map[string]time.Time)
+	}
+	next := now.Add(queryRate)
+
+	for _, zone := range r.carbonZones() {
+		if due := f.nextQuery[zone]; due.After(now) {
+			if due.Before(next) {
+				next = due
+			}
+			continue
+		}
+
+		sample, err := f.refreshZone(ctx, zone, now)
+		f.zoneErrors[zone] = err
+		if err != nil {
+			r.Logger.V(0).Error(err, fmt.Sprintf("[CarbonIntensityRefresher] Unable to query the '%s' carbon intensity of zone '%s'.", r.CarbonMethod, zone))
+			f.failures[zone]++
+			f.nextQuery[zone] = now.Add(wait.Jitter(retryDelay(f.failures[zone], queryRate), carbonRefreshJitter))
+			state.failed = now
+			state.err = err
+			recordCarbonIntensityError(zone, now)
+		} else {
+			f.failures[zone] = 0
+			f.nextQuery[zone] = now.Add(wait.Jitter(queryRate, carbonRefreshJitter))
+			state.zoneUpdated[zone] = now
+			if zone == r.CarbonLocation {
+				state.gramsPerJoule = sample.GramsPerJoule
+				state.updated = now
+			}
+			recordCarbonIntensitySuccess(zone, sample.GramsPerJoule, now)
+		}
+
+		if f.nextQuery[zone].Before(next) {
+			next = f.nextQuery[zone]
+		}
+	}
+
+	// The last error is kept until every zone was queried successfully
+	failing := false
+	for _, err := range f.zoneErrors {
+		failing = failing || err != nil
+	}
+	if !failing {
+		state.failed = time.Time{}
+		state.err = nil
+	}
+
+	r.carbonIntensity.Store(&state)
+
+	return next.Sub(now)
+}
+
+// refreshZone queries the carbon intensity of a zone, and the finalized intensities of its correction window
+func (f *CarbonIntensityRefresher) refreshZone(ctx context.Context, zone string, now time.Time) (CarbonIntensitySample, error) {
+	r := f.reconciler
+	series := r.carbonSeriesOf(zone)
+
+	sample, err := r.CarbonIntensityProvider.CarbonIntensity(ctx, zone)
+	if err != nil {
+		return sample, err
+	}
+
+	r.Logger.V(5).Info(fmt.Sprintf("[CarbonIntensityRefresher] Obtained dynamic carbon intensity of %.10f for zone '%s'.", sample.GramsPerJoule, zone))
+	series.Add(now, sample)
+
+	// Replace the intensities of the correction window with the finalized ones
+	if historyProvider, ok := r.CarbonIntensityProvider.(CarbonIntensityHistoryProvider); ok && r.CarbonCorrectionWindow > 0 {
+		history, err := historyProvider.CarbonIntensityHistory(ctx, zone, now.Add(-r.CarbonCorrectionWindow), now)
+		if err != nil {
+			r.Logger.V(0).Error(err, fmt.Sprintf("[CarbonIntensityRefresher] Unable to query the carbon intensity history of zone '%s'.", zone))
+			return sample, nil
+		}
+		series.Add(now, history...)
+	}
+
+	r.refreshForecast(ctx, zone, now)
+
+	return sample, nil
+}
+
+// Delay before the next query of a zone after consecutive failures, which does not exceed the query rate
+func retryDelay(failures int, queryRate time.Duration) time.Duration {
+	delay := carbonRetryMaxDelay
+	if failures < 8 {
+		delay = min(carbonRetryMinDelay<<(failures-1), carbonRetryMaxDelay)
+	}
+
+	return min(delay, queryRate)
+}
+
+// Record the carbon intensity and the time of the last successful carbon intensity query of a zone in the exported
+// metrics
+func recordCarbonIntensitySuccess(zone string, gramsPerJoule float64, now time.Time) {
+	if susqlMetrics != nil {
+		susqlMetrics.carbonIntensity.WithLabelValues(zone).Set(gramsPerJoule)
+		susqlMetrics.carbonIntensityLastSuccess.WithLabelValues(zone).Set(float64(now.Unix()))
+	}
+}
+
+// Record a failed carbon intensity query of a zone in the exported metrics
+func recordCarbonIntensityError(zone string, now time.Time) {
+	if susqlMetrics != nil {
+		susqlMetrics.carbonIntensityQueryErrors.WithLabelValues(zone).Inc()
+		susqlMetrics.carbonIntensityLastError.WithLabelValues(zone).Set(float64(now.Unix()))
+	}
+}
+
+// Current carbon intensity of the default zone, which is the initial CarbonIntensity until the refresher publishes one
+func (r *LabelGroupReconciler) currentCarbonIntensity() carbonIntensityState {
+	if state := r.carbonIntensity.Load(); state != nil {
+		return *state
+	}
+
+	return carbonIntensityState{gramsPerJoule: r.CarbonIntensity}
+}
+
+// Wake the refresher up, e.g., to query the carbon intensity of a new zone
+func (r *LabelGroupReconciler) triggerCarbonRefresh() {
+	if r.carbonRefreshTrigger == nil {
+		return
+	}
+
+	select {
+	case r.carbonRefreshTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// The carbon intensity is stale once scheduled queries have been missed
+func (r *LabelGroupReconciler) carbonIntensityStale(state carbonIntensityState, now time.Time) bool {
+	return r.carbonUpdateStale(state.updated, now)
+}
+
+// A carbon intensity last updated at a time is stale once scheduled queries have been missed
+func (r *LabelGroupReconciler) carbonUpdateStale(updated time.Time, now time.Time) bool {
+	return now.Sub(updated) > carbonStaleQueryCount*time.Duration(r.CarbonQueryRate)*time.Second
+}
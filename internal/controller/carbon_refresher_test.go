@@ -0,0 +1,192 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// fakeCarbonIntensityProvider returns fixed intensities, or an error for the failing zones
+type fakeCarbonIntensityProvider struct {
+	intensities map[string]float64
+	failing     map[string]bool
+	queries     []string
+}
+
+func (p *fakeCarbonIntensityProvider) CarbonIntensity(_ context.Context, zone string) (CarbonIntensitySample, error) {
+	p.queries = append(p.queries, zone)
+	if p.failing[zone] {
+		return CarbonIntensitySample{}, fmt.Errorf("zone '%s' is unavailable", zone)
+	}
+
+	return CarbonIntensitySample{Time: time.Now(), GramsPerJoule: p.intensities[zone]}, nil
+}
+
+var _ = Describe("Carbon Intensity Refresher", func() {
+	var provider *fakeCarbonIntensityProvider
+	var reconciler *LabelGroupReconciler
+	var refresher *CarbonIntensityRefresher
+	var now time.Time
+
+	BeforeEach(func() {
+		provider = &fakeCarbonIntensityProvider{
+			intensities: map[string]float64{"JP-TK": 1e-4, "DE": 2e-4},
+			failing:     map[string]bool{},
+		}
+		reconciler = &LabelGroupReconciler{
+			CarbonMethod:            CarbonMethodSimpleDynamic,
+			CarbonIntensityProvider: provider,
+			CarbonIntensity:         3e-4,
+			CarbonLocation:          "JP-TK",
+			CarbonQueryRate:         3600,
+			Logger:                  logr.Discard(),
+		}
+		refresher = NewCarbonIntensityRefresher(reconciler)
+		now = time.Now()
+	})
+
+	It("should use the initial intensity until the first query", func() {
+		Expect(reconciler.currentCarbonIntensity().gramsPerJoule).To(Equal(3e-4))
+		Expect(reconciler.currentCarbonIntensity().updated.IsZero()).To(BeTrue())
+	})
+
+	It("should publish the intensity of the default zone and query each zone at the query rate", func() {
+		reconciler.carbonSeriesOf("DE")
+
+		delay := refresher.refresh(context.Background(), now)
+		Expect(provider.queries).To(ConsistOf("JP-TK", "DE"))
+		Expect(delay).To(BeNumerically(">=", time.Hour))
+
+		state := reconciler.currentCarbonIntensity()
+		Expect(state.gramsPerJoule).To(Equal(1e-4))
+		Expect(state.updated).To(Equal(now))
+		Expect(reconciler.carbonIntensityStale(state, now)).To(BeFalse())
+
+		Expect(reconciler.carbonSeriesOf("DE").segments(now, now, 0)[0].gramsPerJoule).To(Equal(2e-4))
+
+		// No zone is due before the query rate elapsed
+		refresher.refresh(context.Background(), now.Add(time.Minute))
+		Expect(provider.queries).To(HaveLen(2))
+	})
+
+	It("should query new zones right away", func() {
+		refresher.refresh(context.Background(), now)
+		Eventually(reconciler.carbonRefreshTrigger).Should(Receive())
+
+		reconciler.carbonSeriesOf("DE")
+		Expect(reconciler.carbonRefreshTrigger).To(Receive())
+
+		refresher.refresh(context.Background(), now.Add(time.Second))
+		Expect(provider.queries).To(Equal([]string{"JP-TK", "DE"}))
+	})
+
+	It("should back off after failures and keep the last error until the zones recover", func() {
+		provider.failing["JP-TK"] = true
+
+		delay := refresher.refresh(context.Background(), now)
+		Expect(delay).To(BeNumerically("<=", float64(carbonRetryMinDelay)*(1+carbonRefreshJitter)))
+		state := reconciler.currentCarbonIntensity()
+		Expect(state.gramsPerJoule).To(Equal(3e-4))
+		Expect(state.failed).To(Equal(now))
+		Expect(state.err).To(MatchError(ContainSubstring("unavailable")))
+
+		delay = refresher.refresh(context.Background(), now.Add(time.Minute))
+		Expect(delay).To(BeNumerically(">=", 2*carbonRetryMinDelay))
+
+		provider.failing["JP-TK"] = false
+		refresher.refresh(context.Background(), now.Add(time.Hour))
+		state = reconciler.currentCarbonIntensity()
+		Expect(state.gramsPerJoule).To(Equal(1e-4))
+		Expect(state.failed.IsZero()).To(BeTrue())
+		Expect(state.err).NotTo(HaveOccurred())
+	})
+
+	It("should evaluate the freshness of the intensities of the zones a group accounted in", func() {
+		labelGroup := &namespacedLabelGroup{&susqlv2.LabelGroup{}}
+		condition := func() *metav1.Condition {
+			return meta.FindStatusCondition(labelGroup.Status.Conditions, susqlv2.ConditionCarbonIntensityFresh)
+		}
+
+		// DE is queried three hours ago, and then fails
+		reconciler.carbonSeriesOf("DE")
+		refresher.refresh(context.Background(), now.Add(-3*time.Hour))
+		provider.failing["DE"] = true
+		refresher.refresh(context.Background(), now)
+
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"JP-TK"})
+		Expect(condition().Status).To(Equal(metav1.ConditionTrue))
+
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"DE", "JP-TK"})
+		Expect(condition().Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition().Reason).To(Equal(reasonIntensityStale))
+		Expect(condition().Message).To(HavePrefix("The carbon intensity of zone 'DE' was last updated at"))
+
+		// FR was never queried, and is accounted with the intensity of the default zone
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"DE", "FR", "JP-TK"})
+		Expect(condition().Reason).To(Equal(reasonIntensityNotFound))
+		Expect(condition().Message).To(ContainSubstring("zone 'FR'"))
+		Expect(condition().Message).To(ContainSubstring("fallback carbon intensity of 0.0001000000 g/J"))
+	})
+
+	It("should export the intensity and the failed queries of the zones", func() {
+		previousMetrics := susqlMetrics
+		susqlMetrics = newSusqlMetrics(nil)
+		defer func() { susqlMetrics = previousMetrics }()
+		metricValue := func(collector prometheus.Collector) float64 {
+			registry := prometheus.NewRegistry()
+			Expect(registry.Register(collector)).To(Succeed())
+			families, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			metric := families[0].GetMetric()[0]
+			return metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+		}
+
+		provider.failing["DE"] = true
+		reconciler.carbonSeriesOf("DE")
+		refresher.refresh(context.Background(), now)
+		refresher.refresh(context.Background(), now.Add(2*time.Hour))
+
+		Expect(metricValue(susqlMetrics.carbonIntensity.WithLabelValues("JP-TK"))).To(Equal(1e-4))
+		Expect(metricValue(susqlMetrics.carbonIntensityLastSuccess.WithLabelValues("JP-TK"))).To(Equal(float64(now.Add(2 * time.Hour).Unix())))
+		Expect(metricValue(susqlMetrics.carbonIntensityQueryErrors.WithLabelValues("DE"))).To(Equal(2.0))
+		Expect(metricValue(susqlMetrics.carbonIntensityQueryErrors.WithLabelValues("JP-TK"))).To(BeZero())
+	})
+
+	It("should report a stale intensity", func() {
+		refresher.refresh(context.Background(), time.Now().Add(-3*time.Hour))
+
+		Expect(reconciler.carbonIntensityStale(reconciler.currentCarbonIntensity(), time.Now())).To(BeTrue())
+	})
+
+	It("should limit the retry delay", func() {
+		Expect(retryDelay(1, time.Hour)).To(Equal(carbonRetryMinDelay))
+		Expect(retryDelay(3, time.Hour)).To(Equal(4 * carbonRetryMinDelay))
+		Expect(retryDelay(100, time.Hour)).To(Equal(carbonRetryMaxDelay))
+		Expect(retryDelay(100, time.Minute)).To(Equal(time.Minute))
+	})
+})
@@ -0,0 +1,119 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// groupCarbonConfig is the carbon configuration of a label group: the configuration of the operator with the
+// overrides of its spec
+type groupCarbonConfig struct {
+	static          bool    // Whether the static intensity applies to all the energy of the group
+	intensity       float64 // Static carbon intensity, also used by the dynamic method until a zone has a sample
+	location        string  // Carbon zone of the pods whose nodes are not mapped to a zone
+	intensityFactor float64 // Factor applied to the carbon intensity
+}
+
+// ValidateCarbonSpec checks the carbon configuration of a label group
+func ValidateCarbonSpec(spec *susqlv2.CarbonSpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	switch spec.Method {
+	case susqlv2.CarbonMethodStatic, susqlv2.CarbonMethodDynamic, "":
+	default:
+		return fmt.Errorf("unknown carbon method '%s'", spec.Method)
+	}
+
+	if _, err := parseCarbonSpecValue("intensity", spec.Intensity, 0); err != nil {
+		return err
+	}
+
+	if _, err := parseCarbonSpecValue("intensityFactor", spec.IntensityFactor, 1); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Carbon configuration of a label group, falling back to the configuration of the operator
+func (r *LabelGroupReconciler) groupCarbonConfig(spec *susqlv2.CarbonSpec) (groupCarbonConfig, error) {
+	config := groupCarbonConfig{
+		static:          r.CarbonIntensityProvider == nil,
+		intensity:       r.currentCarbonIntensity().gramsPerJoule,
+		location:        r.CarbonLocation,
+		intensityFactor: 1,
+	}
+
+	if spec == nil {
+		return config, nil
+	}
+
+	if err := ValidateCarbonSpec(spec); err != nil {
+		return config, err
+	}
+
+	switch spec.Method {
+	case susqlv2.CarbonMethodStatic:
+		config.static = true
+		// The configured static intensity, rather than the last value of the provider
+		config.intensity = r.CarbonIntensity
+	case susqlv2.CarbonMethodDynamic:
+		if r.CarbonIntensityProvider == nil {
+			return config, fmt.Errorf("the '%s' carbon method of the operator does not query carbon intensities", r.CarbonMethod)
+		}
+		config.static = false
+	}
+
+	if spec.Intensity != "" && config.static {
+		config.intensity, _ = parseCarbonSpecValue("intensity", spec.Intensity, 0)
+	}
+
+	if spec.Location != "" {
+		config.location = spec.Location
+	}
+
+	config.intensityFactor, _ = parseCarbonSpecValue("intensityFactor", spec.IntensityFactor, 1)
+
+	return config, nil
+}
+
+// Parse a finite non-negative decimal value of a carbon spec, which is defaultValue when it is not set
+func parseCarbonSpecValue(name string, value string, defaultValue float64) (float64, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid carbon %s '%s': %w", name, value, err)
+	}
+	if math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
+		return 0, fmt.Errorf("invalid carbon %s '%s': it must be a finite number", name, value)
+	}
+	if floatValue < 0 {
+		return 0, fmt.Errorf("invalid carbon %s '%s': it must not be negative", name, value)
+	}
+
+	return floatValue, nil
+}
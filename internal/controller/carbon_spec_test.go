@@ -0,0 +1,85 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Carbon Spec", func() {
+	var reconciler *LabelGroupReconciler
+
+	BeforeEach(func() {
+		reconciler = &LabelGroupReconciler{
+			CarbonMethod:            CarbonMethodElectricityMaps,
+			CarbonIntensityProvider: &fakeCarbonIntensityProvider{},
+			CarbonIntensity:         3e-4,
+			CarbonLocation:          "JP-TK",
+			Logger:                  logr.Discard(),
+		}
+	})
+
+	It("should validate the carbon configuration", func() {
+		Expect(ValidateCarbonSpec(nil)).To(Succeed())
+		Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{Method: susqlv2.CarbonMethodStatic, Intensity: "0", IntensityFactor: "0.4"})).To(Succeed())
+		Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{Method: "forecast"})).To(HaveOccurred())
+		Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{Intensity: "low"})).To(HaveOccurred())
+		Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{IntensityFactor: "-1"})).To(HaveOccurred())
+		for _, value := range []string{"NaN", "nan", "Inf", "+Inf", "-Infinity", "1e400"} {
+			Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{Intensity: value})).To(HaveOccurred(), value)
+			Expect(ValidateCarbonSpec(&susqlv2.CarbonSpec{IntensityFactor: value})).To(HaveOccurred(), value)
+		}
+	})
+
+	It("should use the carbon configuration of the operator", func() {
+		config, err := reconciler.groupCarbonConfig(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).To(Equal(groupCarbonConfig{intensity: 3e-4, location: "JP-TK", intensityFactor: 1}))
+	})
+
+	It("should override the carbon configuration of the operator", func() {
+		config, err := reconciler.groupCarbonConfig(&susqlv2.CarbonSpec{Method: susqlv2.CarbonMethodStatic, Intensity: "0.00001"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.static).To(BeTrue())
+		Expect(config.intensity).To(Equal(1e-5))
+
+		config, err = reconciler.groupCarbonConfig(&susqlv2.CarbonSpec{Location: "DE", IntensityFactor: "0.4"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.static).To(BeFalse())
+		Expect(config.location).To(Equal("DE"))
+		Expect(config.intensityFactor).To(Equal(0.4))
+	})
+
+	It("should reject the dynamic method without a carbon intensity provider", func() {
+		reconciler.CarbonIntensityProvider = nil
+
+		_, err := reconciler.groupCarbonConfig(&susqlv2.CarbonSpec{Method: susqlv2.CarbonMethodDynamic})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should scale the carbon by the intensity factor", func() {
+		start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+		carbon, _ := accountCarbon(&CarbonIntensitySeries{}, "JP-TK", nil, start, start, 100, 2e-4, 0.5, start)
+		Expect(carbon).To(BeNumerically("~", 0.01, 1e-12))
+	})
+})
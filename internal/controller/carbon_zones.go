@@ -0,0 +1,236 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// ParseCarbonZoneMap parses a comma separated list of 'key=zone' pairs, whose keys are node names or values of the
+// carbon zone node label
+func ParseCarbonZoneMap(zoneMap string) (map[string]string, error) {
+	zones := make(map[string]string)
+
+	for _, pair := range strings.Split(zoneMap, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, zone, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		zone = strings.TrimSpace(zone)
+		if !found || key == "" || zone == "" {
+			return nil, fmt.Errorf("invalid carbon zone mapping '%s', expected 'key=zone'", pair)
+		}
+		if _, duplicate := zones[key]; duplicate {
+			return nil, fmt.Errorf("duplicate carbon zone mapping of '%s'", key)
+		}
+
+		zones[key] = zone
+	}
+
+	return zones, nil
+}
+
+// Whether the nodes are mapped to carbon zones, otherwise all the energy is in the zone of CarbonLocation
+func (r *LabelGroupReconciler) carbonZonesEnabled() bool {
+	return r.CarbonZoneLabel != "" || len(r.CarbonZoneMap) > 0
+}
+
+// Carbon zone of a node: the zone mapped to its name, or to the value of its carbon zone label, or that value
+// itself, falling back to the zone of CarbonLocation
+func (r *LabelGroupReconciler) nodeZone(ctx context.Context, nodeName string) string {
+	if zone, found := r.CarbonZoneMap[nodeName]; found {
+		return zone
+	}
+
+	if r.CarbonZoneLabel == "" || nodeName == "" {
+		return r.CarbonLocation
+	}
+
+	node := &corev1.Node{}
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+		r.Logger.V(0).Error(err, fmt.Sprintf("[nodeZone] Couldn't get node '%s', using carbon zone '%s'.", nodeName, r.CarbonLocation))
+		return r.CarbonLocation
+	}
+
+	value := node.Labels[r.CarbonZoneLabel]
+	if value == "" {
+		return r.CarbonLocation
+	}
+
+	if zone, found := r.CarbonZoneMap[value]; found {
+		return zone
+	}
+
+	return value
+}
+
+// Carbon zones of the containers of pods, keyed by the container id reported by Kepler
+func (r *LabelGroupReconciler) containerZones(ctx context.Context, podsByNamespace map[string][]corev1.Pod) map[string]string {
+	zones := make(map[string]string)
+	if !r.carbonZonesEnabled() {
+		return zones
+	}
+
+	nodeZones := make(map[string]string)
+
+	for _, pods := range podsByNamespace {
+		for _, pod := range pods {
+			zone, found := nodeZones[pod.Spec.NodeName]
+			if !found {
+				zone = r.nodeZone(ctx, pod.Spec.NodeName)
+				nodeZones[pod.Spec.NodeName] = zone
+			}
+
+			for _, containerId := range podContainerIds(&pod) {
+				zones[containerId] = zone
+			}
+		}
+	}
+
+	return zones
+}
+
+// Ids of the containers of a pod, without the container runtime prefix of the pod status, the way Kepler reports them
+func podContainerIds(pod *corev1.Pod) []string {
+	var containerIds []string
+
+	for _, status := range podContainerStatuses(pod) {
+		if containerId := containerIdOf(status); containerId != "" {
+			containerIds = append(containerIds, containerId)
+		}
+	}
+
+	return containerIds
+}
+
+// Statuses of the init, regular and ephemeral containers of a pod
+func podContainerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	return slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses)
+}
+
+// Id of a container without the container runtime prefix, empty when it has not been created yet
+func containerIdOf(status corev1.ContainerStatus) string {
+	if _, containerId, found := strings.Cut(status.ContainerID, "://"); found {
+		return containerId
+	}
+
+	return status.ContainerID
+}
+
+// containerZone returns the zone of a container: the zone of its pod's node, or the zone recorded when it was active,
+// e.g., for terminated containers, or the default zone
+func containerZone(status *susqlv2.LabelGroupStatus, currentZones map[string]string, defaultZone string, containerId string) string {
+	if zone, found := currentZones[containerId]; found {
+		return zone
+	}
+	if zone, found := status.ContainerZones[containerId]; found {
+		return zone
+	}
+
+	return defaultZone
+}
+
+// energyByZone sums the energy of containers by carbon zone, see containerZone. The zones of the active containers
+// that are not in the default zone are recorded in the status.
+func energyByZone(status *susqlv2.LabelGroupStatus, containerEnergy map[string]float64, currentZones map[string]string, defaultZone string) map[string]float64 {
+	zoneOf := func(containerId string) string {
+		return containerZone(status, currentZones, defaultZone, containerId)
+	}
+
+	energy := make(map[string]float64)
+	for containerId, containerEnergy := range containerEnergy {
+		energy[zoneOf(containerId)] += containerEnergy
+	}
+
+	containerZones := make(map[string]string)
+	for containerId := range status.ActiveContainerIds {
+		if zone := zoneOf(containerId); zone != defaultZone {
+			containerZones[containerId] = zone
+		}
+	}
+	status.ContainerZones = nil
+	if len(containerZones) > 0 {
+		status.ContainerZones = containerZones
+	}
+
+	return energy
+}
+
+// addZoneCarbon adds energy and carbon to the totals of a zone in the status
+func addZoneCarbon(status *susqlv2.LabelGroupStatus, zone string, energy float64, carbon float64) {
+	for i := range status.CarbonByZone {
+		if status.CarbonByZone[i].Zone == zone {
+			status.CarbonByZone[i].EnergyJoules = *energyQuantity(quantityToFloat(&status.CarbonByZone[i].EnergyJoules) + energy)
+			status.CarbonByZone[i].CarbonGrams = *carbonQuantity(quantityToFloat(&status.CarbonByZone[i].CarbonGrams) + carbon)
+			return
+		}
+	}
+
+	status.CarbonByZone = append(status.CarbonByZone, susqlv2.ZoneCarbon{
+		Zone:         zone,
+		EnergyJoules: *energyQuantity(energy),
+		CarbonGrams:  *carbonQuantity(carbon),
+	})
+	sort.Slice(status.CarbonByZone, func(i, j int) bool { return status.CarbonByZone[i].Zone < status.CarbonByZone[j].Zone })
+}
+
+// Carbon intensity series of a zone
+func (r *LabelGroupReconciler) carbonSeriesOf(zone string) *CarbonIntensitySeries {
+	r.carbonMutex.Lock()
+	defer r.carbonMutex.Unlock()
+
+	if r.carbonSeries == nil {
+		r.carbonSeries = make(map[string]*CarbonIntensitySeries)
+	}
+
+	series, found := r.carbonSeries[zone]
+	if !found {
+		series = &CarbonIntensitySeries{}
+		r.carbonSeries[zone] = series
+		r.triggerCarbonRefresh()
+	}
+
+	return series
+}
+
+// Zones whose carbon intensity is queried: the zone of CarbonLocation first, then the zones energy was accounted in
+func (r *LabelGroupReconciler) carbonZones() []string {
+	r.carbonMutex.RLock()
+	defer r.carbonMutex.RUnlock()
+
+	zones := []string{r.CarbonLocation}
+	for zone := range r.carbonSeries {
+		if zone != r.CarbonLocation {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones[1:])
+
+	return zones
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Carbon Zones", func() {
+	newNode := func(name string, region string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if region != "" {
+			node.Labels["topology.kubernetes.io/region"] = region
+		}
+		return node
+	}
+
+	newPod := func(name string, nodeName string, containerIds ...string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Spec: corev1.PodSpec{NodeName: nodeName}}
+		for _, containerId := range containerIds {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{ContainerID: "containerd://" + containerId})
+		}
+		return pod
+	}
+
+	It("should parse the zone map", func() {
+		zones, err := ParseCarbonZoneMap(" us-east-1=US-MIDA-PJM, edge-node-1=JP-TK,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zones).To(Equal(map[string]string{"us-east-1": "US-MIDA-PJM", "edge-node-1": "JP-TK"}))
+
+		_, err = ParseCarbonZoneMap("us-east-1")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseCarbonZoneMap("a=b,a=c")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should map the containers to the zones of their nodes", func() {
+		reconciler := &LabelGroupReconciler{
+			Client:          fake.NewClientBuilder().WithObjects(newNode("node-1", "eu-west-1"), newNode("node-2", "us-east-1"), newNode("node-3", "")).Build(),
+			CarbonLocation:  "JP-TK",
+			CarbonZoneLabel: "topology.kubernetes.io/region",
+			CarbonZoneMap:   map[string]string{"us-east-1": "US-MIDA-PJM", "edge-node": "DE"},
+			Logger:          logr.Discard(),
+		}
+
+		zones := reconciler.containerZones(context.Background(), map[string][]corev1.Pod{"default": {
+			newPod("a", "node-1", "a1", "a2"),
+			newPod("b", "node-2", "b1"),
+			newPod("c", "node-3", "c1"),
+			newPod("d", "edge-node", "d1"),
+			newPod("e", "missing-node", "e1"),
+		}})
+
+		Expect(zones).To(Equal(map[string]string{
+			"a1": "eu-west-1",
+			"a2": "eu-west-1",
+			"b1": "US-MIDA-PJM",
+			"c1": "JP-TK",
+			"d1": "DE",
+			"e1": "JP-TK",
+		}))
+	})
+
+	It("should not map containers without zone configuration", func() {
+		reconciler := &LabelGroupReconciler{CarbonLocation: "JP-TK", Logger: logr.Discard()}
+
+		Expect(reconciler.containerZones(context.Background(), map[string][]corev1.Pod{"default": {newPod("a", "node-1", "a1")}})).To(BeEmpty())
+	})
+
+	It("should sum the energy by zone and remember the zones of the active containers", func() {
+		status := &susqlv2.LabelGroupStatus{
+			ActiveContainerIds: map[string]float64{"a": 10, "b": 20},
+			ContainerZones:     map[string]string{"b": "DE", "gone": "FR"},
+		}
+
+		energy := energyByZone(status, map[string]float64{"a": 1, "b": 2, "gone": 4, "new": 8}, map[string]string{"a": "US"}, "JP-TK")
+
+		Expect(energy).To(Equal(map[string]float64{"US": 1, "DE": 2, "FR": 4, "JP-TK": 8}))
+		Expect(status.ContainerZones).To(Equal(map[string]string{"a": "US", "b": "DE"}))
+	})
+
+	It("should accumulate the carbon of each zone", func() {
+		status := &susqlv2.LabelGroupStatus{}
+
+		addZoneCarbon(status, "US", 10, 0.001)
+		addZoneCarbon(status, "DE", 20, 0.004)
+		addZoneCarbon(status, "US", 5, 0.0005)
+
+		Expect(status.CarbonByZone).To(HaveLen(2))
+		Expect(status.CarbonByZone[0].Zone).To(Equal("DE"))
+		Expect(quantityToFloat(&status.CarbonByZone[1].EnergyJoules)).To(BeNumerically("~", 15, 1e-3))
+		Expect(quantityToFloat(&status.CarbonByZone[1].CarbonGrams)).To(BeNumerically("~", 0.0015, 1e-9))
+	})
+})
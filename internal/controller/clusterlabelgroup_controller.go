@@ -0,0 +1,77 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// ClusterLabelGroupReconciler reconciles a ClusterLabelGroup object. It shares the configuration,
+// the carbon intensity and the exported metrics of the LabelGroupReconciler.
+type ClusterLabelGroupReconciler struct {
+	*LabelGroupReconciler
+}
+
+// +kubebuilder:rbac:groups=susql.ibm.com,resources=clusterlabelgroups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=susql.ibm.com,resources=clusterlabelgroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=susql.ibm.com,resources=clusterlabelgroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile aggregates the energy of the pods selected by a ClusterLabelGroup across namespaces.
+func (r *ClusterLabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	_ = log.FromContext(ctx)
+
+	if err := r.waitForStorageMigration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Get ClusterLabelGroup object to process if it exists
+	clusterLabelGroupObject := &susqlv2.ClusterLabelGroup{}
+
+	err := r.Get(ctx, req.NamespacedName, clusterLabelGroupObject)
+	if err != nil {
+		// ClusterLabelGroup not found
+		return ctrl.Result{}, nil
+	}
+
+	r.Logger.V(1).Info(fmt.Sprintf("[Reconcile] Entered Reconcile() for ClusterLabelGroup '%s'.", clusterLabelGroupObject.Name))
+
+	return r.reconcileLabelGroup(ctx, &clusterLabelGroup{clusterLabelGroupObject})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterLabelGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	controllerManager := ctrl.NewControllerManagedBy(mgr).
+		For(&susqlv2.ClusterLabelGroup{}).
+		Named("susql-cluster").
+		Complete(r)
+
+	r.Logger.V(5).Info("[SetupWithManager] Initializing Metrics Exporter.")
+
+	// Start server to export metrics, unless it was already started for LabelGroups
+	if err := r.InitializeMetricsExporter(); err != nil {
+		return err
+	}
+
+	return controllerManager
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// Reasons of the conditions of the LabelGroupStatus, also used as Event reasons
+const (
+	reasonValid                     = "Valid"
+	reasonInvalidLabels             = "InvalidLabels"
+	reasonInvalidSelector           = "InvalidSelector"
+	reasonInvalidEnergySource       = "InvalidEnergySource"
+	reasonInvalidCarbon             = "InvalidCarbon"
+	reasonInvalidSCI                = "InvalidSCI"
+	reasonQuerySucceeded            = "QuerySucceeded"
+	reasonKeplerQueryFailed         = "KeplerQueryFailed"
+	reasonSusQLQueryFailed          = "SusQLQueryFailed"
+	reasonPodsFound                 = "PodsFound"
+	reasonNoPodsFound               = "NoPodsFound"
+	reasonPodListFailed             = "PodListFailed"
+	reasonStaticIntensity           = "StaticIntensity"
+	reasonIntensityUpdated          = "IntensityUpdated"
+	reasonIntensityNotFound         = "IntensityNotQueried"
+	reasonIntensityStale            = "IntensityStale"
+	reasonAggregating               = "Aggregating"
+	reasonCounterReset              = "CounterReset"
+	reasonInvalidBudget             = "InvalidBudget"
+	reasonInvalidBreakdown          = "InvalidBreakdown"
+	reasonInvalidIdleEnergy         = "InvalidIdleEnergy"
+	reasonBudgetAvailable           = "BudgetAvailable"
+	reasonBudgetWarning             = "BudgetWarning"
+	reasonBudgetExceeded            = "BudgetExceeded"
+	reasonBudgetEnforced            = "BudgetEnforced"
+	reasonBudgetEnforcementReversed = "BudgetEnforcementReversed"
+)
+
+// Set a condition of a LabelGroup or ClusterLabelGroup, emitting an Event when it changes. It returns
+// whether the condition changed, in which case the status needs to be updated.
+func (r *LabelGroupReconciler) setCondition(labelGroup labelGroupObject, conditionType string, conditionStatus metav1.ConditionStatus, reason string, message string) bool {
+	object := labelGroup.object()
+
+	changed := meta.SetStatusCondition(&labelGroup.groupStatus().Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: object.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+
+	if changed && r.Recorder != nil {
+		eventType := corev1.EventTypeNormal
+		if conditionStatus != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(object, eventType, reason, fmt.Sprintf("%s: %s", conditionType, message))
+	}
+
+	return changed
+}
+
+// Derive the Ready condition from the phase and the other conditions
+func (r *LabelGroupReconciler) setReadyCondition(labelGroup labelGroupObject) bool {
+	status := labelGroup.groupStatus()
+
+	for _, conditionType := range []string{susqlv2.ConditionSpecValid, susqlv2.ConditionPodsMatched, susqlv2.ConditionPrometheusReachable} {
+		if condition := meta.FindStatusCondition(status.Conditions, conditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+			return r.setCondition(labelGroup, susqlv2.ConditionReady, metav1.ConditionFalse, condition.Reason, condition.Message)
+		}
+	}
+
+	if status.Phase != susqlv2.Aggregating {
+		return r.setCondition(labelGroup, susqlv2.ConditionReady, metav1.ConditionFalse, string(status.Phase), fmt.Sprintf("The label group is in the '%s' phase", status.Phase))
+	}
+
+	return r.setCondition(labelGroup, susqlv2.ConditionReady, metav1.ConditionTrue, reasonAggregating, "Energy and carbon are being aggregated")
+}
+
+// Set the CarbonIntensityFresh condition from the times the carbon intensities of the zones the energy of a label
+// group was accounted in were last queried. The zones that were never queried successfully are accounted with the
+// fallback intensity, the last intensity of the zone of the operator or its initial carbon intensity.
+func (r *LabelGroupReconciler) setCarbonIntensityCondition(labelGroup labelGroupObject, zones []string) bool {
+	if spec := labelGroup.carbonSpec(); r.CarbonIntensityProvider == nil || (spec != nil && spec.Method == susqlv2.CarbonMethodStatic) {
+		return r.setCondition(labelGroup, susqlv2.ConditionCarbonIntensityFresh, metav1.ConditionTrue, reasonStaticIntensity, "A static carbon intensity is used")
+	}
+
+	state := r.currentCarbonIntensity()
+	now := time.Now()
+
+	var notQueried, stale []string
+	var oldest time.Time
+	for _, zone := range zones {
+		updated, found := state.zoneUpdated[zone]
+		if !found {
+			notQueried = append(notQueried, zone)
+			continue
+		}
+		// The intensity is stale once a scheduled query has been missed
+		if r.carbonUpdateStale(updated, now) {
+			stale = append(stale, zone)
+		}
+		if oldest.IsZero() || updated.Before(oldest) {
+			oldest = updated
+		}
+	}
+
+	if len(notQueried) > 0 {
+		return r.setCondition(labelGroup, susqlv2.ConditionCarbonIntensityFresh, metav1.ConditionFalse, reasonIntensityNotFound,
+			fmt.Sprintf("The carbon intensity of %s has not been queried successfully yet, the fallback carbon intensity of %.10f g/J is used",
+				describeZones(notQueried), state.gramsPerJoule))
+	}
+
+	if len(stale) > 0 {
+		message := fmt.Sprintf("The carbon intensity of %s was last updated at %s", describeZones(stale), oldest.UTC().Format(time.RFC3339))
+		if !state.failed.IsZero() {
+			message += fmt.Sprintf(", the last query failed at %s", state.failed.UTC().Format(time.RFC3339))
+		}
+		return r.setCondition(labelGroup, susqlv2.ConditionCarbonIntensityFresh, metav1.ConditionFalse, reasonIntensityStale, message)
+	}
+
+	return r.setCondition(labelGroup, susqlv2.ConditionCarbonIntensityFresh, metav1.ConditionTrue, reasonIntensityUpdated,
+		fmt.Sprintf("The carbon intensity of %s was updated at %s", describeZones(zones), oldest.UTC().Format(time.RFC3339)))
+}
+
+// describeZones names carbon zones in a condition message, e.g., "zones 'DE', 'FR'"
+func describeZones(zones []string) string {
+	quoted := make([]string, len(zones))
+	for i, zone := range zones {
+		quoted[i] = fmt.Sprintf("'%s'", zone)
+	}
+	if len(zones) == 1 {
+		return "zone " + quoted[0]
+	}
+
+	return "zones " + strings.Join(quoted, ", ")
+}
+
+// Update the status after a condition changed on a path that does not update it otherwise
+func (r *LabelGroupReconciler) updateConditions(ctx context.Context, labelGroup labelGroupObject, changed bool) {
+	if r.setReadyCondition(labelGroup) || changed {
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
+			r.Logger.V(0).Error(err, "[updateConditions] Couldn't update the conditions.")
+		}
+	}
+}
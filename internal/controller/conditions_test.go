@@ -0,0 +1,136 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Conditions", func() {
+	var reconciler *LabelGroupReconciler
+	var recorder *record.FakeRecorder
+	var labelGroup *namespacedLabelGroup
+
+	conditionOf := func(conditionType string) *metav1.Condition {
+		return meta.FindStatusCondition(labelGroup.Status.Conditions, conditionType)
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &LabelGroupReconciler{Recorder: recorder, CarbonQueryRate: 3600, Logger: logr.Discard()}
+		labelGroup = &namespacedLabelGroup{&susqlv2.LabelGroup{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop", Generation: 3}}}
+	})
+
+	It("should emit an Event when a condition changes only", func() {
+		Expect(reconciler.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionTrue, reasonPodsFound, "2 pods")).To(BeTrue())
+		Expect(recorder.Events).To(Receive(Equal("Normal PodsFound PodsMatched: 2 pods")))
+		Expect(conditionOf(susqlv2.ConditionPodsMatched).ObservedGeneration).To(Equal(int64(3)))
+
+		Expect(reconciler.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionTrue, reasonPodsFound, "2 pods")).To(BeFalse())
+		Expect(recorder.Events).NotTo(Receive())
+
+		// A transition to another status than True is a warning
+		Expect(reconciler.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionFalse, reasonNoPodsFound, "no pods")).To(BeTrue())
+		Expect(recorder.Events).To(Receive(Equal("Warning NoPodsFound PodsMatched: no pods")))
+		Expect(conditionOf(susqlv2.ConditionPodsMatched).Status).To(Equal(metav1.ConditionFalse))
+
+		// The conditions are still set without a recorder
+		reconciler.Recorder = nil
+		Expect(reconciler.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionTrue, reasonPodsFound, "1 pod")).To(BeTrue())
+		Expect(conditionOf(susqlv2.ConditionPodsMatched).Message).To(Equal("1 pod"))
+	})
+
+	It("should derive the Ready condition from the phase and the other conditions", func() {
+		labelGroup.Status.Phase = susqlv2.Initializing
+		reconciler.setReadyCondition(labelGroup)
+		Expect(conditionOf(susqlv2.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+		Expect(conditionOf(susqlv2.ConditionReady).Reason).To(Equal(string(susqlv2.Initializing)))
+
+		labelGroup.Status.Phase = susqlv2.Aggregating
+		reconciler.setReadyCondition(labelGroup)
+		Expect(conditionOf(susqlv2.ConditionReady).Status).To(Equal(metav1.ConditionTrue))
+		Expect(conditionOf(susqlv2.ConditionReady).Reason).To(Equal(reasonAggregating))
+
+		// A failing condition makes the group not ready with its reason, the first one in SpecValid, PodsMatched,
+		// PrometheusReachable order
+		reconciler.setCondition(labelGroup, susqlv2.ConditionPrometheusReachable, metav1.ConditionFalse, reasonKeplerQueryFailed, "timeout")
+		reconciler.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionFalse, reasonNoPodsFound, "no pods")
+		reconciler.setReadyCondition(labelGroup)
+		Expect(conditionOf(susqlv2.ConditionReady).Status).To(Equal(metav1.ConditionFalse))
+		Expect(conditionOf(susqlv2.ConditionReady).Reason).To(Equal(reasonNoPodsFound))
+		Expect(conditionOf(susqlv2.ConditionReady).Message).To(Equal("no pods"))
+
+		reconciler.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reasonInvalidSelector, "invalid selector")
+		reconciler.setReadyCondition(labelGroup)
+		Expect(conditionOf(susqlv2.ConditionReady).Reason).To(Equal(reasonInvalidSelector))
+	})
+
+	It("should tell whether the carbon intensity is fresh", func() {
+		now := time.Now()
+
+		// Without a carbon intensity provider
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"JP-TK"})
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Status).To(Equal(metav1.ConditionTrue))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Reason).To(Equal(reasonStaticIntensity))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal " + reasonStaticIntensity)))
+
+		// The static method of the group
+		reconciler.CarbonIntensityProvider = &fakeCarbonIntensityProvider{}
+		labelGroup.Spec.Carbon = &susqlv2.CarbonSpec{Method: susqlv2.CarbonMethodStatic}
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"JP-TK"})
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Reason).To(Equal(reasonStaticIntensity))
+
+		// The dynamic method before the first query
+		labelGroup.Spec.Carbon = nil
+		reconciler.CarbonIntensity = 3e-4
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"JP-TK"})
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Status).To(Equal(metav1.ConditionFalse))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Reason).To(Equal(reasonIntensityNotFound))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Message).To(ContainSubstring("fallback carbon intensity of 0.0003000000 g/J"))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning " + reasonIntensityNotFound)))
+
+		reconciler.carbonIntensity.Store(&carbonIntensityState{
+			gramsPerJoule: 1e-4,
+			updated:       now,
+			zoneUpdated:   map[string]time.Time{"JP-TK": now, "DE": now.Add(-3 * time.Hour)},
+			failed:        now,
+			err:           errors.New("zone 'DE' is unavailable"),
+		})
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"JP-TK"})
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Status).To(Equal(metav1.ConditionTrue))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Reason).To(Equal(reasonIntensityUpdated))
+		Expect(recorder.Events).To(Receive(ContainSubstring(reasonIntensityUpdated)))
+
+		// The intensity is stale once two queries were missed
+		reconciler.setCarbonIntensityCondition(labelGroup, []string{"DE", "JP-TK"})
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Status).To(Equal(metav1.ConditionFalse))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Reason).To(Equal(reasonIntensityStale))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Message).To(ContainSubstring("zone 'DE' was last updated at " + now.Add(-3*time.Hour).UTC().Format(time.RFC3339)))
+		Expect(conditionOf(susqlv2.ConditionCarbonIntensityFresh).Message).To(ContainSubstring("the last query failed at " + now.UTC().Format(time.RFC3339)))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning " + reasonIntensityStale)))
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	secondsPerYear             = 365.25 * 24 * 60 * 60 // Seconds of an average year
+	hardwareProfileListTimeout = 10 * time.Second      // Time to wait for the cache of the hardware profiles, which never syncs without permission to list them
+)
+
+var (
+	defaultEmbodiedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, "nvidia.com/gpu"} // Resources weighted equally by default
+)
+
+// ValidateHardwareProfile checks the spec of a hardware profile
+func ValidateHardwareProfile(profile *susqlv2.HardwareProfile) error {
+	if profile.Spec.EmbodiedCarbonKilograms.Sign() < 0 {
+		return fmt.Errorf("the embodied carbon of the hardware profile '%s' is negative", profile.Name)
+	}
+	if profile.Spec.LifetimeYears.Sign() <= 0 {
+		return fmt.Errorf("the lifetime of the hardware profile '%s' is not positive", profile.Name)
+	}
+	for resourceName, weight := range profile.Spec.ResourceWeights {
+		if weight.Sign() < 0 {
+			return fmt.Errorf("the weight of the resource '%s' of the hardware profile '%s' is negative", resourceName, profile.Name)
+		}
+	}
+
+	if profile.Spec.NodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(profile.Spec.NodeSelector); err != nil {
+			return fmt.Errorf("invalid node selector of the hardware profile '%s': %w", profile.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Embodied carbon of a node per second of its lifetime, in grams
+func embodiedCarbonRate(profile *susqlv2.HardwareProfile) float64 {
+	return quantityToFloat(&profile.Spec.EmbodiedCarbonKilograms) * 1000 / (quantityToFloat(&profile.Spec.LifetimeYears) * secondsPerYear)
+}
+
+// First hardware profile, in name order, whose node selector matches a node
+func hardwareProfileOf(node *corev1.Node, profiles []susqlv2.HardwareProfile) *susqlv2.HardwareProfile {
+	for i := range profiles {
+		selector := labels.Everything()
+		if profiles[i].Spec.NodeSelector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(profiles[i].Spec.NodeSelector); err != nil {
+				continue
+			}
+		}
+
+		if selector.Matches(labels.Set(node.Labels)) {
+			return &profiles[i]
+		}
+	}
+
+	return nil
+}
+
+// Resources requested by a pod, the larger of the sum of its containers and of each init container, plus its overhead
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for resourceName, quantity := range container.Resources.Requests {
+			total := requests[resourceName]
+			total.Add(quantity)
+			requests[resourceName] = total
+		}
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		for resourceName, quantity := range container.Resources.Requests {
+			if total := requests[resourceName]; quantity.Cmp(total) > 0 {
+				requests[resourceName] = quantity.DeepCopy()
+			}
+		}
+	}
+
+	for resourceName, quantity := range pod.Spec.Overhead {
+		total := requests[resourceName]
+		total.Add(quantity)
+		requests[resourceName] = total
+	}
+
+	return requests
+}
+
+// Share of a node reserved by a pod: the weighted average of the fractions of the allocatable resources of the node
+// that the pod requests
+func nodeShare(pod *corev1.Pod, node *corev1.Node, profile *susqlv2.HardwareProfile) float64 {
+	weights := make(map[corev1.ResourceName]float64)
+	if len(profile.Spec.ResourceWeights) == 0 {
+		for _, resourceName := range defaultEmbodiedResources {
+			weights[resourceName] = 1
+		}
+	} else {
+		for resourceName, weight := range profile.Spec.ResourceWeights {
+			weights[resourceName] = quantityToFloat(&weight)
+		}
+	}
+
+	requests := podRequests(pod)
+	var share, totalWeight float64
+
+	for resourceName, weight := range weights {
+		allocatable, found := node.Status.Allocatable[resourceName]
+		if !found || allocatable.Sign() <= 0 || weight <= 0 {
+			continue
+		}
+
+		request := requests[resourceName]
+		share += weight * min(quantityToFloat(&request)/quantityToFloat(&allocatable), 1)
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0
+	}
+
+	return share / totalWeight
+}
+
+// embodiedCarbon allocates the embodied carbon of the nodes running the pods of a label group during an interval,
+// in grams, by the share of each node the running pods reserved
+func (r *LabelGroupReconciler) embodiedCarbon(ctx context.Context, podsByNamespace map[string][]corev1.Pod, interval time.Duration) (float64, error) {
+	if interval <= 0 {
+		return 0, nil
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, hardwareProfileListTimeout)
+	defer cancel()
+
+	profileList := &susqlv2.HardwareProfileList{}
+	if err := r.List(listCtx, profileList); err != nil {
+		return 0, fmt.Errorf("couldn't list the hardware profiles: %w", err)
+	}
+
+	var profiles []susqlv2.HardwareProfile
+	for _, profile := range profileList.Items {
+		if err := ValidateHardwareProfile(&profile); err != nil {
+			r.Logger.V(0).Error(err, "[embodiedCarbon] Ignoring the invalid hardware profile.")
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	if len(profiles) == 0 {
+		return 0, nil
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+
+	nodes := make(map[string]*corev1.Node)
+	var carbon float64
+
+	for _, pods := range podsByNamespace {
+		for i := range pods {
+			pod := &pods[i]
+			if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+
+			node, found := nodes[pod.Spec.NodeName]
+			if !found {
+				node = &corev1.Node{}
+				if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+					r.Logger.V(2).Info(fmt.Sprintf("[embodiedCarbon] Couldn't get node '%s': %v", pod.Spec.NodeName, err))
+					node = nil
+				}
+				nodes[pod.Spec.NodeName] = node
+			}
+			if node == nil {
+				continue
+			}
+
+			profile := hardwareProfileOf(node, profiles)
+			if profile == nil {
+				continue
+			}
+
+			carbon += embodiedCarbonRate(profile) * nodeShare(pod, node, profile) * interval.Seconds()
+		}
+	}
+
+	return carbon, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Embodied Carbon", func() {
+	newNode := func(name string, instanceType string, cpu string, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"node.kubernetes.io/instance-type": instanceType}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+
+	newPod := func(name string, nodeName string, cpu string, memory string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				}},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	newProfile := func(name string, instanceType string, kilograms string, years string) *susqlv2.HardwareProfile {
+		profile := &susqlv2.HardwareProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: susqlv2.HardwareProfileSpec{
+				EmbodiedCarbonKilograms: resource.MustParse(kilograms),
+				LifetimeYears:           resource.MustParse(years),
+			},
+		}
+		if instanceType != "" {
+			profile.Spec.NodeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"node.kubernetes.io/instance-type": instanceType}}
+		}
+		return profile
+	}
+
+	It("should validate the hardware profiles", func() {
+		Expect(ValidateHardwareProfile(newProfile("a", "", "1000", "4"))).To(Succeed())
+		Expect(ValidateHardwareProfile(newProfile("a", "", "-1", "4"))).To(HaveOccurred())
+		Expect(ValidateHardwareProfile(newProfile("a", "", "1000", "0"))).To(HaveOccurred())
+	})
+
+	It("should allocate the share of the node reserved by a pod", func() {
+		node := newNode("node-1", "m5", "8", "32Gi")
+		profile := newProfile("m5", "m5", "1000", "4")
+		pod := newPod("a", "node-1", "2", "16Gi")
+
+		// The node has no GPU: the average of a quarter of the CPU and half of the memory
+		Expect(nodeShare(&pod, node, profile)).To(BeNumerically("~", 0.375, 1e-12))
+
+		profile.Spec.ResourceWeights = map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("1")}
+		Expect(nodeShare(&pod, node, profile)).To(BeNumerically("~", 0.25, 1e-12))
+
+		pod.Spec.InitContainers = []corev1.Container{{Name: "init", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}}}
+		Expect(nodeShare(&pod, node, profile)).To(BeNumerically("~", 0.5, 1e-12))
+	})
+
+	It("should amortize the embodied carbon of the nodes over their lifetime", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		reconciler := &LabelGroupReconciler{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newNode("node-1", "m5", "8", "32Gi"),
+				newNode("node-2", "c5", "8", "32Gi"),
+				newNode("node-3", "r5", "8", "32Gi"),
+				newProfile("z-default", "", "0", "1"),
+				newProfile("a-m5", "m5", "1000", "4"),
+				newProfile("c5", "c5", "2000", "4"),
+			).Build(),
+			Logger: logr.Discard(),
+		}
+
+		podsByNamespace := map[string][]corev1.Pod{"default": {
+			newPod("a", "node-1", "8", "32Gi"),
+			newPod("b", "node-2", "4", "16Gi"),
+			newPod("c", "node-3", "8", "32Gi"),
+		}}
+
+		year := time.Duration(secondsPerYear * float64(time.Second))
+		carbon, err := reconciler.embodiedCarbon(context.Background(), podsByNamespace, year)
+		Expect(err).NotTo(HaveOccurred())
+		// A quarter of the lifetime of node-1 and half of node-2 for a quarter of its lifetime
+		Expect(carbon).To(BeNumerically("~", 250e3+250e3, 1e-3))
+
+		carbon, err = reconciler.embodiedCarbon(context.Background(), podsByNamespace, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(carbon).To(BeZero())
+	})
+})
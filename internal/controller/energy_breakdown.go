@@ -0,0 +1,237 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	defaultBreakdownMaxEntries = 20 // Maximum number of breakdown entries of the groups that do not set one
+
+	breakdownNamespaceLabelName = "pod_namespace" // SusQL Prometheus label of the breakdown metrics holding the pod namespace
+	breakdownPodLabelName       = "pod"           // SusQL Prometheus label of the breakdown metrics holding the pod name
+	breakdownContainerLabelName = "container"     // SusQL Prometheus label of the breakdown metrics holding the container name
+)
+
+// breakdownIncrease is the energy of a breakdown entry since the previous aggregation, and the zone of its pod
+type breakdownIncrease struct {
+	zone   string
+	energy float64
+}
+
+// ValidateBreakdownSpec checks the breakdown of a label group, which is valid when it is not set
+func ValidateBreakdownSpec(breakdownSpec *susqlv2.BreakdownSpec) error {
+	if breakdownSpec == nil {
+		return nil
+	}
+
+	if breakdownSpec.Level != susqlv2.BreakdownLevelPod && breakdownSpec.Level != susqlv2.BreakdownLevelContainer {
+		return fmt.Errorf("invalid breakdown level '%s', expected '%s' or '%s'", breakdownSpec.Level, susqlv2.BreakdownLevelPod, susqlv2.BreakdownLevelContainer)
+	}
+	if breakdownSpec.MaxEntries < 0 {
+		return fmt.Errorf("invalid maximum number of breakdown entries %d", breakdownSpec.MaxEntries)
+	}
+
+	return nil
+}
+
+// Key of the breakdown entry of a container at a level, 'namespace/pod/container' with an empty container at the pod
+// level
+func breakdownKey(level susqlv2.BreakdownLevelType, namespace string, pod string, container string) string {
+	if level != susqlv2.BreakdownLevelContainer {
+		container = ""
+	}
+
+	return namespace + "/" + pod + "/" + container
+}
+
+// Entry of a breakdown key
+func breakdownEntry(key string) susqlv2.EnergyBreakdownEntry {
+	parts := strings.SplitN(key, "/", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+
+	return susqlv2.EnergyBreakdownEntry{Namespace: parts[0], Pod: parts[1], Container: parts[2]}
+}
+
+// breakdownKeys returns the breakdown keys of the containers of the pods, by container id
+func breakdownKeys(podsByNamespace map[string][]corev1.Pod, level susqlv2.BreakdownLevelType) map[string]string {
+	keys := make(map[string]string)
+
+	for namespace, pods := range podsByNamespace {
+		for _, pod := range pods {
+			for _, status := range podContainerStatuses(&pod) {
+				if containerId := containerIdOf(status); containerId != "" {
+					keys[containerId] = breakdownKey(level, namespace, pod.Name, status.Name)
+				}
+			}
+		}
+	}
+
+	return keys
+}
+
+// breakdownEnergy sums the energy of the containers by breakdown entry. The entry of a container is the one of its pod,
+// or the one recorded while it was active for the containers of deleted pods, or the "(other)" entry. The entries of
+// the active containers are recorded in the status.
+func breakdownEnergy(status *susqlv2.LabelGroupStatus, level susqlv2.BreakdownLevelType, currentKeys map[string]string,
+	containerEnergy map[string]float64, zoneOf func(containerId string) string) map[string]*breakdownIncrease {
+	keyOf := func(containerId string) string {
+		if key, found := currentKeys[containerId]; found {
+			return key
+		}
+		if key, found := status.BreakdownContainers[containerId]; found {
+			// The level may have changed since the key was recorded
+			entry := breakdownEntry(key)
+			return breakdownKey(level, entry.Namespace, entry.Pod, entry.Container)
+		}
+		return breakdownKey(level, "", susqlv2.BreakdownOther, "")
+	}
+
+	increases := make(map[string]*breakdownIncrease)
+	for containerId, energy := range containerEnergy {
+		key := keyOf(containerId)
+		if increases[key] == nil {
+			increases[key] = &breakdownIncrease{zone: zoneOf(containerId)}
+		}
+		increases[key].energy += energy
+	}
+
+	breakdownContainers := make(map[string]string)
+	for containerId := range status.ActiveContainerIds {
+		breakdownContainers[containerId] = keyOf(containerId)
+	}
+	status.BreakdownContainers = nil
+	if len(breakdownContainers) > 0 {
+		status.BreakdownContainers = breakdownContainers
+	}
+
+	return increases
+}
+
+// addBreakdown adds the energy of the breakdown entries since the previous aggregation to the breakdown in the status,
+// with its carbon: the energy scaled by the PUE at the carbon per joule accounted in the zone of the entry. The
+// entries beyond the maximum number are folded into the "(other)" entry, see limitBreakdown.
+func addBreakdown(status *susqlv2.LabelGroupStatus, breakdownSpec *susqlv2.BreakdownSpec, increases map[string]*breakdownIncrease,
+	pue float64, zoneEnergy map[string]float64, zoneCarbon map[string]float64, currentKeys map[string]string) {
+	entries := make(map[string]susqlv2.EnergyBreakdownEntry)
+	add := func(key string, energy float64, carbon float64) {
+		entry, found := entries[key]
+		if !found {
+			entry = breakdownEntry(key)
+		}
+		entry.EnergyJoules = *energyQuantity(quantityToFloat(&entry.EnergyJoules) + energy)
+		entry.CarbonGrams = *carbonQuantity(quantityToFloat(&entry.CarbonGrams) + carbon)
+		entries[key] = entry
+	}
+
+	// The entries of the containers of a pod are merged when the level changed to Pod
+	for _, entry := range status.EnergyBreakdown {
+		add(breakdownKey(breakdownSpec.Level, entry.Namespace, entry.Pod, entry.Container), quantityToFloat(&entry.EnergyJoules), quantityToFloat(&entry.CarbonGrams))
+	}
+
+	for key, increase := range increases {
+		carbon := 0.0
+		if zoneEnergy[increase.zone] > 0 {
+			carbon = increase.energy * pue * zoneCarbon[increase.zone] / zoneEnergy[increase.zone]
+		}
+		add(key, increase.energy, carbon)
+	}
+
+	current := make(map[string]bool)
+	for _, key := range currentKeys {
+		current[key] = true
+	}
+
+	maxEntries := int(breakdownSpec.MaxEntries)
+	if maxEntries == 0 {
+		maxEntries = defaultBreakdownMaxEntries
+	}
+
+	status.EnergyBreakdown = limitBreakdown(breakdownSpec.Level, entries, maxEntries, current)
+}
+
+// limitBreakdown keeps at most maxEntries entries besides the "(other)" entry, folding the entries of the pods that
+// are not current first, and then the entries of the least energy, into it. The entries are sorted by decreasing energy,
+// with the "(other)" entry last.
+func limitBreakdown(level susqlv2.BreakdownLevelType, entries map[string]susqlv2.EnergyBreakdownEntry, maxEntries int,
+	current map[string]bool) []susqlv2.EnergyBreakdownEntry {
+	otherKey := breakdownKey(level, "", susqlv2.BreakdownOther, "")
+	other, hasOther := entries[otherKey]
+	delete(entries, otherKey)
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	energyOf := func(key string) float64 {
+		entry := entries[key]
+		return quantityToFloat(&entry.EnergyJoules)
+	}
+	carbonOf := func(key string) float64 {
+		entry := entries[key]
+		return quantityToFloat(&entry.CarbonGrams)
+	}
+
+	if len(keys) > maxEntries {
+		// Fold the entries of the pods that are gone, then the ones of the least energy
+		sort.Slice(keys, func(i, j int) bool {
+			if current[keys[i]] != current[keys[j]] {
+				return !current[keys[i]]
+			}
+			if energyOf(keys[i]) != energyOf(keys[j]) {
+				return energyOf(keys[i]) < energyOf(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+
+		if !hasOther {
+			other = breakdownEntry(otherKey)
+			hasOther = true
+		}
+		for _, key := range keys[:len(keys)-maxEntries] {
+			other.EnergyJoules = *energyQuantity(quantityToFloat(&other.EnergyJoules) + energyOf(key))
+			other.CarbonGrams = *carbonQuantity(quantityToFloat(&other.CarbonGrams) + carbonOf(key))
+		}
+		keys = keys[len(keys)-maxEntries:]
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if energyOf(keys[i]) != energyOf(keys[j]) {
+			return energyOf(keys[i]) > energyOf(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	breakdown := make([]susqlv2.EnergyBreakdownEntry, 0, len(keys)+1)
+	for _, key := range keys {
+		breakdown = append(breakdown, entries[key])
+	}
+	if hasOther {
+		breakdown = append(breakdown, other)
+	}
+
+	return breakdown
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Energy Breakdown", func() {
+	newPod := func(namespace string, name string, containers map[string]string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		for containerName, containerId := range containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: containerName, ContainerID: "containerd://" + containerId})
+		}
+		return pod
+	}
+
+	newEntry := func(namespace string, pod string, container string, energy float64, carbon float64) susqlv2.EnergyBreakdownEntry {
+		return susqlv2.EnergyBreakdownEntry{
+			Namespace: namespace, Pod: pod, Container: container,
+			EnergyJoules: *energyQuantity(energy), CarbonGrams: *carbonQuantity(carbon),
+		}
+	}
+
+	energyOf := func(entry susqlv2.EnergyBreakdownEntry) float64 {
+		return quantityToFloat(&entry.EnergyJoules)
+	}
+
+	It("should validate the breakdown", func() {
+		Expect(ValidateBreakdownSpec(nil)).To(Succeed())
+		Expect(ValidateBreakdownSpec(&susqlv2.BreakdownSpec{Level: susqlv2.BreakdownLevelContainer, MaxEntries: 5})).To(Succeed())
+		Expect(ValidateBreakdownSpec(&susqlv2.BreakdownSpec{Level: "Node"})).NotTo(Succeed())
+		Expect(ValidateBreakdownSpec(&susqlv2.BreakdownSpec{Level: susqlv2.BreakdownLevelPod, MaxEntries: -1})).NotTo(Succeed())
+	})
+
+	It("should key the containers by pod or by container", func() {
+		pods := map[string][]corev1.Pod{
+			"ml":  {newPod("ml", "train", map[string]string{"main": "c1", "sidecar": "c2"})},
+			"web": {newPod("web", "front", map[string]string{"nginx": "c3"})},
+		}
+
+		Expect(breakdownKeys(pods, susqlv2.BreakdownLevelContainer)).To(Equal(map[string]string{
+			"c1": "ml/train/main",
+			"c2": "ml/train/sidecar",
+			"c3": "web/front/nginx",
+		}))
+		Expect(breakdownKeys(pods, susqlv2.BreakdownLevelPod)).To(Equal(map[string]string{
+			"c1": "ml/train/",
+			"c2": "ml/train/",
+			"c3": "web/front/",
+		}))
+		Expect(breakdownEntry("ml/train/main")).To(Equal(susqlv2.EnergyBreakdownEntry{Namespace: "ml", Pod: "train", Container: "main"}))
+	})
+
+	It("should attribute the energy of deleted pods to their recorded entries", func() {
+		status := &susqlv2.LabelGroupStatus{
+			ActiveContainerIds:  map[string]float64{"c1": 10, "c2": 20},
+			BreakdownContainers: map[string]string{"c2": "ml/done/main", "gone": "ml/old/main"},
+		}
+		zones := map[string]string{"c1": "DE", "c2": "FR"}
+
+		increases := breakdownEnergy(status, susqlv2.BreakdownLevelPod, map[string]string{"c1": "ml/train/"},
+			map[string]float64{"c1": 1, "c2": 2, "gone": 4, "unknown": 8}, func(containerId string) string { return zones[containerId] })
+
+		Expect(increases).To(HaveLen(4))
+		Expect(*increases["ml/train/"]).To(Equal(breakdownIncrease{zone: "DE", energy: 1}))
+		Expect(*increases["ml/done/"]).To(Equal(breakdownIncrease{zone: "FR", energy: 2}))
+		Expect(increases["ml/old/"].energy).To(Equal(4.0))
+		Expect(increases["/"+susqlv2.BreakdownOther+"/"].energy).To(Equal(8.0))
+		Expect(status.BreakdownContainers).To(Equal(map[string]string{"c1": "ml/train/", "c2": "ml/done/"}))
+	})
+
+	It("should add the energy and the carbon of the entries", func() {
+		status := &susqlv2.LabelGroupStatus{EnergyBreakdown: []susqlv2.EnergyBreakdownEntry{newEntry("ml", "train", "", 10, 1)}}
+		increases := map[string]*breakdownIncrease{
+			"ml/train/": {zone: "DE", energy: 5},
+			"ml/eval/":  {zone: "FR", energy: 20},
+		}
+
+		// 2 g/J in DE and 0.5 g/J in FR with a PUE of 1.5 on the facility energy
+		addBreakdown(status, &susqlv2.BreakdownSpec{Level: susqlv2.BreakdownLevelPod}, increases, 1.5,
+			map[string]float64{"DE": 7.5, "FR": 30}, map[string]float64{"DE": 15, "FR": 15}, map[string]string{"c1": "ml/train/"})
+
+		Expect(status.EnergyBreakdown).To(HaveLen(2))
+		Expect(status.EnergyBreakdown[0].Pod).To(Equal("eval"))
+		Expect(energyOf(status.EnergyBreakdown[0])).To(BeNumerically("~", 20, 1e-3))
+		Expect(quantityToFloat(&status.EnergyBreakdown[0].CarbonGrams)).To(BeNumerically("~", 15, 1e-6))
+		Expect(energyOf(status.EnergyBreakdown[1])).To(BeNumerically("~", 15, 1e-3))
+		Expect(quantityToFloat(&status.EnergyBreakdown[1].CarbonGrams)).To(BeNumerically("~", 16, 1e-6))
+	})
+
+	It("should merge the containers of a pod when the level changes to Pod", func() {
+		status := &susqlv2.LabelGroupStatus{EnergyBreakdown: []susqlv2.EnergyBreakdownEntry{
+			newEntry("ml", "train", "main", 10, 1),
+			newEntry("ml", "train", "sidecar", 2, 0.5),
+		}}
+
+		addBreakdown(status, &susqlv2.BreakdownSpec{Level: susqlv2.BreakdownLevelPod}, nil, 1, nil, nil, nil)
+
+		Expect(status.EnergyBreakdown).To(HaveLen(1))
+		Expect(status.EnergyBreakdown[0].Container).To(BeEmpty())
+		Expect(energyOf(status.EnergyBreakdown[0])).To(BeNumerically("~", 12, 1e-3))
+		Expect(quantityToFloat(&status.EnergyBreakdown[0].CarbonGrams)).To(BeNumerically("~", 1.5, 1e-6))
+	})
+
+	It("should fold the deleted pods and then the least energy into the other entry", func() {
+		entries := map[string]susqlv2.EnergyBreakdownEntry{
+			"ml/a/":                            newEntry("ml", "a", "", 100, 10),
+			"ml/b/":                            newEntry("ml", "b", "", 1, 0.1),
+			"ml/c/":                            newEntry("ml", "c", "", 50, 5),
+			"ml/d/":                            newEntry("ml", "d", "", 20, 2),
+			"/" + susqlv2.BreakdownOther + "/": newEntry("", susqlv2.BreakdownOther, "", 3, 0.3),
+		}
+		current := map[string]bool{"ml/b/": true, "ml/c/": true, "ml/d/": true}
+
+		breakdown := limitBreakdown(susqlv2.BreakdownLevelPod, entries, 2, current)
+
+		// The deleted pod a is folded first despite its energy, then b of the least energy
+		Expect(breakdown).To(HaveLen(3))
+		Expect(breakdown[0].Pod).To(Equal("c"))
+		Expect(breakdown[1].Pod).To(Equal("d"))
+		Expect(breakdown[2].Pod).To(Equal(susqlv2.BreakdownOther))
+		Expect(energyOf(breakdown[2])).To(BeNumerically("~", 104, 1e-3))
+		Expect(quantityToFloat(&breakdown[2].CarbonGrams)).To(BeNumerically("~", 10.4, 1e-6))
+
+		Expect(limitBreakdown(susqlv2.BreakdownLevelPod, map[string]susqlv2.EnergyBreakdownEntry{"ml/a/": newEntry("ml", "a", "", 1, 0)}, 2, nil)).To(HaveLen(1))
+	})
+
+	It("should reserve the labels of the breakdown metrics", func() {
+		_, err := ParseMetricLabelMap("app.kubernetes.io/name=pod")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseMetricLabelMap("team=container")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,250 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// PUEPeriod is the power usage effectiveness of the data center from a time of the day, until the next period
+type PUEPeriod struct {
+	Start time.Duration // Time of the day, in UTC
+	PUE   float64
+}
+
+// pueCache keeps the PUE returned by the PUE query, so that it is queried once per sampling period
+type pueCache struct {
+	mutex sync.Mutex
+	value float64
+	time  time.Time
+}
+
+// ParsePUESchedule parses a comma separated list of 'HH:MM=pue' pairs, the PUE of the data center from a time of
+// the day in UTC until the next one
+func ParsePUESchedule(schedule string) ([]PUEPeriod, error) {
+	var periods []PUEPeriod
+
+	for _, pair := range strings.Split(schedule, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		start, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid PUE schedule entry '%s', expected 'HH:MM=pue'", pair)
+		}
+
+		startTime, err := time.Parse("15:04", strings.TrimSpace(start))
+		if err != nil {
+			return nil, fmt.Errorf("invalid time of the PUE schedule entry '%s': %w", pair, err)
+		}
+
+		pue, err := ParsePUE(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PUE schedule entry '%s': %w", pair, err)
+		}
+
+		periods = append(periods, PUEPeriod{
+			Start: time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute,
+			PUE:   pue,
+		})
+	}
+
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start < periods[j].Start })
+	for i := 1; i < len(periods); i++ {
+		if periods[i].Start == periods[i-1].Start {
+			return nil, fmt.Errorf("duplicate PUE schedule time %s", periods[i].Start)
+		}
+	}
+
+	return periods, nil
+}
+
+// ParsePUE parses a power usage effectiveness, which is finite and at least 1
+func ParsePUE(value string) (float64, error) {
+	pue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(pue) || math.IsInf(pue, 0) {
+		return 0, fmt.Errorf("the PUE %s is not a finite number", value)
+	}
+	if pue < 1 {
+		return 0, fmt.Errorf("the PUE %s is lower than 1", value)
+	}
+
+	return pue, nil
+}
+
+// PUE of a schedule at a time, the PUE of the last period of the day applying until the first one
+func schedulePUE(schedule []PUEPeriod, at time.Time) (float64, bool) {
+	if len(schedule) == 0 {
+		return 0, false
+	}
+
+	at = at.UTC()
+	timeOfDay := at.Sub(time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC))
+
+	index := sort.Search(len(schedule), func(i int) bool { return schedule[i].Start > timeOfDay }) - 1
+	if index < 0 {
+		index = len(schedule) - 1
+	}
+
+	return schedule[index].PUE, true
+}
+
+// Power usage effectiveness of the data center at a time: the value of the PUE query, or of the PUE schedule, or the
+// static PUE
+func (r *LabelGroupReconciler) pue(ctx context.Context, at time.Time) float64 {
+	if r.PUEQuery != "" {
+		pue, err := r.queryPUE(ctx, at)
+		if err == nil {
+			return pue
+		}
+		r.Logger.V(0).Error(err, "[pue] Couldn't query the PUE, using the scheduled or static PUE.")
+	}
+
+	if pue, ok := schedulePUE(r.PUESchedule, at); ok {
+		return pue
+	}
+
+	if r.PUE < 1 {
+		return 1
+	}
+
+	return r.PUE
+}
+
+// Query the PUE from the Kepler Prometheus server, at most once per sampling period
+func (r *LabelGroupReconciler) queryPUE(ctx context.Context, at time.Time) (float64, error) {
+	r.pueCache.mutex.Lock()
+	defer r.pueCache.mutex.Unlock()
+
+	if !r.pueCache.time.IsZero() && at.Sub(r.pueCache.time) < r.SamplingRate {
+		return r.pueCache.value, nil
+	}
+
+	v1api, err := r.keplerAPI()
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := queryValue(ctx, v1api, r.PUEQuery, at)
+	if err != nil {
+		return 0, err
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("the PUE query '%s' returned %f, which is not a finite number", r.PUEQuery, value)
+	}
+	if value < 1 {
+		return 0, fmt.Errorf("the PUE query '%s' returned %f, which is lower than 1", r.PUEQuery, value)
+	}
+
+	r.pueCache.value = value
+	r.pueCache.time = at
+
+	return value, nil
+}
+
+// chargedInterval is the time since the previous aggregation over which the node overhead and the embodied carbon are
+// charged to the running pods of a label group. It is capped by the longest requeue delay of the aggregation, as the
+// pods running now may not have been running during a longer gap, e.g., while no pods matched, while the energy
+// couldn't be queried, or while the operator was down.
+func (r *LabelGroupReconciler) chargedInterval(status *susqlv2.LabelGroupStatus, now time.Time) time.Duration {
+	if status.LastAggregationTime == nil {
+		return 0
+	}
+
+	return min(now.Sub(status.LastAggregationTime.Time), max(r.SamplingRate, nopodDelay))
+}
+
+// podNodeNameField is the field index of the pods by the name of their node
+const podNodeNameField = "spec.nodeName"
+
+// podNodeName is the index function of podNodeNameField
+func podNodeName(object client.Object) []string {
+	pod, ok := object.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	return []string{pod.Spec.NodeName}
+}
+
+// nodeOverheadEnergy attributes the fixed overhead power of the nodes running pods of a label group during an interval,
+// shared evenly between the running pods of each node, and returns the energy of the group by carbon zone
+func (r *LabelGroupReconciler) nodeOverheadEnergy(ctx context.Context, podsByNamespace map[string][]corev1.Pod,
+	interval time.Duration, defaultZone string) (map[string]float64, error) {
+	overhead := make(map[string]float64)
+	if r.NodeOverheadWatts <= 0 || interval <= 0 {
+		return overhead, nil
+	}
+
+	groupPods := make(map[string]int)
+	for _, pods := range podsByNamespace {
+		for _, pod := range pods {
+			if pod.Spec.NodeName != "" && pod.Status.Phase == corev1.PodRunning {
+				groupPods[pod.Spec.NodeName]++
+			}
+		}
+	}
+	if len(groupPods) == 0 {
+		return overhead, nil
+	}
+
+	// The running pods of each node are listed from the cache by the node name index, instead of all the pods of
+	// the cluster
+	nodePods := make(map[string]int)
+	for nodeName := range groupPods {
+		podList := &corev1.PodList{}
+		if err := r.List(ctx, podList, client.MatchingFields{podNodeNameField: nodeName}); err != nil {
+			return overhead, err
+		}
+
+		for _, pod := range podList.Items {
+			if pod.Status.Phase == corev1.PodRunning {
+				nodePods[nodeName]++
+			}
+		}
+	}
+
+	for nodeName, count := range groupPods {
+		zone := defaultZone
+		if r.carbonZonesEnabled() {
+			zone = r.nodeZone(ctx, nodeName)
+		}
+
+		share := float64(count) / float64(max(nodePods[nodeName], count))
+		overhead[zone] += r.NodeOverheadWatts * interval.Seconds() * share
+	}
+
+	return overhead, nil
+}
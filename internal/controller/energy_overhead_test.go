@@ -0,0 +1,148 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Energy Overhead", func() {
+	newPod := func(name string, nodeName string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: nodeName},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	It("should parse the PUE schedule", func() {
+		schedule, err := ParsePUESchedule(" 18:00=1.3, 00:00=1.2,09:30=1.5,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schedule).To(Equal([]PUEPeriod{
+			{Start: 0, PUE: 1.2},
+			{Start: 9*time.Hour + 30*time.Minute, PUE: 1.5},
+			{Start: 18 * time.Hour, PUE: 1.3},
+		}))
+
+		_, err = ParsePUESchedule("09:00")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePUESchedule("25:00=1.2")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePUESchedule("09:00=0.9")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePUESchedule("09:00=1.2,09:00=1.3")
+		Expect(err).To(HaveOccurred())
+		_, err = ParsePUESchedule("09:00=NaN")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should parse a finite PUE of at least 1", func() {
+		Expect(ParsePUE("1.2")).To(Equal(1.2))
+		for _, value := range []string{"0.9", "NaN", "Inf", "+Inf", "-Inf", "1e400"} {
+			_, err := ParsePUE(value)
+			Expect(err).To(HaveOccurred(), value)
+		}
+	})
+
+	It("should apply the PUE of the period of the time of the day", func() {
+		schedule := []PUEPeriod{{Start: 9 * time.Hour, PUE: 1.5}, {Start: 18 * time.Hour, PUE: 1.3}}
+
+		pue, _ := schedulePUE(schedule, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+		Expect(pue).To(Equal(1.5))
+		pue, _ = schedulePUE(schedule, time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC))
+		Expect(pue).To(Equal(1.3))
+		// The last period of the day applies until the first one
+		pue, _ = schedulePUE(schedule, time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC))
+		Expect(pue).To(Equal(1.3))
+
+		_, ok := schedulePUE(nil, time.Now())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should fall back to the PUE schedule and the static PUE", func() {
+		reconciler := &LabelGroupReconciler{PUE: 1.4, Logger: logr.Discard()}
+		at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		Expect(reconciler.pue(context.Background(), at)).To(Equal(1.4))
+
+		reconciler.PUESchedule = []PUEPeriod{{Start: 0, PUE: 1.1}}
+		Expect(reconciler.pue(context.Background(), at)).To(Equal(1.1))
+
+		// The PUE query fails without a Kepler Prometheus server
+		reconciler.PUEQuery = "datacenter_pue"
+		reconciler.KeplerPrometheusUrl = "http://127.0.0.1:1"
+		Expect(reconciler.pue(context.Background(), at)).To(Equal(1.1))
+
+		Expect((&LabelGroupReconciler{}).pue(context.Background(), at)).To(Equal(1.0))
+	})
+
+	It("should share the overhead of the nodes between their running pods", func() {
+		reconciler := &LabelGroupReconciler{
+			Client: fake.NewClientBuilder().WithIndex(&corev1.Pod{}, podNodeNameField, podNodeName).WithObjects(
+				newPod("a", "node-1", corev1.PodRunning),
+				newPod("b", "node-1", corev1.PodRunning),
+				newPod("c", "node-1", corev1.PodRunning),
+				newPod("d", "node-1", corev1.PodRunning),
+				newPod("e", "node-1", corev1.PodSucceeded),
+				newPod("f", "node-2", corev1.PodRunning),
+			).Build(),
+			NodeOverheadWatts: 20,
+			Logger:            logr.Discard(),
+		}
+
+		podsByNamespace := map[string][]corev1.Pod{"default": {
+			*newPod("a", "node-1", corev1.PodRunning),
+			*newPod("e", "node-1", corev1.PodSucceeded),
+			*newPod("f", "node-2", corev1.PodRunning),
+		}}
+
+		overhead, err := reconciler.nodeOverheadEnergy(context.Background(), podsByNamespace, 10*time.Second, "JP-TK")
+		Expect(err).NotTo(HaveOccurred())
+		// A quarter of node-1 and the whole node-2
+		Expect(overhead).To(HaveKeyWithValue("JP-TK", BeNumerically("~", 50+200, 1e-9)))
+
+		overhead, err = reconciler.nodeOverheadEnergy(context.Background(), podsByNamespace, 0, "JP-TK")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(overhead).To(BeEmpty())
+	})
+
+	It("should cap the interval charged after a gap in the aggregation", func() {
+		reconciler := &LabelGroupReconciler{SamplingRate: 2 * time.Second}
+		now := time.Now()
+		status := &susqlv2.LabelGroupStatus{}
+		Expect(reconciler.chargedInterval(status, now)).To(BeZero())
+
+		status.LastAggregationTime = &metav1.Time{Time: now.Add(-2 * time.Second)}
+		Expect(reconciler.chargedInterval(status, now)).To(Equal(2 * time.Second))
+
+		// e.g., no pods matched, the energy couldn't be queried or the operator was down for an hour
+		status.LastAggregationTime = &metav1.Time{Time: now.Add(-time.Hour)}
+		Expect(reconciler.chargedInterval(status, now)).To(Equal(nopodDelay))
+
+		reconciler.SamplingRate = time.Minute
+		Expect(reconciler.chargedInterval(status, now)).To(Equal(time.Minute))
+	})
+})
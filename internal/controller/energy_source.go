@@ -0,0 +1,350 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// EnergySource reads the energy counters of containers from a Prometheus server
+type EnergySource interface {
+	// Energy counters in joules of the containers of the given pods of a namespace, keyed by container id
+	ContainerEnergy(ctx context.Context, namespace string, podNames []string) (map[string]float64, error)
+
+	// Last energy counters in joules of containers that are not running anymore, keyed by container id,
+	// looking back 'lookback' (Prometheus duration) for their last samples
+	LastContainerEnergy(ctx context.Context, containerIds []string, lookback string) (map[string]float64, error)
+}
+
+// IdleEnergySource is an energy source whose counters include the idle energy of the nodes attributed to the
+// containers, which it also reads on its own
+type IdleEnergySource interface {
+	EnergySource
+
+	// Energy counters and idle energy counters in joules of the containers of the given pods of a namespace, keyed by
+	// container id
+	ContainerEnergyByMode(ctx context.Context, namespace string, podNames []string) (map[string]float64, map[string]float64, error)
+
+	// Last energy counters and idle energy counters in joules of containers that are not running anymore, keyed by
+	// container id, looking back 'lookback' (Prometheus duration) for their last samples
+	LastContainerEnergyByMode(ctx context.Context, containerIds []string, lookback string) (map[string]float64, map[string]float64, error)
+}
+
+// ValidateEnergySource checks that an energy source can be used
+func ValidateEnergySource(spec *susqlv2.EnergySource) error {
+	if spec == nil {
+		return nil
+	}
+
+	switch spec.Type {
+	case susqlv2.EnergySourceKepler, "":
+		return nil
+
+	case susqlv2.EnergySourcePromQL:
+		if spec.Query == "" {
+			return fmt.Errorf("the query of the %s energy source is not set", spec.Type)
+		}
+		_, _, err := parseEnergyQueryTemplates(spec)
+		return err
+
+	default:
+		return fmt.Errorf("unknown energy source type '%s'", spec.Type)
+	}
+}
+
+// ValidateGroupEnergySource checks the energy source of a label group. PromQL energy sources are rejected, as their
+// queries would be sent with the credentials of the operator: they can only be configured for the operator.
+func ValidateGroupEnergySource(spec *susqlv2.EnergySource) error {
+	if spec != nil && spec.Type == susqlv2.EnergySourcePromQL {
+		return fmt.Errorf("the %s energy source can only be configured for the operator with ENERGY-SOURCE", spec.Type)
+	}
+
+	return ValidateEnergySource(spec)
+}
+
+// Create the energy source of a LabelGroup, falling back to the energy source of the operator
+func (r *LabelGroupReconciler) newEnergySource(spec *susqlv2.EnergySource) (EnergySource, error) {
+	if spec == nil {
+		spec = &r.EnergySource
+	} else if err := ValidateGroupEnergySource(spec); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateEnergySource(spec); err != nil {
+		return nil, err
+	}
+
+	querier := &containerEnergyQuerier{api: r.keplerAPI, url: r.KeplerPrometheusUrl, logger: r.Logger}
+
+	switch spec.Type {
+	case susqlv2.EnergySourceKepler, "":
+		metricName := spec.MetricName
+		if metricName == "" {
+			metricName = r.KeplerMetricName
+		}
+		return &KeplerEnergySource{querier: querier, metricName: metricName}, nil
+
+	case susqlv2.EnergySourcePromQL:
+		query, lastValueQuery, err := parseEnergyQueryTemplates(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &PromQLEnergySource{querier: querier, query: query, lastValueQuery: lastValueQuery}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown energy source type '%s'", spec.Type)
+	}
+}
+
+// KeplerEnergySource reads the kepler_container_joules_total counters of Kepler, adding the dynamic and idle energy,
+// and reads the idle energy on its own
+type KeplerEnergySource struct {
+	querier    *containerEnergyQuerier
+	metricName string
+}
+
+const keplerIdleMode = "idle" // Value of the mode label of the idle energy of the Kepler counters
+
+func (k *KeplerEnergySource) ContainerEnergy(ctx context.Context, namespace string, podNames []string) (map[string]float64, error) {
+	energy, _, err := k.ContainerEnergyByMode(ctx, namespace, podNames)
+	return energy, err
+}
+
+func (k *KeplerEnergySource) LastContainerEnergy(ctx context.Context, containerIds []string, lookback string) (map[string]float64, error) {
+	energy, _, err := k.LastContainerEnergyByMode(ctx, containerIds, lookback)
+	return energy, err
+}
+
+func (k *KeplerEnergySource) ContainerEnergyByMode(ctx context.Context, namespace string, podNames []string) (map[string]float64, map[string]float64, error) {
+	// Query the pods in batches matching their names with a regular expression, summing the dynamic and the idle
+	// energy of each container on their own
+	queries, err := planQueries(podNames, func(batch []string) (string, error) {
+		return fmt.Sprintf("sum by (container_id, mode) (%s{container_namespace=%s,pod_name=~%s,mode=~\"dynamic|idle\"})",
+			k.metricName, strconv.Quote(namespace), promQLRegex(batch)), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := runQueries(ctx, queries, k.querier.queryByMode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	energy, idleEnergy := splitEnergyModes(values)
+	return energy, idleEnergy, nil
+}
+
+func (k *KeplerEnergySource) LastContainerEnergyByMode(ctx context.Context, containerIds []string, lookback string) (map[string]float64, map[string]float64, error) {
+	queries, err := planQueries(containerIds, func(batch []string) (string, error) {
+		return fmt.Sprintf("sum by (container_id, mode) (last_over_time(%s{container_id=~%s,mode=~\"dynamic|idle\"}[%s]))",
+			k.metricName, promQLRegex(batch), lookback), nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := runQueries(ctx, queries, k.querier.queryByMode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	energy, idleEnergy := splitEnergyModes(values)
+	return energy, idleEnergy, nil
+}
+
+// splitEnergyModes sums the values of queryByMode by container id, and returns these sums along with the values of
+// the idle mode, which are 0 for the containers without idle energy
+func splitEnergyModes(values map[string]float64) (map[string]float64, map[string]float64) {
+	energy := make(map[string]float64, len(values))
+	idleEnergy := make(map[string]float64, len(values))
+
+	for key, value := range values {
+		containerId, mode, _ := strings.Cut(key, "/")
+		var idle float64
+		if mode == keplerIdleMode {
+			idle = value
+		}
+		energy[containerId] += value
+		idleEnergy[containerId] += idle
+	}
+
+	return energy, idleEnergy
+}
+
+// PromQLEnergySource reads the energy counters returned by user provided PromQL query templates
+type PromQLEnergySource struct {
+	querier        *containerEnergyQuerier
+	query          *template.Template
+	lastValueQuery *template.Template
+}
+
+// Values given to the query templates of the PromQL energy source
+type energyQueryData struct {
+	Namespace         string
+	Pods              []string
+	PodsRegex         string
+	ContainerIds      []string
+	ContainerIdsRegex string
+	Lookback          string
+}
+
+func (p *PromQLEnergySource) ContainerEnergy(ctx context.Context, namespace string, podNames []string) (map[string]float64, error) {
+	queries, err := planQueries(podNames, func(batch []string) (string, error) {
+		return executeEnergyQueryTemplate(p.query, energyQueryData{
+			Namespace: namespace,
+			Pods:      batch,
+			PodsRegex: promQLRegex(batch),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runQueries(ctx, queries, p.querier.query)
+}
+
+func (p *PromQLEnergySource) LastContainerEnergy(ctx context.Context, containerIds []string, lookback string) (map[string]float64, error) {
+	if p.lastValueQuery == nil {
+		return make(map[string]float64), nil
+	}
+
+	queries, err := planQueries(containerIds, func(batch []string) (string, error) {
+		return executeEnergyQueryTemplate(p.lastValueQuery, energyQueryData{
+			ContainerIds:      batch,
+			ContainerIdsRegex: promQLRegex(batch),
+			Lookback:          lookback,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runQueries(ctx, queries, p.querier.query)
+}
+
+func parseEnergyQueryTemplates(spec *susqlv2.EnergySource) (*template.Template, *template.Template, error) {
+	query, err := template.New("query").Option("missingkey=error").Parse(spec.Query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid energy query template: %w", err)
+	}
+
+	if spec.LastValueQuery == "" {
+		return query, nil, nil
+	}
+
+	lastValueQuery, err := template.New("lastValueQuery").Option("missingkey=error").Parse(spec.LastValueQuery)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid last value energy query template: %w", err)
+	}
+
+	return query, lastValueQuery, nil
+}
+
+func executeEnergyQueryTemplate(queryTemplate *template.Template, data energyQueryData) (string, error) {
+	var queryBuilder strings.Builder
+
+	if err := queryTemplate.Execute(&queryBuilder, data); err != nil {
+		return "", fmt.Errorf("couldn't execute the %s template: %w", queryTemplate.Name(), err)
+	}
+
+	return queryBuilder.String(), nil
+}
+
+// promQLRegex returns a quoted PromQL string with a regular expression matching exactly the given values
+func promQLRegex(values []string) string {
+	quotedValues := make([]string, len(values))
+	for i, value := range values {
+		quotedValues[i] = regexp.QuoteMeta(value)
+	}
+
+	return strconv.Quote(strings.Join(quotedValues, "|"))
+}
+
+// containerEnergyQuerier runs the queries of the energy sources, whose results are identified by the container_id label
+type containerEnergyQuerier struct {
+	api    func() (v1.API, error)
+	url    string
+	logger logr.Logger
+}
+
+func (q *containerEnergyQuerier) query(ctx context.Context, queryString string) (map[string]float64, error) {
+	return q.queryBy(ctx, queryString, func(metric model.Metric) string {
+		return string(metric["container_id"])
+	})
+}
+
+// queryByMode runs a query whose results are also identified by the mode label of Kepler, keyed by
+// 'container_id/mode'
+func (q *containerEnergyQuerier) queryByMode(ctx context.Context, queryString string) (map[string]float64, error) {
+	return q.queryBy(ctx, queryString, func(metric model.Metric) string {
+		return string(metric["container_id"]) + "/" + string(metric["mode"])
+	})
+}
+
+func (q *containerEnergyQuerier) queryBy(ctx context.Context, queryString string, keyOf func(metric model.Metric) string) (map[string]float64, error) {
+	v1api, err := q.api()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create HTTP client: %w (URL: %s)", err, q.url)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	results, warnings, err := v1api.Query(queryCtx, queryString, time.Now(), v1.WithTimeout(0*time.Second))
+
+	q.logger.V(5).Info(fmt.Sprintf("[containerEnergyQuerier] Query: %s", queryString)) // trace
+
+	if err != nil {
+		q.logger.V(0).Error(err, "[containerEnergyQuerier] Querying Prometheus didn't work.\n"+
+			fmt.Sprintf("\tURL: %s\n", q.url)+
+			fmt.Sprintf("\tqueryString: %s\n", queryString))
+		return nil, err
+	}
+
+	if len(warnings) > 0 {
+		q.logger.V(0).Info(fmt.Sprintf("WARNING [containerEnergyQuerier] %v\n", warnings) +
+			fmt.Sprintf("\tURL: %s\n", q.url) +
+			fmt.Sprintf("\tqueryString: %s", queryString))
+	}
+
+	vector, ok := results.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected result type %s of query %s", results.Type(), queryString)
+	}
+
+	metricValues := make(map[string]float64, len(vector))
+
+	for _, result := range vector {
+		q.logger.V(5).Info(fmt.Sprintf("[containerEnergyQuerier] Series %s value is %f.", keyOf(result.Metric), float64(result.Value))) // trace
+		metricValues[keyOf(result.Metric)] += float64(result.Value)
+	}
+
+	return metricValues, nil
+}
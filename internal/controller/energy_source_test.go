@@ -0,0 +1,156 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// Fake Prometheus server recording the queries it receives and answering them with a vector
+func newFakePrometheus(queries *[]string, vector string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		Expect(req.ParseForm()).To(Succeed())
+		*queries = append(*queries, req.Form.Get("query"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":` + vector + `}}`))
+	}))
+}
+
+var _ = Describe("Energy Sources", func() {
+	var queries []string
+	var server *httptest.Server
+	var reconciler *LabelGroupReconciler
+
+	BeforeEach(func() {
+		queries = nil
+		server = newFakePrometheus(&queries, `[
+			{"metric":{"container_id":"a"},"value":[1700000000,"10.5"]},
+			{"metric":{"container_id":"b"},"value":[1700000000,"4"]}
+		]`)
+		reconciler = &LabelGroupReconciler{
+			KeplerPrometheusUrl: server.URL,
+			KeplerMetricName:    "kepler_container_joules_total",
+			Logger:              logr.Discard(),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("When validating an energy source", func() {
+		It("should accept the Kepler energy source", func() {
+			Expect(ValidateEnergySource(&susqlv2.EnergySource{Type: susqlv2.EnergySourceKepler})).To(Succeed())
+			Expect(ValidateEnergySource(nil)).To(Succeed())
+		})
+
+		It("should reject PromQL energy sources without a valid query", func() {
+			Expect(ValidateEnergySource(&susqlv2.EnergySource{Type: susqlv2.EnergySourcePromQL})).NotTo(Succeed())
+			Expect(ValidateEnergySource(&susqlv2.EnergySource{Type: susqlv2.EnergySourcePromQL, Query: "{{.Pods"})).NotTo(Succeed())
+			Expect(ValidateEnergySource(&susqlv2.EnergySource{Type: "Unknown"})).NotTo(Succeed())
+		})
+
+		It("should reject the PromQL energy sources of the label groups", func() {
+			spec := &susqlv2.EnergySource{Type: susqlv2.EnergySourcePromQL, Query: `my_energy_joules{pod=~{{.PodsRegex}}}`}
+			Expect(ValidateEnergySource(spec)).To(Succeed())
+			Expect(ValidateGroupEnergySource(spec)).NotTo(Succeed())
+			Expect(ValidateGroupEnergySource(&susqlv2.EnergySource{Type: susqlv2.EnergySourceKepler, MetricName: "kepler_container_joules"})).To(Succeed())
+			Expect(ValidateGroupEnergySource(nil)).To(Succeed())
+
+			_, err := reconciler.newEnergySource(spec)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When reading the energy from Kepler", func() {
+		It("should key the energy counters by container id", func() {
+			energySource, err := reconciler.newEnergySource(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			values, err := energySource.ContainerEnergy(context.Background(), "default", []string{"pod-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]float64{"a": 10.5, "b": 4}))
+			Expect(queries).To(HaveLen(1))
+			Expect(queries[0]).To(Equal(`sum by (container_id, mode) (kepler_container_joules_total{container_namespace="default",pod_name=~"pod-1",mode=~"dynamic|idle"})`))
+		})
+
+		It("should read the idle energy counters on their own", func() {
+			server.Close()
+			server = newFakePrometheus(&queries, `[
+				{"metric":{"container_id":"a","mode":"dynamic"},"value":[1700000000,"10.5"]},
+				{"metric":{"container_id":"a","mode":"idle"},"value":[1700000000,"2"]},
+				{"metric":{"container_id":"b","mode":"dynamic"},"value":[1700000000,"4"]}
+			]`)
+			reconciler.KeplerPrometheusUrl = server.URL
+
+			energySource, err := reconciler.newEnergySource(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			values, idleValues, err := energySource.(IdleEnergySource).LastContainerEnergyByMode(context.Background(), []string{"a", "b"}, "5m")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(Equal(map[string]float64{"a": 12.5, "b": 4}))
+			Expect(idleValues).To(Equal(map[string]float64{"a": 2, "b": 0}))
+			Expect(queries).To(Equal([]string{
+				`sum by (container_id, mode) (last_over_time(kepler_container_joules_total{container_id=~"a|b",mode=~"dynamic|idle"}[5m]))`,
+			}))
+		})
+	})
+
+	Context("When reading the energy with PromQL templates", func() {
+		It("should render the templates with the pods and containers", func() {
+			reconciler.EnergySource = susqlv2.EnergySource{
+				Type:           susqlv2.EnergySourcePromQL,
+				Query:          `sum by (container_id) (scaph_process_energy_joules{namespace="{{.Namespace}}",pod=~{{.PodsRegex}}})`,
+				LastValueQuery: `sum by (container_id) (last_over_time(scaph_process_energy_joules{container_id=~{{.ContainerIdsRegex}}}[{{.Lookback}}]))`,
+			}
+			energySource, err := reconciler.newEnergySource(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			values, err := energySource.ContainerEnergy(context.Background(), "default", []string{"pod-1", "pod.2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(HaveKeyWithValue("a", 10.5))
+
+			_, err = energySource.LastContainerEnergy(context.Background(), []string{"a"}, "5m")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(queries).To(Equal([]string{
+				`sum by (container_id) (scaph_process_energy_joules{namespace="default",pod=~"pod-1|pod\\.2"})`,
+				`sum by (container_id) (last_over_time(scaph_process_energy_joules{container_id=~"a"}[5m]))`,
+			}))
+		})
+
+		It("should not look for terminated containers without a last value query", func() {
+			reconciler.EnergySource = susqlv2.EnergySource{Type: susqlv2.EnergySourcePromQL, Query: `my_energy_joules{pod=~{{.PodsRegex}}}`}
+			energySource, err := reconciler.newEnergySource(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			values, err := energySource.LastContainerEnergy(context.Background(), []string{"a"}, "5m")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(values).To(BeEmpty())
+			Expect(queries).To(BeEmpty())
+		})
+	})
+})
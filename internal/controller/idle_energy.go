@@ -0,0 +1,100 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// ValidateIdleEnergy checks the idle energy attribution of a label group, Kepler when it is not set
+func ValidateIdleEnergy(attribution susqlv2.IdleEnergyAttributionType) error {
+	switch attribution {
+	case susqlv2.IdleEnergyKepler, susqlv2.IdleEnergyNone, "":
+		return nil
+
+	default:
+		return fmt.Errorf("invalid idle energy attribution '%s', expected '%s' or '%s'", attribution,
+			susqlv2.IdleEnergyKepler, susqlv2.IdleEnergyNone)
+	}
+}
+
+// readContainerEnergy reads the energy counters of the containers of the pods of a namespace, and their idle energy
+// counters, which are empty when the energy source does not separate the idle energy
+func readContainerEnergy(ctx context.Context, energySource EnergySource, namespace string, podNames []string) (map[string]float64, map[string]float64, error) {
+	if idleEnergySource, ok := energySource.(IdleEnergySource); ok {
+		return idleEnergySource.ContainerEnergyByMode(ctx, namespace, podNames)
+	}
+
+	energy, err := energySource.ContainerEnergy(ctx, namespace, podNames)
+	return energy, make(map[string]float64), err
+}
+
+// readLastContainerEnergy reads the last energy counters of terminated containers, and their last idle energy
+// counters, which are empty when the energy source does not separate the idle energy
+func readLastContainerEnergy(ctx context.Context, energySource EnergySource, containerIds []string, lookback string) (map[string]float64, map[string]float64, error) {
+	if idleEnergySource, ok := energySource.(IdleEnergySource); ok {
+		return idleEnergySource.LastContainerEnergyByMode(ctx, containerIds, lookback)
+	}
+
+	energy, err := energySource.LastContainerEnergy(ctx, containerIds, lookback)
+	return energy, make(map[string]float64), err
+}
+
+// accumulateIdleEnergy is accumulateContainerEnergy for the idle energy counters of the containers. It must be called
+// before the energy counters are accumulated: the containers that were active without an idle energy counter, e.g.,
+// before the idle energy was tracked, start being tracked without idle energy, as their energy is not new.
+func accumulateIdleEnergy(idleCounters map[string]float64, activeContainers map[string]float64, idleValues map[string]float64) map[string]float64 {
+	for containerId, value := range idleValues {
+		_, tracked := idleCounters[containerId]
+		if _, active := activeContainers[containerId]; active && !tracked {
+			idleCounters[containerId] = value
+		}
+	}
+
+	increases, _ := accumulateContainerEnergy(idleCounters, idleValues)
+
+	return increases
+}
+
+// attributeIdleEnergy splits the energy of each container since the previous sample into its dynamic energy and its
+// idle energy, and returns the energy of each container accounted for the group with the attribution, along with the
+// dynamic energy and the idle energy it includes.
+//
+// Kepler keeps the idle energy Kepler attributed to each container, and None drops it.
+func attributeIdleEnergy(attribution susqlv2.IdleEnergyAttributionType, containerEnergy map[string]float64, idleEnergy map[string]float64) (map[string]float64, float64, float64) {
+	dynamicEnergy := make(map[string]float64, len(containerEnergy))
+	var totalIdle float64
+
+	for containerId, energy := range containerEnergy {
+		// The idle energy is part of the energy, which counter resets could break
+		idle := min(max(idleEnergy[containerId], 0), energy)
+		dynamicEnergy[containerId] = energy - idle
+		totalIdle += idle
+	}
+
+	totalDynamic := sumValues(dynamicEnergy)
+
+	if attribution == susqlv2.IdleEnergyNone {
+		return dynamicEnergy, totalDynamic, 0
+	}
+
+	return maps.Clone(containerEnergy), totalDynamic, totalIdle
+}
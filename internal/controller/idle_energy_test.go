@@ -0,0 +1,89 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Idle Energy", func() {
+	// 10 J of energy of container a with 4 J of idle energy, and 3 J of energy of container b with 2 J of idle energy
+	containerEnergy := map[string]float64{"a": 10, "b": 3}
+	idleEnergy := map[string]float64{"a": 4, "b": 2}
+
+	It("should validate the idle energy attribution", func() {
+		Expect(ValidateIdleEnergy("")).To(Succeed())
+		Expect(ValidateIdleEnergy(susqlv2.IdleEnergyNone)).To(Succeed())
+		Expect(ValidateIdleEnergy("Proportional")).NotTo(Succeed())
+	})
+
+	It("should keep the idle energy Kepler attributed to each container", func() {
+		energy, dynamic, idle := attributeIdleEnergy(susqlv2.IdleEnergyKepler, containerEnergy, idleEnergy)
+		Expect(energy).To(Equal(containerEnergy))
+		Expect(dynamic).To(Equal(7.0))
+		Expect(idle).To(Equal(6.0))
+
+		energy, _, _ = attributeIdleEnergy("", containerEnergy, idleEnergy)
+		Expect(energy).To(Equal(containerEnergy))
+	})
+
+	It("should drop the idle energy", func() {
+		energy, dynamic, idle := attributeIdleEnergy(susqlv2.IdleEnergyNone, containerEnergy, idleEnergy)
+		Expect(energy).To(Equal(map[string]float64{"a": 6, "b": 1}))
+		Expect(dynamic).To(Equal(7.0))
+		Expect(idle).To(BeZero())
+	})
+
+	It("should never count more idle energy than energy", func() {
+		energy, dynamic, idle := attributeIdleEnergy(susqlv2.IdleEnergyNone, map[string]float64{"a": 1}, map[string]float64{"a": 5})
+		Expect(energy).To(Equal(map[string]float64{"a": 0}))
+		Expect(dynamic).To(BeZero())
+		Expect(idle).To(BeZero())
+	})
+
+	It("should start tracking the idle energy of the active containers without idle energy", func() {
+		idleCounters := map[string]float64{"a": 10}
+		activeContainers := map[string]float64{"a": 30, "b": 50}
+
+		increases := accumulateIdleEnergy(idleCounters, activeContainers, map[string]float64{"a": 12, "b": 20, "new": 3})
+
+		Expect(increases).To(Equal(map[string]float64{"a": 2, "b": 0, "new": 3}))
+		Expect(idleCounters).To(Equal(map[string]float64{"a": 12, "b": 20, "new": 3}))
+	})
+
+	It("should not read idle energy from the energy sources that do not separate it", func() {
+		var queries []string
+		server := newFakePrometheus(&queries, `[{"metric":{"container_id":"a"},"value":[1700000000,"10.5"]}]`)
+		defer server.Close()
+
+		reconciler := &LabelGroupReconciler{KeplerPrometheusUrl: server.URL, Logger: logr.Discard(),
+			EnergySource: susqlv2.EnergySource{Type: susqlv2.EnergySourcePromQL, Query: `my_energy_joules{pod=~{{.PodsRegex}}}`}}
+		energySource, err := reconciler.newEnergySource(nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		values, idleValues, err := readContainerEnergy(context.Background(), energySource, "default", []string{"pod-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(values).To(Equal(map[string]float64{"a": 10.5}))
+		Expect(idleValues).To(BeEmpty())
+	})
+})
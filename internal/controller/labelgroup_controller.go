@@ -19,55 +19,97 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"net/http"
 	coreruntime "runtime"
-	"strconv"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
-	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
 )
 
 // LabelGroupReconciler reconciles a LabelGroup object
 type LabelGroupReconciler struct {
 	client.Client
-	Scheme                        *runtime.Scheme
-	KeplerPrometheusUrl           string
-	KeplerMetricName              string
-	SusQLPrometheusDatabaseUrl    string
-	SusQLPrometheusMetricsUrl     string
-	SamplingRate                  time.Duration // Sampling rate for all LabelGroups
-	CarbonMethod                  string
-	CarbonIntensity               float64
-	CarbonIntensityUrl            string
-	CarbonIntensityTimeStamp      int64
-	CarbonIntensityErrorTimeStamp int64
-	CarbonLocation                string
-	CarbonQueryRate               int64
-	CarbonQueryFilter             string
-	CarbonQueryConv2J             float64
-	Logger                        logr.Logger
-	carbonMutex                   sync.RWMutex // Protects carbon intensity fields
+	Scheme                       *runtime.Scheme
+	KeplerPrometheusUrl          string
+	KeplerPrometheusRoundTripper http.RoundTripper // HTTP client configuration of the Kepler Prometheus server (TLS, authentication, headers, proxy)
+	KeplerMetricName             string
+	KeplerLookbackWindow         string               // Prometheus duration to look back for the last energy value of terminated containers
+	EnergySource                 susqlv2.EnergySource // Energy source of the LabelGroups that do not set one
+	SusQLPrometheusDatabaseUrl   string
+	SusQLPrometheusRoundTripper  http.RoundTripper // HTTP client configuration of the SusQL Prometheus database
+	SusQLPrometheusMetricsUrl    string
+	SamplingRate                 time.Duration // Sampling rate for all LabelGroups
+	CarbonMethod                 string
+	CarbonIntensityProvider      CarbonIntensityProvider // Queries the carbon intensity, nil with the static method
+	CarbonIntensity              float64                 // Initial carbon intensity, used until the provider is queried successfully
+	CarbonLocation               string                  // Carbon zone of the nodes that are not mapped to another zone
+	CarbonZoneLabel              string                  // Node label holding the carbon zone of the nodes, or the key of CarbonZoneMap
+	CarbonZoneMap                map[string]string       // Carbon zones of node names and of values of the CarbonZoneLabel node label
+	CarbonQueryRate              int64
+	CarbonCorrectionWindow       time.Duration                 // Time during which carbon is corrected with the finalized intensities of the provider
+	PUE                          float64                       // Static power usage effectiveness of the data center
+	PUESchedule                  []PUEPeriod                   // Power usage effectiveness of the data center by time of the day, replacing PUE
+	PUEQuery                     string                        // PromQL query returning the power usage effectiveness of the data center, replacing PUESchedule and PUE
+	NodeOverheadWatts            float64                       // Fixed overhead power of each node, shared between its running pods
+	SCIFunctionalUnits           map[string]*template.Template // PromQL query templates of the SCI functional units, by name
+	OperatorNamespace            string                        // Namespace of the operator, holding the PrometheusRules of the ClusterLabelGroup budgets
+	EnableBudgetEnforcement      bool                          // Take the enforcement actions of the budgets of the label groups
+	EnableWebhooks               bool                          // The pod admission webhook is served, which blocks the new pods of the budgets
+	APIReader                    client.Reader                 // Reads the workloads of the budget enforcement actions without caching them
+	StorageMigrated              <-chan struct{}               // Closed when the label groups stored as v1 are migrated, nil when there is nothing to wait for
+	Recorder                     record.EventRecorder          // Emits Events on changes of the LabelGroup conditions
+	MetricLabelMode              string                        // Layout of the labels of the exported SusQL metrics
+	MetricLabelMap               map[string]string             // LabelGroup Kubernetes labels exported as SusQL Prometheus labels
+	Logger                       logr.Logger
+	carbonMutex                  sync.RWMutex                         // Protects carbonSeries and carbonForecasts
+	carbonSeries                 map[string]*CarbonIntensitySeries    // Carbon intensities of the zones matched to the energy aggregation intervals
+	carbonForecasts              map[string]*CarbonIntensitySeries    // Last forecast carbon intensities of the zones, ingested by the refresher
+	carbonIntensity              atomic.Pointer[carbonIntensityState] // Carbon intensity of the default zone published by the refresher
+	carbonRefreshTrigger         chan struct{}                        // Wakes the carbon intensity refresher up
+	pueCache                     pueCache                             // Last value of PUEQuery
+	budgetRules                  sync.Map                             // Fingerprints of the budget PrometheusRules applied, by label group UID
 }
 
 const (
-	susqlEnergyMetricName = "susql_total_energy_joules"        // SusQL energy metric to query
-	susqlCarbonMetricName = "susql_total_carbon_dioxide_grams" // SusQL carbon metric to query
-	fixingDelay           = 15 * time.Second                   // Time to wait in the event the LabelGroup was badly constructed
-	nopodDelay            = 15 * time.Second                   // Time to wait in the event no pods are found
-	errorDelay            = 1 * time.Second                    // Time to wait when an error happens due to network connectivity issues
-	carbonRetryDelay      = 300                                // Number of seconds to wait for retry after carbon query failure
+	susqlEnergyMetricName         = "susql_total_energy_joules"                 // SusQL energy metric to query
+	susqlCarbonMetricName         = "susql_total_carbon_dioxide_grams"          // SusQL carbon metric to query
+	susqlDynamicEnergyMetricName  = "susql_total_dynamic_energy_joules"         // SusQL dynamic energy metric to query
+	susqlIdleEnergyMetricName     = "susql_total_idle_energy_joules"            // SusQL idle energy metric to query
+	susqlFacilityEnergyMetricName = "susql_total_facility_energy_joules"        // SusQL facility energy metric to query
+	susqlEmbodiedCarbonMetricName = "susql_total_embodied_carbon_dioxide_grams" // SusQL embodied carbon metric to query
+	susqlBudgetMetricName         = "susql_budget_utilization_ratio"            // SusQL budget utilization metric
+	fixingDelay                   = 15 * time.Second                            // Time to wait in the event the LabelGroup was badly constructed
+	nopodDelay                    = 15 * time.Second                            // Time to wait in the event no pods are found
+	errorDelay                    = 1 * time.Second                             // Time to wait when an error happens due to network connectivity issues
+
+	susqlKubernetesLabelPrefix        = "susql.label/"         // Prefix of the SusQL Kubernetes labels
+	susqlPrometheusNamespaceLabelName = "labelgroup_namespace" // SusQL Prometheus label holding the LabelGroup namespace
+	susqlPrometheusNameLabelName      = "labelgroup_name"      // SusQL Prometheus label holding the LabelGroup name
+)
+
+// Layouts of the labels of the exported SusQL metrics
+const (
+	MetricLabelModeLabelGroup = "labelgroup" // Series are identified by LabelGroup namespace/name and mapped labels
+	MetricLabelModeLegacy     = "legacy"     // Series are identified by the fixed susql_label_N labels
 )
 
 var (
-	susqlKubernetesLabelNames = []string{"susql.label/1", "susql.label/2", "susql.label/3", "susql.label/4", "susql.label/5", "susql.label/6"} // Names of the SusQL Kubernetes labels
-	susqlPrometheusLabelNames = []string{"susql_label_1", "susql_label_2", "susql_label_3", "susql_label_4", "susql_label_5", "susql_label_6"} // Names of the SusQL Prometheus labels
+	susqlLegacyPrometheusLabelNames = []string{"susql_label_1", "susql_label_2", "susql_label_3", "susql_label_4", "susql_label_5", "susql_label_6"} // Names of the legacy SusQL Prometheus labels
 )
 
 // +kubebuilder:rbac:groups=susql.ibm.com,resources=labelgroups,verbs=get;list;watch;create;update;patch;delete
@@ -75,6 +117,14 @@ var (
 // +kubebuilder:rbac:groups=susql.ibm.com,resources=labelgroups/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=susql.ibm.com,resources=hardwareprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,namespace=system,resources=secrets,verbs=get
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheuses/api,verbs=get;create;update
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -89,8 +139,12 @@ var (
 func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	_ = log.FromContext(ctx)
 
+	if err := r.waitForStorageMigration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Get LabelGroup object to process if it exists
-	labelGroup := &susqlv1.LabelGroup{}
+	labelGroup := &susqlv2.LabelGroup{}
 
 	// Get deep copy of LabelGroup object in reconciler cache
 	err := r.Get(ctx, req.NamespacedName, labelGroup)
@@ -101,132 +155,95 @@ func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	r.Logger.V(1).Info(fmt.Sprintf("[Reconcile] Entered Reconcile() for LabelGroup '%s' in namespace '%s'.", labelGroup.Name, labelGroup.Namespace))
 
+	return r.reconcileLabelGroup(ctx, &namespacedLabelGroup{labelGroup})
+}
+
+// waitForStorageMigration waits until the label groups stored as v1 are migrated, since writing their status through
+// the v2 API before would drop their v1 totals
+func (r *LabelGroupReconciler) waitForStorageMigration(ctx context.Context) error {
+	if r.StorageMigrated == nil {
+		return nil
+	}
+
+	select {
+	case <-r.StorageMigrated:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Move a LabelGroup or a ClusterLabelGroup through the Initializing, Reloading and Aggregating phases
+func (r *LabelGroupReconciler) reconcileLabelGroup(ctx context.Context, labelGroup labelGroupObject) (ctrl.Result, error) {
+	status := labelGroup.groupStatus()
+
+	// Reverse the budget enforcement actions before the label group is deleted
+	if !labelGroup.object().GetDeletionTimestamp().IsZero() {
+		return r.finalizeBudgetEnforcement(ctx, labelGroup)
+	}
+
 	var m coreruntime.MemStats
 	coreruntime.ReadMemStats(&m)
 	r.Logger.V(5).Info(fmt.Sprintf("Memory: Alloc=%.2f MB  TotalAlloc=%.2f MB  Sys= %.2f MB  NumGC=%v", float32(m.Alloc)/1024.0/1024.0, float32(m.TotalAlloc)/1024.0/1024.0, float32(m.Sys)/1024.0/1024.0, m.NumGC))
 
-	// Check that the susql prometheus labels are created
-	if len(labelGroup.Status.PrometheusLabels) == 0 && labelGroup.Status.Phase != susqlv1.Initializing {
-		r.Logger.V(1).Info(fmt.Sprintf("[Reconcile] The SusQL prometheus labels for LabelGroup '%s' in namespace '%s' have not been created. Reinitializing this LabelGroup.", labelGroup.Name, labelGroup.Namespace))
+	// Check that the susql prometheus labels are created and match the current metric label layout
+	prometheusLabels, prometheusLabelsErr := r.prometheusLabelsForLabelGroup(labelGroup)
+	prometheusLabelsOutdated := prometheusLabelsErr == nil && len(status.PrometheusLabels) > 0 && !maps.Equal(prometheusLabels, status.PrometheusLabels)
 
-		labelGroup.Status.Phase = susqlv1.Initializing
+	if (len(status.PrometheusLabels) == 0 || prometheusLabelsOutdated) && status.Phase != susqlv2.Initializing {
+		r.Logger.V(1).Info(fmt.Sprintf("[Reconcile] The SusQL prometheus labels for %s have not been created or are outdated. Reinitializing it.", labelGroup.describe()))
 
-		if err := r.Status().Update(ctx, labelGroup); err != nil {
-			r.Logger.V(0).Error(err, "[Reconcile] Couldn't update the phase.")
+		if prometheusLabelsOutdated {
+			r.DeleteAggregatedMetricsForLabels(status.PrometheusLabels)
 		}
 
-		return ctrl.Result{}, nil
-	}
+		status.Phase = susqlv2.Initializing
+		r.setReadyCondition(labelGroup)
 
-	// Is it time to update the Carbon Intensity value?
-	// TODO: put this code only in Reloading and Aggregating cases
-	if r.CarbonMethod == "simpledynamic" {
-		r.carbonMutex.RLock()
-		currentEpoch := time.Now().Unix()
-		shouldUpdate := (currentEpoch-r.CarbonIntensityTimeStamp) > r.CarbonQueryRate && (currentEpoch-r.CarbonIntensityErrorTimeStamp) > carbonRetryDelay
-		r.carbonMutex.RUnlock()
-
-		if shouldUpdate {
-			newCarbonIntensity, err := querySimpleCarbonIntensity(r.CarbonIntensityUrl, r.CarbonLocation, r.CarbonQueryFilter, r.CarbonQueryConv2J)
-			r.carbonMutex.Lock()
-			if err == nil {
-				r.CarbonIntensity = newCarbonIntensity
-				r.CarbonIntensityTimeStamp = currentEpoch
-				r.CarbonIntensityErrorTimeStamp = 0
-				r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-simpledynamic] Obtained dynamic carbon intensity of %.10f.", newCarbonIntensity))
-			} else {
-				r.CarbonIntensityErrorTimeStamp = currentEpoch
-				r.Logger.V(0).Error(err, "[Reconcile-simpledynamic] Unable to query carbon intensity.")
-			}
-			r.carbonMutex.Unlock()
-		}
-	}
-	if r.CarbonMethod == "casdk" {
-		r.carbonMutex.RLock()
-		currentEpoch := time.Now().Unix()
-		shouldUpdate := (currentEpoch-r.CarbonIntensityTimeStamp) > r.CarbonQueryRate && (currentEpoch-r.CarbonIntensityErrorTimeStamp) > carbonRetryDelay
-		r.carbonMutex.RUnlock()
-
-		if shouldUpdate {
-			newCarbonIntensity, err := queryCarbonIntensity(r.CarbonIntensityUrl, r.CarbonLocation, r.CarbonQueryFilter, r.CarbonQueryConv2J)
-			r.carbonMutex.Lock()
-			if err == nil {
-				r.CarbonIntensity = newCarbonIntensity
-				r.CarbonIntensityTimeStamp = currentEpoch
-				r.CarbonIntensityErrorTimeStamp = 0
-				r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-casdk] Obtained dynamic carbon intensity of %.10f.", newCarbonIntensity))
-			} else {
-				r.CarbonIntensityErrorTimeStamp = currentEpoch
-				r.Logger.V(0).Error(err, "[Reconcile-casdk] Unable to query carbon intensity.")
-			}
-			r.carbonMutex.Unlock()
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile] Couldn't update the phase.")
 		}
+
+		return ctrl.Result{}, nil
 	}
 
 	// Decide what action to take based on the state of the labelGroup
-	switch labelGroup.Status.Phase {
-	case susqlv1.Initializing:
+	switch status.Phase {
+	case susqlv2.Initializing:
 		r.Logger.V(5).Info("[Reconcile-Initializing] Entered initializing case.")
-		if len(labelGroup.Spec.Labels) > len(susqlPrometheusLabelNames) {
-			r.Logger.V(0).Error(fmt.Errorf("[Reconcile-Initializing] The number of provided labels is greater than the maximum number of supported labels (e.g., up to %d labels).", len(susqlPrometheusLabelNames)), "")
-			return ctrl.Result{RequeueAfter: fixingDelay}, nil
+		if prometheusLabelsErr != nil {
+			// Changing the LabelGroup triggers a new reconcile, so there is no need to requeue
+			r.Logger.V(0).Error(prometheusLabelsErr, "[Reconcile-Initializing] Couldn't construct the SusQL prometheus labels.")
+			r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reasonInvalidLabels, prometheusLabelsErr.Error()))
+			return ctrl.Result{}, nil
 		}
 
-		susqlKubernetesLabels := make(map[string]string)
-
-		for ldx := 0; ldx < len(labelGroup.Spec.Labels); ldx++ {
-			susqlKubernetesLabels[susqlKubernetesLabelNames[ldx]] = labelGroup.Spec.Labels[ldx]
+		if reason, err := r.validateGroupSpec(labelGroup); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Initializing] The provided spec is not valid.")
+			r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reason, err.Error()))
+			return ctrl.Result{RequeueAfter: fixingDelay}, nil
 		}
 
-		susqlPrometheusLabels := make(map[string]string)
+		r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionTrue, reasonValid, "The labels and selectors are valid")
 
-		for ldx := 0; ldx < len(susqlKubernetesLabelNames); ldx++ {
-			if ldx < len(labelGroup.Spec.Labels) {
-				susqlPrometheusLabels[susqlPrometheusLabelNames[ldx]] = labelGroup.Spec.Labels[ldx]
-			} else {
-				susqlPrometheusLabels[susqlPrometheusLabelNames[ldx]] = ""
-			}
-		}
+		susqlKubernetesLabels := make(map[string]string)
 
-		// Create energy query string
-		var susqlPrometheusEnergyQuery string
-		susqlPrometheusEnergyQuery = susqlEnergyMetricName
-		susqlPrometheusEnergyQuery += "{"
-		for ldx := 0; ldx < len(susqlKubernetesLabelNames); ldx++ {
-			if ldx < len(labelGroup.Spec.Labels) {
-				susqlPrometheusEnergyQuery += fmt.Sprintf("%s=\"%s\"", susqlPrometheusLabelNames[ldx], labelGroup.Spec.Labels[ldx])
-			} else {
-				susqlPrometheusEnergyQuery += fmt.Sprintf("%s=\"\"", susqlPrometheusLabelNames[ldx])
-			}
-			if ldx < len(susqlKubernetesLabelNames)-1 {
-				susqlPrometheusEnergyQuery += ","
-			}
+		for ldx, label := range labelGroup.groupLabels() {
+			susqlKubernetesLabels[fmt.Sprintf("%s%d", susqlKubernetesLabelPrefix, ldx+1)] = label
 		}
-		susqlPrometheusEnergyQuery += "}"
 
-		// Create carbon query string
-		var susqlPrometheusCarbonQuery string
-		susqlPrometheusCarbonQuery = susqlCarbonMetricName
-		susqlPrometheusCarbonQuery += "{"
-		for ldx := 0; ldx < len(susqlKubernetesLabelNames); ldx++ {
-			if ldx < len(labelGroup.Spec.Labels) {
-				susqlPrometheusCarbonQuery += fmt.Sprintf("%s=\"%s\"", susqlPrometheusLabelNames[ldx], labelGroup.Spec.Labels[ldx])
-			} else {
-				susqlPrometheusCarbonQuery += fmt.Sprintf("%s=\"\"", susqlPrometheusLabelNames[ldx])
-			}
-			if ldx < len(susqlKubernetesLabelNames)-1 {
-				susqlPrometheusCarbonQuery += ","
-			}
-		}
-		susqlPrometheusCarbonQuery += "}"
+		// Create energy and carbon query strings
+		susqlPrometheusEnergyQuery := buildSusQLPrometheusQuery(susqlEnergyMetricName, prometheusLabels)
+		susqlPrometheusCarbonQuery := buildSusQLPrometheusQuery(susqlCarbonMetricName, prometheusLabels)
 
-		labelGroup.Status.KubernetesLabels = susqlKubernetesLabels
-		labelGroup.Status.PrometheusLabels = susqlPrometheusLabels
-		labelGroup.Status.SusQLPrometheusEnergyQuery = susqlPrometheusEnergyQuery
-		labelGroup.Status.SusQLPrometheusCarbonQuery = susqlPrometheusCarbonQuery
-		labelGroup.Status.Phase = susqlv1.Reloading
+		status.KubernetesLabels = susqlKubernetesLabels
+		status.PrometheusLabels = prometheusLabels
+		status.SusQLPrometheusEnergyQuery = susqlPrometheusEnergyQuery
+		status.SusQLPrometheusCarbonQuery = susqlPrometheusCarbonQuery
+		status.Phase = susqlv2.Reloading
+		r.setReadyCondition(labelGroup)
 
-		if err := r.Status().Update(ctx, labelGroup); err != nil {
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
 			r.Logger.V(0).Error(err, "[Reconcile-Initializing] Couldn't update status of the LabelGroup.")
 			return ctrl.Result{RequeueAfter: fixingDelay}, nil
 		}
@@ -234,32 +251,33 @@ func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// Requeue
 		return ctrl.Result{}, nil
 
-	case susqlv1.Reloading:
+	case susqlv2.Reloading:
 		r.Logger.V(5).Info("[Reconcile-Reloading] Entered reloading case.")
 		// Reload data from existing database
-		if !labelGroup.Spec.DisableUsingMostRecentValue {
-			totalEnergy, err := r.GetMostRecentValueWithContext(ctx, labelGroup.Status.SusQLPrometheusEnergyQuery)
-
-			if err != nil {
-				r.Logger.V(0).Error(err, "[Reconcile-Reloading] Couldn't retrieve most recent energy value.")
-				return ctrl.Result{RequeueAfter: fixingDelay}, nil
+		if !labelGroup.disableUsingMostRecentValue() {
+			for _, total := range aggregatedTotals {
+				value, err := r.GetMostRecentValueWithContext(ctx, buildSusQLPrometheusQuery(total.metricName, status.PrometheusLabels))
+
+				if err != nil {
+					r.Logger.V(0).Error(err, fmt.Sprintf("[Reconcile-Reloading] Couldn't retrieve most recent %s value.", total.name))
+					r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionPrometheusReachable, metav1.ConditionFalse, reasonSusQLQueryFailed,
+						fmt.Sprintf("Couldn't query the SusQL Prometheus database at '%s': %v", r.SusQLPrometheusDatabaseUrl, err)))
+					return ctrl.Result{RequeueAfter: fixingDelay}, nil
+				}
+
+				// Never go back from the value already accumulated in the status, since the most recent value
+				// in the database may be missing, e.g., when the SusQL prometheus labels have changed
+				field := total.field(status)
+				*field = total.quantity(max(value, quantityToFloat(*field)))
 			}
 
-			labelGroup.Status.TotalEnergy = fmt.Sprintf("%f", totalEnergy)
-
-			totalCarbon, err := r.GetMostRecentValueWithContext(ctx, labelGroup.Status.SusQLPrometheusCarbonQuery)
-
-			if err != nil {
-				r.Logger.V(0).Error(err, "[Reconcile-Reloading] Couldn't retrieve most recent carbon value.")
-				return ctrl.Result{RequeueAfter: fixingDelay}, nil
-			}
-
-			labelGroup.Status.TotalCarbon = fmt.Sprintf("%.10f", float64(totalCarbon))
+			r.setCondition(labelGroup, susqlv2.ConditionPrometheusReachable, metav1.ConditionTrue, reasonQuerySucceeded, "The Prometheus servers can be queried")
 		}
 
-		labelGroup.Status.Phase = susqlv1.Aggregating
+		status.Phase = susqlv2.Aggregating
+		r.setReadyCondition(labelGroup)
 
-		if err := r.Status().Update(ctx, labelGroup); err != nil {
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
 			r.Logger.V(0).Error(err, "[Reconcile-Reloading] Couldn't update status of the LabelGroup.")
 			return ctrl.Result{RequeueAfter: fixingDelay}, nil
 		}
@@ -267,94 +285,285 @@ func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// Requeue
 		return ctrl.Result{}, nil
 
-	case susqlv1.Aggregating:
+	case susqlv2.Aggregating:
 		r.Logger.V(5).Info("[Reconcile-Aggregating] Entered aggregating case.") // trace
 
-		// Get list of pods matching the LabelGroup and namespace
-		podsInNamespace, err := r.filterPodsInNamespace(ctx, labelGroup.Namespace, labelGroup.Status.KubernetesLabels)
+		// Get list of pods matching the LabelGroup, grouped by namespace
+		podsByNamespace, err := labelGroup.listPods(ctx, r)
 
-		if err != nil || len(podsInNamespace) == 0 {
-			r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] Unable to get podlist: Namespace: %s  LabelName: %s", labelGroup.Namespace, labelGroup.Name))
-			//			r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] KubernetesLabels: %#v", labelGroup.Status.KubernetesLabels))
+		if err != nil || countPods(podsByNamespace) == 0 {
+			r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] Unable to get podlist for %s", labelGroup.describe()))
 			if err != nil {
 				r.Logger.V(0).Error(err, "[Reconcile-Aggregating] ERROR: Unable to get pods for the labels provided due to this error.")
+				r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionFalse, reasonPodListFailed,
+					fmt.Sprintf("Couldn't list the pods: %v", err)))
+			} else {
+				changed := r.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionFalse, reasonNoPodsFound,
+					"No pods match the labels or selectors")
+
+				// Workloads scaled down by the budget enforcement have no pods, whose actions are reversed once the budget
+				// is no longer exceeded
+				if len(status.BudgetEnforcement) > 0 {
+					now := time.Now()
+					if err := r.reconcileBudgetEnforcement(ctx, labelGroup, nil, r.evaluateBudget(ctx, labelGroup, now), now); err != nil {
+						r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't enforce the budget.")
+					}
+					changed = true
+				}
+
+				r.updateConditions(ctx, labelGroup, changed)
 			}
 
 			return ctrl.Result{RequeueAfter: nopodDelay}, nil
 		}
 
-		// Aggregate Kepler measurements for these set of pods
-		metricValues, err := r.GetMetricValuesForPodNamesWithContext(ctx, r.KeplerMetricName, podsInNamespace, labelGroup.Namespace)
+		r.setCondition(labelGroup, susqlv2.ConditionPodsMatched, metav1.ConditionTrue, reasonPodsFound,
+			fmt.Sprintf("%d pods match in %d namespaces", countPods(podsByNamespace), len(podsByNamespace)))
+
+		if reason, err := r.validateGroupSpec(labelGroup); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] The provided spec is not valid.")
+			r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reason, err.Error()))
+			return ctrl.Result{RequeueAfter: fixingDelay}, nil
+		}
 
+		energySource, err := r.newEnergySource(labelGroup.energySource())
 		if err != nil {
-			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Querying Prometheus didn't work.")
-			return ctrl.Result{RequeueAfter: errorDelay}, nil
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Invalid energy source.")
+			r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reasonInvalidEnergySource, err.Error()))
+			return ctrl.Result{RequeueAfter: fixingDelay}, nil
 		}
 
+		carbonConfig, err := r.groupCarbonConfig(labelGroup.carbonSpec())
+		if err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Invalid carbon configuration.")
+			r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionFalse, reasonInvalidCarbon, err.Error()))
+			return ctrl.Result{RequeueAfter: fixingDelay}, nil
+		}
+
+		breakdownSpec := labelGroup.breakdownSpec()
+
+		r.setCondition(labelGroup, susqlv2.ConditionSpecValid, metav1.ConditionTrue, reasonValid, "The labels and selectors are valid")
+
+		// Aggregate the energy measurements for these set of pods, querying each namespace on its own
+		// so that pods with the same name in different namespaces are not conflated
+		metricValues := make(map[string]float64)
+		idleValues := make(map[string]float64)
+
+		for namespaceName, podNames := range podNamesByNamespace(podsByNamespace) {
+			namespaceMetricValues, namespaceIdleValues, err := readContainerEnergy(ctx, energySource, namespaceName, podNames)
+
+			if err != nil {
+				r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Querying Prometheus didn't work.")
+				r.updateConditions(ctx, labelGroup, r.setCondition(labelGroup, susqlv2.ConditionPrometheusReachable, metav1.ConditionFalse, reasonKeplerQueryFailed,
+					fmt.Sprintf("Couldn't query the Kepler Prometheus server at '%s': %v", r.KeplerPrometheusUrl, err)))
+				return ctrl.Result{RequeueAfter: errorDelay}, nil
+			}
+
+			maps.Copy(metricValues, namespaceMetricValues)
+			maps.Copy(idleValues, namespaceIdleValues)
+		}
+
+		r.setCondition(labelGroup, susqlv2.ConditionPrometheusReachable, metav1.ConditionTrue, reasonQuerySucceeded, "The Prometheus servers can be queried")
+
 		// Compute total energy
 		// 1) Get the current total energy from ETCD
-		var totalEnergy float64
+		var totalEnergy float64 = quantityToFloat(status.TotalEnergyJoules)
 
-		if value, err := strconv.ParseFloat(labelGroup.Status.TotalEnergy, 64); err == nil {
-			totalEnergy = value
-		} else {
-			totalEnergy = 0.0
+		if status.ActiveContainerIds == nil {
+			// First pass with this pod group
+			status.ActiveContainerIds = make(map[string]float64)
+		}
+		if status.ActiveContainerIdleCounters == nil {
+			status.ActiveContainerIdleCounters = make(map[string]float64)
 		}
 
-		var originalTotalEnergy float64 = totalEnergy
+		// 2) Credit the energy that terminated containers consumed since the previous sample, looking back
+		//    for their last Kepler counter value before forgetting them
+		var resets []string
+		containerEnergy := make(map[string]float64)
+		idleEnergy := make(map[string]float64)
 
-		if labelGroup.Status.ActiveContainerIds == nil {
-			// First pass with this pod group
-			labelGroup.Status.ActiveContainerIds = make(map[string]float64)
+		if vanished := vanishedContainers(status.ActiveContainerIds, metricValues); len(vanished) > 0 {
+			lastValues, lastIdleValues, err := readLastContainerEnergy(ctx, energySource, vanished, r.KeplerLookbackWindow)
+			if err != nil {
+				r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't query the last energy value of terminated containers.")
+			}
+
+			tailIncreases, tailResets := accumulateContainerTailEnergy(status.ActiveContainerIds, lastValues)
+			tailIdleIncreases, _ := accumulateContainerTailEnergy(status.ActiveContainerIdleCounters, lastIdleValues)
+			maps.Copy(containerEnergy, tailIncreases)
+			maps.Copy(idleEnergy, tailIdleIncreases)
+			r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] Tail energy of terminated containers %v: %f", vanished, sumValues(tailIncreases))) // trace
+			resets = append(resets, tailResets...)
 		}
 
-		// 2) Check if the active containers are still active by comparing them to the current ones
-		//    - In the set of new containers, remove all containers that are active
-		for containerId, oldValue := range labelGroup.Status.ActiveContainerIds {
-			if newValue, found := metricValues[containerId]; found {
-				totalEnergy += (newValue - oldValue)
-				labelGroup.Status.ActiveContainerIds[containerId] = newValue
-				delete(metricValues, containerId)
-			} else {
-				// Delete inactive container since it doesn't appear in queried containers
-				delete(labelGroup.Status.ActiveContainerIds, containerId)
+		// 3) Add the energy consumed by the active and new containers since the previous sample, treating
+		//    decreasing Kepler counters as resets, and update the list of active containers
+		maps.Copy(idleEnergy, accumulateIdleEnergy(status.ActiveContainerIdleCounters, status.ActiveContainerIds, idleValues))
+		increases, activeResets := accumulateContainerEnergy(status.ActiveContainerIds, metricValues)
+		maps.Copy(containerEnergy, increases)
+		resets = append(resets, activeResets...)
+		r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] ActiveContainerIds: %#v", status.ActiveContainerIds)) // trace
+
+		// 4) Report the counter resets
+		if len(resets) > 0 {
+			status.CounterResets += int64(len(resets))
+			r.Logger.V(2).Info(fmt.Sprintf("[Reconcile-Aggregating] Kepler counter reset for containers %v of %s.", resets, labelGroup.describe()))
+			if r.Recorder != nil {
+				r.Recorder.Event(labelGroup.object(), corev1.EventTypeWarning, reasonCounterReset,
+					fmt.Sprintf("Kepler energy counters decreased and were treated as reset for %d container(s): %s", len(resets), strings.Join(resets, ", ")))
 			}
 		}
-		//		r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] metricValues: %#v", metricValues))                               // trace
-		r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] ActiveContainerIds: %#v", labelGroup.Status.ActiveContainerIds)) // trace
 
-		// 3) Add the values of the remaining new containers to the total energy and update the list of active containers
-		for containerId, newValue := range metricValues {
-			totalEnergy += newValue
-			labelGroup.Status.ActiveContainerIds[containerId] = newValue
+		// Keep the idle energy of the containers with the idle energy attribution of the group
+		containerEnergy, dynamicEnergy, attributedIdleEnergy := attributeIdleEnergy(labelGroup.idleEnergy(), containerEnergy, idleEnergy)
+		totalEnergy += dynamicEnergy + attributedIdleEnergy
+		totalDynamicEnergy := quantityToFloat(status.TotalDynamicEnergyJoules) + dynamicEnergy
+		totalIdleEnergy := quantityToFloat(status.TotalIdleEnergyJoules) + attributedIdleEnergy
+		r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] Dynamic energy: %f, idle energy: %f", dynamicEnergy, attributedIdleEnergy)) // trace
+
+		// Split the energy by breakdown entry, and by the carbon zones of the nodes the containers ran on
+		currentZones := r.containerZones(ctx, podsByNamespace)
+		var breakdownIncreases map[string]*breakdownIncrease
+		var currentBreakdownKeys map[string]string
+
+		if breakdownSpec != nil {
+			currentBreakdownKeys = breakdownKeys(podsByNamespace, breakdownSpec.Level)
+			breakdownIncreases = breakdownEnergy(status, breakdownSpec.Level, currentBreakdownKeys, containerEnergy, func(containerId string) string {
+				return containerZone(status, currentZones, carbonConfig.location, containerId)
+			})
+		} else {
+			status.EnergyBreakdown = nil
+			status.BreakdownContainers = nil
 		}
 
-		// 4) Update ETCD with the values
-		labelGroup.Status.TotalEnergy = fmt.Sprintf("%.2f", totalEnergy)
+		zoneEnergy := energyByZone(status, containerEnergy, currentZones, carbonConfig.location)
 
-		var totalCarbon float64
+		now := time.Now()
+		from := now
+		if status.LastAggregationTime != nil {
+			from = status.LastAggregationTime.Time
+		}
 
-		if value, err := strconv.ParseFloat(labelGroup.Status.TotalCarbon, 64); err == nil {
-			totalCarbon = value
-		} else {
-			totalCarbon = 0.0
+		// 5) Attribute the fixed overhead of the nodes running the pods since the previous aggregation to the facility
+		//    energy, as the energy of the containers is only their dynamic and idle energy
+		chargedInterval := r.chargedInterval(status, now)
+		overhead, err := r.nodeOverheadEnergy(ctx, podsByNamespace, chargedInterval, carbonConfig.location)
+		if err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't attribute the overhead energy of the nodes.")
+		}
+		for zone, energy := range overhead {
+			zoneEnergy[zone] += energy
+		}
+
+		// 6) Scale the IT energy by the power usage effectiveness of the data center into the facility energy,
+		//    whose carbon is accounted
+		pue := r.pue(ctx, now)
+		totalFacilityEnergy := quantityToFloat(status.TotalFacilityEnergyJoules)
+		for zone := range zoneEnergy {
+			zoneEnergy[zone] *= pue
+			totalFacilityEnergy += zoneEnergy[zone]
+		}
+		r.Logger.V(5).Info(fmt.Sprintf("[Reconcile-Aggregating] PUE: %f, overhead energy: %#v", pue, overhead)) // trace
+
+		// 7) Allocate the embodied carbon of the hardware of the nodes by the resources the running pods reserved
+		embodiedCarbon, err := r.embodiedCarbon(ctx, podsByNamespace, chargedInterval)
+		if err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't allocate the embodied carbon of the nodes.")
+		}
+		totalEmbodiedCarbon := quantityToFloat(status.TotalEmbodiedCarbonGrams) + embodiedCarbon
+
+		// 8) Update ETCD with the values
+		status.TotalEnergyJoules = energyQuantity(totalEnergy)
+		status.TotalDynamicEnergyJoules = energyQuantity(totalDynamicEnergy)
+		status.TotalIdleEnergyJoules = energyQuantity(totalIdleEnergy)
+		status.TotalFacilityEnergyJoules = energyQuantity(totalFacilityEnergy)
+		status.TotalEmbodiedCarbonGrams = carbonQuantity(totalEmbodiedCarbon)
+
+		var totalCarbon float64 = quantityToFloat(status.TotalCarbonGrams)
+
+		// Match the energy consumed in each zone since the previous aggregation to the carbon intensities of
+		// its interval, and correct the carbon of the past periods whose intensity was finalized
+		ledgerStart := now.Add(-r.carbonCorrectionWindow())
+		seriesOf := r.carbonSeriesOf
+
+		if carbonConfig.static {
+			// The static intensity of the group applies to all the zones and is never corrected
+			ledgerStart = now
+			seriesOf = func(string) *CarbonIntensitySeries { return &CarbonIntensitySeries{} }
+		}
+
+		corrections, ledger := correctCarbon(seriesOf, status.CarbonLedger, carbonConfig.intensityFactor, ledgerStart)
+		for _, zone := range slices.Sorted(maps.Keys(corrections)) {
+			r.Logger.V(2).Info(fmt.Sprintf("[Reconcile-Aggregating] Corrected the carbon of %s in zone '%s' by %.10f g with finalized carbon intensities.",
+				labelGroup.describe(), zone, corrections[zone]))
+			totalCarbon += corrections[zone]
+			addZoneCarbon(status, zone, 0, corrections[zone])
+		}
+
+		zoneCarbon := make(map[string]float64)
+		for _, zone := range slices.Sorted(maps.Keys(zoneEnergy)) {
+			var carbon float64
+			carbon, ledger = accountCarbon(seriesOf(zone), zone, ledger, from, now, zoneEnergy[zone], carbonConfig.intensity, carbonConfig.intensityFactor, ledgerStart)
+			totalCarbon += carbon
+			zoneCarbon[zone] = carbon
+			addZoneCarbon(status, zone, zoneEnergy[zone], carbon)
+		}
+
+		if breakdownSpec != nil {
+			addBreakdown(status, breakdownSpec, breakdownIncreases, pue, zoneEnergy, zoneCarbon, currentBreakdownKeys)
+		}
+
+		status.TotalCarbonGrams = carbonQuantity(totalCarbon)
+		status.CarbonLedger = ledger
+		status.LastAggregationTime = &metav1.Time{Time: now}
+
+		// Compute the Software Carbon Intensity per functional unit over its window
+		if sciSpec := labelGroup.sciSpec(); sciSpec == nil {
+			status.SCI = nil
+		} else if sciDue(status, now) {
+			sci, err := r.computeSCI(ctx, sciSpec, labelGroup.object().GetNamespace(), status, now)
+			if err != nil {
+				r.Logger.V(2).Info(fmt.Sprintf("[Reconcile-Aggregating] Couldn't compute the SCI of %s: %v", labelGroup.describe(), err))
+			} else {
+				status.SCI = sci
+			}
 		}
 
-		// Read carbon intensity with lock protection
-		r.carbonMutex.RLock()
-		currentCarbonIntensity := r.CarbonIntensity
-		r.carbonMutex.RUnlock()
+		// Evaluate the usage of the budget in its period
+		budgetUtilizations := r.evaluateBudget(ctx, labelGroup, now)
+		if err := r.reconcileBudgetEnforcement(ctx, labelGroup, podsByNamespace, budgetUtilizations, now); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't enforce the budget.")
+		}
 
-		totalCarbon = totalCarbon + (totalEnergy-originalTotalEnergy)*currentCarbonIntensity
-		labelGroup.Status.TotalCarbon = fmt.Sprintf("%.10f", totalCarbon)
+		// The freshness of the intensities of the zones the energy was accounted in, or of the zone of the group
+		accountedZones := slices.Sorted(maps.Keys(zoneEnergy))
+		if len(accountedZones) == 0 {
+			accountedZones = []string{carbonConfig.location}
+		}
+		r.setCarbonIntensityCondition(labelGroup, accountedZones)
+		r.setReadyCondition(labelGroup)
 
-		if err := r.Status().Update(ctx, labelGroup); err != nil {
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
 			return ctrl.Result{}, err
 		}
 
-		// 5) Add energy aggregation to Prometheus table
-		r.SetAggregatedEnergyForLabels(totalEnergy, labelGroup.Status.PrometheusLabels)
-		r.SetAggregatedCarbonForLabels(totalCarbon, labelGroup.Status.PrometheusLabels)
+		// 9) Add energy aggregation to Prometheus table
+		if err := r.SetAggregatedTotalsForLabels(status); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't export the aggregated totals.")
+		}
+		if err := r.SetSCIForLabels(status.SCI, status.PrometheusLabels); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't export the SCI.")
+		}
+		if err := r.SetBudgetUtilizationForLabels(budgetUtilizations, status.PrometheusLabels); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't export the budget utilization.")
+		}
+		if err := r.SetEnergyBreakdownForLabels(status.EnergyBreakdown, status.PrometheusLabels); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't export the energy breakdown.")
+		}
+		if err := r.reconcileBudgetRule(ctx, labelGroup); err != nil {
+			r.Logger.V(0).Error(err, "[Reconcile-Aggregating] Couldn't apply the PrometheusRule of the budget.")
+		}
 
 		// Requeue
 		return ctrl.Result{RequeueAfter: r.SamplingRate}, nil
@@ -362,9 +571,10 @@ func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	default:
 		r.Logger.V(5).Info("[Reconcile-default] Entered default case.")
 		// First time seeing this object
-		labelGroup.Status.Phase = susqlv1.Initializing
+		status.Phase = susqlv2.Initializing
+		r.setReadyCondition(labelGroup)
 
-		if err := r.Status().Update(ctx, labelGroup); err != nil {
+		if err := r.Status().Update(ctx, labelGroup.object()); err != nil {
 			r.Logger.V(0).Error(err, "[Reconcile-default] Couldn't set object to 'Initializing'.")
 		}
 
@@ -372,10 +582,48 @@ func (r *LabelGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 }
 
+// validateGroupSpec checks the spec of a label group, and returns the reason of the SpecValid condition when it is not valid
+func (r *LabelGroupReconciler) validateGroupSpec(labelGroup labelGroupObject) (string, error) {
+	if err := labelGroup.validate(); err != nil {
+		return reasonInvalidSelector, err
+	}
+
+	if err := ValidateGroupEnergySource(labelGroup.energySource()); err != nil {
+		return reasonInvalidEnergySource, err
+	}
+
+	if err := ValidateCarbonSpec(labelGroup.carbonSpec()); err != nil {
+		return reasonInvalidCarbon, err
+	}
+
+	if err := ValidateSCISpec(labelGroup.sciSpec(), r.SCIFunctionalUnits); err != nil {
+		return reasonInvalidSCI, err
+	}
+
+	if err := ValidateBudgetSpec(labelGroup.budgetSpec()); err != nil {
+		return reasonInvalidBudget, err
+	}
+
+	if err := ValidateBreakdownSpec(labelGroup.breakdownSpec()); err != nil {
+		return reasonInvalidBreakdown, err
+	}
+
+	if err := ValidateIdleEnergy(labelGroup.idleEnergy()); err != nil {
+		return reasonInvalidIdleEnergy, err
+	}
+
+	return reasonValid, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *LabelGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Index the pods by node, whose running pods share the node overhead
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, podNodeNameField, podNodeName); err != nil {
+		return err
+	}
+
 	controllerManager := ctrl.NewControllerManagedBy(mgr).
-		For(&susqlv1.LabelGroup{}).
+		For(&susqlv2.LabelGroup{}).
 		// Watch for changes to Pods and enqueue requests for LabelGroup owners
 		Owns(&corev1.Pod{}).
 		Named("susql").
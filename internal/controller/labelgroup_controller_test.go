@@ -27,7 +27,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
 )
 
 var _ = Describe("LabelGroup Controller", func() {
@@ -40,13 +40,13 @@ var _ = Describe("LabelGroup Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
-		labelgroup := &susqlv1.LabelGroup{}
+		labelgroup := &susqlv2.LabelGroup{}
 
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind LabelGroup")
 			err := k8sClient.Get(ctx, typeNamespacedName, labelgroup)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &susqlv1.LabelGroup{
+				resource := &susqlv2.LabelGroup{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
@@ -59,7 +59,7 @@ var _ = Describe("LabelGroup Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &susqlv1.LabelGroup{}
+			resource := &susqlv2.LabelGroup{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
 			Expect(err).NotTo(HaveOccurred())
 
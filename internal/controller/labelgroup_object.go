@@ -0,0 +1,236 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// labelGroupObject is what the reconcile phases need from a LabelGroup or a ClusterLabelGroup
+type labelGroupObject interface {
+	// Object to be updated in the cluster
+	object() client.Object
+
+	// Status shared by both kinds of label groups
+	groupStatus() *susqlv2.LabelGroupStatus
+
+	// List of labels used to construct the SusQL Kubernetes labels
+	groupLabels() []string
+
+	// Do not use the most recent value stored in the database
+	disableUsingMostRecentValue() bool
+
+	// Source of the energy of the containers, nil for the energy source of the operator
+	energySource() *susqlv2.EnergySource
+
+	// Carbon configuration overriding the one of the operator, nil for the carbon configuration of the operator
+	carbonSpec() *susqlv2.CarbonSpec
+
+	// Functional unit of the Software Carbon Intensity, nil when the SCI is not computed
+	sciSpec() *susqlv2.SCISpec
+
+	// Energy and carbon budget, nil when the group has no budget
+	budgetSpec() *susqlv2.BudgetSpec
+
+	// Breakdown of the energy and carbon, nil when it is not kept
+	breakdownSpec() *susqlv2.BreakdownSpec
+
+	// Attribution of the idle energy of the nodes, empty for Kepler
+	idleEnergy() susqlv2.IdleEnergyAttributionType
+
+	// Check that the selectors in the spec are valid
+	validate() error
+
+	// List the names of the matching pods grouped by namespace
+	listPods(ctx context.Context, r *LabelGroupReconciler) (map[string][]corev1.Pod, error)
+
+	// Human readable description used in log messages
+	describe() string
+}
+
+// namespacedLabelGroup tracks pods in the namespace of a LabelGroup
+type namespacedLabelGroup struct {
+	*susqlv2.LabelGroup
+}
+
+func (lg *namespacedLabelGroup) object() client.Object {
+	return lg.LabelGroup
+}
+
+func (lg *namespacedLabelGroup) groupStatus() *susqlv2.LabelGroupStatus {
+	return &lg.Status
+}
+
+func (lg *namespacedLabelGroup) groupLabels() []string {
+	return lg.Spec.Labels
+}
+
+func (lg *namespacedLabelGroup) disableUsingMostRecentValue() bool {
+	return lg.Spec.DisableUsingMostRecentValue
+}
+
+func (lg *namespacedLabelGroup) energySource() *susqlv2.EnergySource {
+	return lg.Spec.EnergySource
+}
+
+func (lg *namespacedLabelGroup) carbonSpec() *susqlv2.CarbonSpec {
+	return lg.Spec.Carbon
+}
+
+func (lg *namespacedLabelGroup) sciSpec() *susqlv2.SCISpec {
+	return lg.Spec.SCI
+}
+
+func (lg *namespacedLabelGroup) budgetSpec() *susqlv2.BudgetSpec {
+	return lg.Spec.Budget
+}
+
+func (lg *namespacedLabelGroup) breakdownSpec() *susqlv2.BreakdownSpec {
+	return lg.Spec.Breakdown
+}
+
+func (lg *namespacedLabelGroup) idleEnergy() susqlv2.IdleEnergyAttributionType {
+	return lg.Spec.IdleEnergy
+}
+
+func (lg *namespacedLabelGroup) validate() error {
+	_, err := podSelectorForLabelGroup(lg.LabelGroup)
+	return err
+}
+
+func (lg *namespacedLabelGroup) listPods(ctx context.Context, r *LabelGroupReconciler) (map[string][]corev1.Pod, error) {
+	podSelector, err := podSelectorForLabelGroup(lg.LabelGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := r.filterPodsInNamespace(ctx, lg.Namespace, podSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]corev1.Pod{lg.Namespace: pods}, nil
+}
+
+func (lg *namespacedLabelGroup) describe() string {
+	return fmt.Sprintf("LabelGroup '%s' in namespace '%s'", lg.Name, lg.Namespace)
+}
+
+// clusterLabelGroup tracks pods in all the namespaces selected by a ClusterLabelGroup
+type clusterLabelGroup struct {
+	*susqlv2.ClusterLabelGroup
+}
+
+func (clg *clusterLabelGroup) object() client.Object {
+	return clg.ClusterLabelGroup
+}
+
+func (clg *clusterLabelGroup) groupStatus() *susqlv2.LabelGroupStatus {
+	return &clg.Status
+}
+
+func (clg *clusterLabelGroup) groupLabels() []string {
+	return nil
+}
+
+func (clg *clusterLabelGroup) disableUsingMostRecentValue() bool {
+	return clg.Spec.DisableUsingMostRecentValue
+}
+
+func (clg *clusterLabelGroup) selectors() (labels.Selector, labels.Selector, error) {
+	// A missing namespace selector selects all namespaces
+	namespaceSelector := labels.Everything()
+
+	if clg.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(clg.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid namespace selector: %w", err)
+		}
+		namespaceSelector = selector
+	}
+
+	// An empty pod selector would select all the pods of the cluster
+	if len(clg.Spec.Selector.MatchLabels) == 0 && len(clg.Spec.Selector.MatchExpressions) == 0 {
+		return nil, nil, fmt.Errorf("invalid pod selector: the selector must not be empty")
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(&clg.Spec.Selector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pod selector: %w", err)
+	}
+
+	return namespaceSelector, podSelector, nil
+}
+
+func (clg *clusterLabelGroup) energySource() *susqlv2.EnergySource {
+	return clg.Spec.EnergySource
+}
+
+func (clg *clusterLabelGroup) carbonSpec() *susqlv2.CarbonSpec {
+	return clg.Spec.Carbon
+}
+
+func (clg *clusterLabelGroup) sciSpec() *susqlv2.SCISpec {
+	return clg.Spec.SCI
+}
+
+func (clg *clusterLabelGroup) budgetSpec() *susqlv2.BudgetSpec {
+	return clg.Spec.Budget
+}
+
+func (clg *clusterLabelGroup) breakdownSpec() *susqlv2.BreakdownSpec {
+	return clg.Spec.Breakdown
+}
+
+func (clg *clusterLabelGroup) idleEnergy() susqlv2.IdleEnergyAttributionType {
+	return clg.Spec.IdleEnergy
+}
+
+func (clg *clusterLabelGroup) validate() error {
+	_, _, err := clg.selectors()
+	return err
+}
+
+func (clg *clusterLabelGroup) listPods(ctx context.Context, r *LabelGroupReconciler) (map[string][]corev1.Pod, error) {
+	namespaceSelector, podSelector, err := clg.selectors()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetPodsMatchingLabels(ctx, namespaceSelector, podSelector)
+}
+
+func (clg *clusterLabelGroup) describe() string {
+	return fmt.Sprintf("ClusterLabelGroup '%s'", clg.Name)
+}
+
+// Count the pods of a list of pods grouped by namespace
+func countPods(podsByNamespace map[string][]corev1.Pod) int {
+	count := 0
+	for _, pods := range podsByNamespace {
+		count += len(pods)
+	}
+	return count
+}
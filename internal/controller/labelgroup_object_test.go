@@ -0,0 +1,51 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("ClusterLabelGroup Selectors", func() {
+	newClusterLabelGroup := func(selector metav1.LabelSelector) *clusterLabelGroup {
+		return &clusterLabelGroup{&susqlv2.ClusterLabelGroup{Spec: susqlv2.ClusterLabelGroupSpec{Selector: selector}}}
+	}
+
+	It("should select the pods of the selector in all namespaces", func() {
+		labelGroup := newClusterLabelGroup(metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+		Expect(labelGroup.validate()).To(Succeed())
+
+		namespaceSelector, podSelector, err := labelGroup.selectors()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(namespaceSelector.Empty()).To(BeTrue())
+		Expect(podSelector.String()).To(Equal("app=web"))
+	})
+
+	It("should reject an empty selector, which would select all the pods of the cluster", func() {
+		Expect(newClusterLabelGroup(metav1.LabelSelector{}).validate()).NotTo(Succeed())
+		Expect(newClusterLabelGroup(metav1.LabelSelector{MatchLabels: map[string]string{}}).validate()).NotTo(Succeed())
+
+		labelGroup := newClusterLabelGroup(metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpExists},
+		}})
+		Expect(labelGroup.validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,152 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount" // Files of the service account of the operator pod
+	secretCacheTTL    = 1 * time.Minute                                 // Time a value read from a Secret is reused
+)
+
+// ParsePrometheusClientConfig parses the HTTP client configuration of a Prometheus endpoint. The configuration uses
+// the format of the http_config section of Prometheus scrape configs (tls_config, authorization, basic_auth,
+// http_headers, proxy_url, ...). Secret references (credentials_ref, password_ref, ca_ref, cert_ref, key_ref) name a
+// key of a Secret in the namespace of the operator as "<secret>/<key>".
+//
+// When the configuration is empty, an https endpoint is queried with the token of the service account of the operator
+// and its certificate is verified with the service CA of the cluster (OpenShift) when available, or the system roots.
+func ParsePrometheusClientConfig(url string, configYAML string) (*config.HTTPClientConfig, error) {
+	if strings.TrimSpace(configYAML) == "" {
+		return defaultPrometheusClientConfig(url), nil
+	}
+
+	clientConfig, err := config.LoadHTTPConfig(configYAML)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus client configuration: %w", err)
+	}
+
+	return clientConfig, nil
+}
+
+func defaultPrometheusClientConfig(url string) *config.HTTPClientConfig {
+	clientConfig := config.DefaultHTTPClientConfig
+
+	if strings.HasPrefix(url, "https://") {
+		clientConfig.Authorization = &config.Authorization{
+			Type:            "Bearer",
+			CredentialsFile: filepath.Join(serviceAccountDir, "token"),
+		}
+
+		serviceCAFile := filepath.Join(serviceAccountDir, "service-ca.crt")
+		if _, err := os.Stat(serviceCAFile); err == nil {
+			clientConfig.TLSConfig.CAFile = serviceCAFile
+		}
+	}
+
+	return &clientConfig
+}
+
+// NewPrometheusRoundTripper creates the round tripper of the HTTP client of a Prometheus endpoint
+func NewPrometheusRoundTripper(clientConfig *config.HTTPClientConfig, name string, secrets config.SecretManager) (http.RoundTripper, error) {
+	var options []config.HTTPClientOption
+	if secrets != nil {
+		options = append(options, config.WithSecretManager(secrets))
+	}
+
+	return config.NewRoundTripperFromConfig(*clientConfig, name, options...)
+}
+
+// SecretManager resolves the Secret references of the Prometheus client configurations from the Secrets of a namespace
+type SecretManager struct {
+	reader    client.Reader
+	namespace string
+	mutex     sync.Mutex
+	cache     map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+// NewSecretManager creates a SecretManager reading the Secrets of the given namespace, or of the namespace of the
+// operator when empty. The reader should not be cached, so that the operator does not watch all the Secrets.
+func NewSecretManager(reader client.Reader, namespace string) *SecretManager {
+	if namespace == "" {
+		namespace = OperatorNamespace()
+	}
+
+	return &SecretManager{reader: reader, namespace: namespace, cache: make(map[string]cachedSecret)}
+}
+
+// OperatorNamespace returns the namespace the operator runs in, empty when it does not run in a pod
+func OperatorNamespace() string {
+	namespaceBytes, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(namespaceBytes))
+}
+
+// Fetch returns the value of the key of a Secret referenced as "<secret>/<key>"
+func (s *SecretManager) Fetch(ctx context.Context, secretRef string) (string, error) {
+	name, key, found := strings.Cut(secretRef, "/")
+	if !found || name == "" || key == "" {
+		return "", fmt.Errorf("invalid secret reference '%s', expected '<secret>/<key>'", secretRef)
+	}
+
+	if s.namespace == "" {
+		return "", fmt.Errorf("couldn't read secret '%s': the namespace of the operator is unknown", name)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if cached, ok := s.cache[secretRef]; ok && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, secret); err != nil {
+		return "", fmt.Errorf("couldn't read secret '%s/%s': %w", s.namespace, name, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret '%s/%s' has no key '%s'", s.namespace, name, key)
+	}
+
+	s.cache[secretRef] = cachedSecret{value: string(value), expires: time.Now().Add(secretCacheTTL)}
+
+	return string(value), nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Prometheus Clients", func() {
+	var requests []*http.Request
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requests = append(requests, req)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(clientConfigYAML string, secretManager *SecretManager) error {
+		clientConfig, err := ParsePrometheusClientConfig(server.URL, clientConfigYAML)
+		Expect(err).NotTo(HaveOccurred())
+
+		roundTripper, err := NewPrometheusRoundTripper(clientConfig, "test", secretManager)
+		Expect(err).NotTo(HaveOccurred())
+
+		response, err := (&http.Client{Transport: roundTripper}).Get(server.URL + "/api/v1/query")
+		if err == nil {
+			response.Body.Close()
+		}
+		return err
+	}
+
+	It("should verify the certificates by default", func() {
+		Expect(get("", nil)).To(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should reject invalid configurations", func() {
+		_, err := ParsePrometheusClientConfig(server.URL, "authorization:\n  credentials: a\n  credentials_file: b\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should send the credentials and headers read from Secrets", func() {
+		secretManager := NewSecretManager(fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus-token", Namespace: "susql"},
+			Data:       map[string][]byte{"token": []byte("secret-token")},
+		}).Build(), "susql")
+
+		Expect(get(`
+authorization:
+  credentials_ref: prometheus-token/token
+tls_config:
+  insecure_skip_verify: true
+http_headers:
+  X-Scope-OrgID:
+    values: ["team-a"]
+`, secretManager)).To(Succeed())
+
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Header.Get("Authorization")).To(Equal("Bearer secret-token"))
+		Expect(requests[0].Header.Get("X-Scope-OrgID")).To(Equal("team-a"))
+	})
+
+	It("should report missing Secrets and keys", func() {
+		secretManager := NewSecretManager(fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus-token", Namespace: "susql"},
+			Data:       map[string][]byte{"token": []byte("secret-token")},
+		}).Build(), "susql")
+
+		_, err := secretManager.Fetch(context.Background(), "prometheus-token/password")
+		Expect(err).To(HaveOccurred())
+		_, err = secretManager.Fetch(context.Background(), "other-token/token")
+		Expect(err).To(HaveOccurred())
+		_, err = secretManager.Fetch(context.Background(), "prometheus-token")
+		Expect(err).To(HaveOccurred())
+	})
+})
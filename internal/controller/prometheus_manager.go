@@ -18,30 +18,38 @@ package controller
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
-	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
 )
 
 const (
 	maxQueryLength = 10000 // Maximum allowed query string length
+
+	budgetResourceLabelName = "resource" // SusQL Prometheus label of the budget utilization holding the budget resource
 )
 
 var (
-	maxQueryTime                         = "1y" // Look back 'maxQueryTime' for the most recent value
-	keplerRoundTripper http.RoundTripper = nil
-	susqlRoundTripper  http.RoundTripper = nil
+	maxQueryTime = "1y" // Look back 'maxQueryTime' for the most recent value
+
+	errNoValue = errors.New("no value") // The query returned an empty vector
 )
 
 // Functions to get data from the cluster
@@ -51,15 +59,9 @@ func (r *LabelGroupReconciler) GetMostRecentValue(susqlPrometheusQuery string) (
 
 func (r *LabelGroupReconciler) GetMostRecentValueWithContext(ctx context.Context, susqlPrometheusQuery string) (float64, error) {
 	// Return the most recent value found in the table
-	if susqlRoundTripper == nil {
-		if strings.HasPrefix(r.SusQLPrometheusDatabaseUrl, "https://") {
-			rttls := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
-			susqlRoundTripper = config.NewAuthorizationCredentialsRoundTripper("Bearer", config.NewFileSecret("/var/run/secrets/kubernetes.io/serviceaccount/token"), rttls)
-		}
-	}
 	client, err := api.NewClient(api.Config{
 		Address:      r.SusQLPrometheusDatabaseUrl,
-		RoundTripper: susqlRoundTripper,
+		RoundTripper: r.SusQLPrometheusRoundTripper,
 	})
 
 	if err != nil {
@@ -97,126 +99,295 @@ func (r *LabelGroupReconciler) GetMostRecentValueWithContext(ctx context.Context
 	}
 }
 
-func (r *LabelGroupReconciler) GetMetricValuesForPodNames(metricName string, podNames []string, namespaceName string) (map[string]float64, error) {
-	return r.GetMetricValuesForPodNamesWithContext(context.Background(), metricName, podNames, namespaceName)
-}
-
-func (r *LabelGroupReconciler) GetMetricValuesForPodNamesWithContext(ctx context.Context, metricName string, podNames []string, namespaceName string) (map[string]float64, error) {
-	// Check for empty pod list
-	if len(podNames) == 0 {
-		return make(map[string]float64), nil
-	}
-
-	if keplerRoundTripper == nil {
-		if strings.HasPrefix(r.KeplerPrometheusUrl, "https://") {
-			rttls := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
-			keplerRoundTripper = config.NewAuthorizationCredentialsRoundTripper("Bearer", config.NewFileSecret("/var/run/secrets/kubernetes.io/serviceaccount/token"), rttls)
-		}
-	}
+// Create the API client of the Kepler Prometheus server
+func (r *LabelGroupReconciler) keplerAPI() (v1.API, error) {
 	client, err := api.NewClient(api.Config{
 		Address:      r.KeplerPrometheusUrl,
-		RoundTripper: keplerRoundTripper,
+		RoundTripper: r.KeplerPrometheusRoundTripper,
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("[GetMetricValuesForPodNamesWithContext] couldn't create HTTP client: %w (metricName: %s, URL: %s)",
-			err, metricName, r.KeplerPrometheusUrl)
-	}
-
-	v1api := v1.NewAPI(client)
-	queryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	// Build query string efficiently using strings.Builder
-	var queryBuilder strings.Builder
-
-	// Estimate capacity to reduce allocations
-	estimatedSize := len(podNames) * 200 // Rough estimate per pod
-	queryBuilder.Grow(estimatedSize)
-
-	// Build query for first pod
-	queryBuilder.WriteString(fmt.Sprintf("sum(%s{pod_name=\"%s\",container_namespace=\"%s\",mode=\"dynamic\"})", metricName, podNames[0], namespaceName))
-	queryBuilder.WriteString("+")
-	queryBuilder.WriteString(fmt.Sprintf("sum(%s{pod_name=\"%s\",container_namespace=\"%s\",mode=\"idle\"})", metricName, podNames[0], namespaceName))
-
-	// Build query for remaining pods
-	for i := 1; i < len(podNames); i++ {
-		queryBuilder.WriteString("+")
-		queryBuilder.WriteString(fmt.Sprintf("sum(%s{pod_name=\"%s\",container_namespace=\"%s\",mode=\"dynamic\"})", metricName, podNames[i], namespaceName))
-		queryBuilder.WriteString("+")
-		queryBuilder.WriteString(fmt.Sprintf("sum(%s{pod_name=\"%s\",container_namespace=\"%s\",mode=\"idle\"})", metricName, podNames[i], namespaceName))
-
-		// Check if query is getting too long
-		if queryBuilder.Len() > maxQueryLength {
-			r.Logger.V(0).Error(fmt.Errorf("query string exceeds maximum length of %d characters", maxQueryLength),
-				"[GetMetricValuesForPodNames] Query too long, truncating pod list")
-			break
-		}
+		return nil, err
 	}
 
-	queryString := queryBuilder.String()
-
-	results, warnings, err := v1api.Query(queryCtx, queryString, time.Now(), v1.WithTimeout(0*time.Second))
+	return v1.NewAPI(client), nil
+}
 
-	r.Logger.V(5).Info(fmt.Sprintf("[GetMetricValuesForPodNamesWithContext] Query: %s", queryString)) // trace
+// Create the API client of the SusQL Prometheus database
+func (r *LabelGroupReconciler) susqlAPI() (v1.API, error) {
+	client, err := api.NewClient(api.Config{
+		Address:      r.SusQLPrometheusDatabaseUrl,
+		RoundTripper: r.SusQLPrometheusRoundTripper,
+	})
 
 	if err != nil {
-		r.Logger.V(0).Error(err, "[GetMetricValuesForPodNamesWithContext] Querying Prometheus didn't work.\n"+
-			fmt.Sprintf("\tmetricName: %s\n", metricName)+
-			fmt.Sprintf("\tKeplerPrometheusUrl: %s\n", r.KeplerPrometheusUrl)+
-			fmt.Sprintf("\tqueryString: %s\n", queryString))
 		return nil, err
 	}
 
-	if len(warnings) > 0 {
-		r.Logger.V(0).Info(fmt.Sprintf("WARNING [GetMetricValuesForPodNamesWithContext] %v\n", warnings) +
-			fmt.Sprintf("\tmetricName: %s\n", metricName) +
-			fmt.Sprintf("\tKeplerPrometheusUrl: %s\n", r.KeplerPrometheusUrl) +
-			fmt.Sprintf("\tqueryString: %s", queryString))
-	}
+	return v1.NewAPI(client), nil
+}
 
-	metricValues := make(map[string]float64, len(results.(model.Vector)))
+// Run an instant query returning a single value, the first sample of a vector or a scalar
+func queryValue(ctx context.Context, v1api v1.API, query string, at time.Time) (float64, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	for _, result := range results.(model.Vector) {
-		r.Logger.V(5).Info(fmt.Sprintf("[GetMetricValuesForPodNamesWithContext] Container id %s value is %f.", string(result.Metric["container_id"]), float64(result.Value))) // trace
-		metricValues[string(result.Metric["container_id"])] = float64(result.Value)
+	results, _, err := v1api.Query(queryCtx, query, at, v1.WithTimeout(0*time.Second))
+	if err != nil {
+		return 0, err
 	}
 
-	return metricValues, nil
+	switch result := results.(type) {
+	case model.Vector:
+		if len(result) == 0 {
+			return 0, fmt.Errorf("the query '%s' returned %w", query, errNoValue)
+		}
+		return float64(result[0].Value), nil
+	case *model.Scalar:
+		return float64(result.Value), nil
+	default:
+		return 0, fmt.Errorf("the query '%s' returned a %s instead of a vector or a scalar", query, results.Type())
+	}
 }
 
 type SusqlMetrics struct {
-	totalEnergy *prometheus.GaugeVec
-	totalCarbon *prometheus.GaugeVec
+	totalEnergy                *prometheus.GaugeVec
+	totalCarbon                *prometheus.GaugeVec
+	totalDynamicEnergy         *prometheus.GaugeVec
+	totalIdleEnergy            *prometheus.GaugeVec
+	totalFacilityEnergy        *prometheus.GaugeVec
+	totalEmbodiedCarbon        *prometheus.GaugeVec
+	sci                        *prometheus.GaugeVec
+	budgetUtilization          *prometheus.GaugeVec
+	breakdownEnergy            *prometheus.GaugeVec
+	breakdownCarbon            *prometheus.GaugeVec
+	carbonIntensity            *prometheus.GaugeVec
+	carbonIntensityLastSuccess *prometheus.GaugeVec
+	carbonIntensityLastError   *prometheus.GaugeVec
+	carbonIntensityQueryErrors *prometheus.CounterVec
+	carbonIntensityForecast    *prometheus.GaugeVec
 }
 
 var (
-	susqlMetrics = &SusqlMetrics{
+	susqlMetrics *SusqlMetrics
+
+	prometheusRegistry *prometheus.Registry
+	prometheusHandler  http.Handler
+)
+
+func newSusqlMetrics(labelNames []string) *SusqlMetrics {
+	return &SusqlMetrics{
 		totalEnergy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: "susql",
 			Name:      "total_energy_joules",
 			Help:      "Accumulated energy over time for set of labels",
-		}, susqlPrometheusLabelNames),
+		}, labelNames),
 		totalCarbon: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: "susql",
 			Name:      "total_carbon_dioxide_grams",
 			Help:      "Accumulated carbon dioxide grams over time for set of labels",
-		}, susqlPrometheusLabelNames),
+		}, labelNames),
+		totalDynamicEnergy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "total_dynamic_energy_joules",
+			Help:      "Accumulated energy over time driven by the activity of the containers for set of labels",
+		}, labelNames),
+		totalIdleEnergy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "total_idle_energy_joules",
+			Help:      "Accumulated idle energy of the nodes over time attributed to the containers for set of labels",
+		}, labelNames),
+		totalFacilityEnergy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "total_facility_energy_joules",
+			Help:      "Accumulated energy over time for set of labels including the overhead of the data center (PUE)",
+		}, labelNames),
+		totalEmbodiedCarbon: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "total_embodied_carbon_dioxide_grams",
+			Help:      "Accumulated embodied carbon dioxide equivalent grams of the hardware over time for set of labels",
+		}, labelNames),
+		sci: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "sci_grams_per_functional_unit",
+			Help:      "Software Carbon Intensity, operational and embodied carbon dioxide grams per functional unit over the SCI window for set of labels",
+		}, labelNames),
+		budgetUtilization: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "budget_utilization_ratio",
+			Help:      "Ratio of the energy or carbon used in the budget period to the budget limit for set of labels",
+		}, append(slices.Clone(labelNames), budgetResourceLabelName)),
+		breakdownEnergy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "breakdown_energy_joules",
+			Help:      "Accumulated energy over time of a pod or container of the set of labels",
+		}, append(slices.Clone(labelNames), breakdownNamespaceLabelName, breakdownPodLabelName, breakdownContainerLabelName)),
+		breakdownCarbon: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "breakdown_carbon_dioxide_grams",
+			Help:      "Accumulated carbon dioxide grams over time of a pod or container of the set of labels",
+		}, append(slices.Clone(labelNames), breakdownNamespaceLabelName, breakdownPodLabelName, breakdownContainerLabelName)),
+		carbonIntensity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "carbon_intensity_grams_per_joule",
+			Help:      "Carbon intensity applied to the energy of a carbon zone, from its last successful query or the static carbon intensity",
+		}, []string{"zone"}),
+		carbonIntensityLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "carbon_intensity_last_success_timestamp_seconds",
+			Help:      "Unix time of the last successful carbon intensity query of a carbon zone",
+		}, []string{"zone"}),
+		carbonIntensityLastError: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "carbon_intensity_last_error_timestamp_seconds",
+			Help:      "Unix time of the last failed carbon intensity query of a carbon zone",
+		}, []string{"zone"}),
+		carbonIntensityQueryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "susql",
+			Name:      "carbon_intensity_query_errors_total",
+			Help:      "Number of failed carbon intensity queries of a carbon zone",
+		}, []string{"zone"}),
+		carbonIntensityForecast: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "susql",
+			Name:      "carbon_intensity_forecast",
+			Help:      "Forecast carbon intensity in grams per joule of a carbon zone at a horizon from the last forecast query",
+		}, []string{"zone", "horizon"}),
 	}
+}
 
-	prometheusRegistry *prometheus.Registry
-	prometheusHandler  http.Handler
-)
+// ParseMetricLabelMap parses a comma separated list of 'kubernetes-label=prometheus_label' pairs
+func ParseMetricLabelMap(metricLabelMap string) (map[string]string, error) {
+	labelMap := make(map[string]string)
+	prometheusLabelNames := map[string]bool{
+		susqlPrometheusNamespaceLabelName: true,
+		susqlPrometheusNameLabelName:      true,
+		breakdownNamespaceLabelName:       true,
+		breakdownPodLabelName:             true,
+		breakdownContainerLabelName:       true,
+	}
+
+	for _, pair := range strings.Split(metricLabelMap, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kubernetesLabelName, prometheusLabelName, found := strings.Cut(pair, "=")
+		kubernetesLabelName = strings.TrimSpace(kubernetesLabelName)
+		prometheusLabelName = strings.TrimSpace(prometheusLabelName)
+
+		if !found || kubernetesLabelName == "" {
+			return nil, fmt.Errorf("metric label mapping '%s' is not of the form 'kubernetes-label=prometheus_label'", pair)
+		}
+		if !model.LabelName(prometheusLabelName).IsValidLegacy() {
+			return nil, fmt.Errorf("metric label mapping '%s' has an invalid prometheus label name '%s'", pair, prometheusLabelName)
+		}
+		if prometheusLabelNames[prometheusLabelName] {
+			return nil, fmt.Errorf("metric label mapping '%s' reuses the prometheus label name '%s'", pair, prometheusLabelName)
+		}
+
+		prometheusLabelNames[prometheusLabelName] = true
+		labelMap[kubernetesLabelName] = prometheusLabelName
+	}
+
+	return labelMap, nil
+}
+
+// Names of the labels of the exported SusQL metrics for the configured metric label mode
+func (r *LabelGroupReconciler) susqlPrometheusLabelNames() []string {
+	if r.MetricLabelMode == MetricLabelModeLegacy {
+		return susqlLegacyPrometheusLabelNames
+	}
+
+	var mappedLabelNames []string
+	for _, prometheusLabelName := range r.MetricLabelMap {
+		mappedLabelNames = append(mappedLabelNames, prometheusLabelName)
+	}
+	sort.Strings(mappedLabelNames)
+
+	return append([]string{susqlPrometheusNamespaceLabelName, susqlPrometheusNameLabelName}, mappedLabelNames...)
+}
+
+// SusQL Prometheus labels identifying the exported series of a LabelGroup or ClusterLabelGroup
+func (r *LabelGroupReconciler) prometheusLabelsForLabelGroup(labelGroup labelGroupObject) (map[string]string, error) {
+	prometheusLabels := make(map[string]string)
+	object := labelGroup.object()
+
+	if r.MetricLabelMode == MetricLabelModeLegacy {
+		if object.GetNamespace() == "" {
+			return nil, fmt.Errorf("the '%s' metric label mode does not support cluster scoped label groups", MetricLabelModeLegacy)
+		}
+
+		if len(labelGroup.groupLabels()) > len(susqlLegacyPrometheusLabelNames) {
+			return nil, fmt.Errorf("the number of provided labels is greater than the maximum number of labels supported by the '%s' metric label mode (e.g., up to %d labels)",
+				MetricLabelModeLegacy, len(susqlLegacyPrometheusLabelNames))
+		}
+
+		for ldx, prometheusLabelName := range susqlLegacyPrometheusLabelNames {
+			if ldx < len(labelGroup.groupLabels()) {
+				prometheusLabels[prometheusLabelName] = labelGroup.groupLabels()[ldx]
+			} else {
+				prometheusLabels[prometheusLabelName] = ""
+			}
+		}
+
+		return prometheusLabels, nil
+	}
+
+	// Cluster scoped label groups are exported with an empty namespace
+	prometheusLabels[susqlPrometheusNamespaceLabelName] = object.GetNamespace()
+	prometheusLabels[susqlPrometheusNameLabelName] = object.GetName()
+
+	for kubernetesLabelName, prometheusLabelName := range r.MetricLabelMap {
+		prometheusLabels[prometheusLabelName] = object.GetLabels()[kubernetesLabelName]
+	}
+
+	return prometheusLabels, nil
+}
+
+// Build the query selecting the series of a SusQL metric for a set of SusQL Prometheus labels
+func buildSusQLPrometheusQuery(metricName string, prometheusLabels map[string]string) string {
+	labelNames := make([]string, 0, len(prometheusLabels))
+	for labelName := range prometheusLabels {
+		labelNames = append(labelNames, labelName)
+	}
+	sort.Strings(labelNames)
+
+	var queryBuilder strings.Builder
+
+	queryBuilder.WriteString(metricName)
+	queryBuilder.WriteString("{")
+	for ldx, labelName := range labelNames {
+		if ldx > 0 {
+			queryBuilder.WriteString(",")
+		}
+		queryBuilder.WriteString(fmt.Sprintf("%s=%s", labelName, strconv.Quote(prometheusLabels[labelName])))
+	}
+	queryBuilder.WriteString("}")
+
+	return queryBuilder.String()
+}
 
 func (r *LabelGroupReconciler) InitializeMetricsExporter() error {
 	// Initiate the exporting of prometheus metrics for the energy
 	r.Logger.V(5).Info("Entering InitializeMetricsExporter().")
 	if prometheusRegistry == nil {
+		susqlMetrics = newSusqlMetrics(r.susqlPrometheusLabelNames())
+
 		prometheusRegistry = prometheus.NewRegistry()
-		prometheusRegistry.MustRegister(susqlMetrics.totalEnergy, susqlMetrics.totalCarbon)
+		prometheusRegistry.MustRegister(susqlMetrics.totalEnergy, susqlMetrics.totalCarbon, susqlMetrics.totalDynamicEnergy, susqlMetrics.totalIdleEnergy, susqlMetrics.totalFacilityEnergy, susqlMetrics.totalEmbodiedCarbon, susqlMetrics.sci, susqlMetrics.budgetUtilization,
+			susqlMetrics.breakdownEnergy, susqlMetrics.breakdownCarbon,
+			susqlMetrics.carbonIntensity, susqlMetrics.carbonIntensityLastSuccess, susqlMetrics.carbonIntensityLastError, susqlMetrics.carbonIntensityQueryErrors,
+			susqlMetrics.carbonIntensityForecast)
+
+		// The static carbon intensity applies to every zone, exported as the one of the default zone
+		if r.CarbonIntensityProvider == nil {
+			susqlMetrics.carbonIntensity.WithLabelValues(r.CarbonLocation).Set(r.CarbonIntensity)
+		}
 
 		prometheusHandler = promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{Registry: prometheusRegistry})
 		http.Handle("/metrics", prometheusHandler)
+		http.HandleFunc(carbonWindowPath, r.serveBestCarbonWindow)
 
 		metricsUrl, parseErr := url.Parse(r.SusQLPrometheusMetricsUrl)
 		if parseErr != nil {
@@ -237,20 +408,164 @@ func (r *LabelGroupReconciler) InitializeMetricsExporter() error {
 	return nil
 }
 
-func (r *LabelGroupReconciler) SetAggregatedEnergyForLabels(totalEnergy float64, prometheusLabels map[string]string) error {
-	// Save aggregated energy to Prometheus table
-	susqlMetrics.totalEnergy.With(prometheusLabels).Set(totalEnergy)
+// aggregatedTotal is a total of the status of a label group, which is exported to its SusQL metric and reloaded from it
+type aggregatedTotal struct {
+	name       string // Name of the total in the log messages
+	metricName string
+	gaugeVec   func(metrics *SusqlMetrics) *prometheus.GaugeVec
+	field      func(status *susqlv2.LabelGroupStatus) **resource.Quantity
+	quantity   func(value float64) *resource.Quantity
+}
+
+var aggregatedTotals = []aggregatedTotal{
+	{name: "energy", metricName: susqlEnergyMetricName, quantity: energyQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalEnergy },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalEnergyJoules }},
+	{name: "carbon", metricName: susqlCarbonMetricName, quantity: carbonQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalCarbon },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalCarbonGrams }},
+	{name: "facility energy", metricName: susqlFacilityEnergyMetricName, quantity: energyQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalFacilityEnergy },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalFacilityEnergyJoules }},
+	{name: "dynamic energy", metricName: susqlDynamicEnergyMetricName, quantity: energyQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalDynamicEnergy },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalDynamicEnergyJoules }},
+	{name: "idle energy", metricName: susqlIdleEnergyMetricName, quantity: energyQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalIdleEnergy },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalIdleEnergyJoules }},
+	{name: "embodied carbon", metricName: susqlEmbodiedCarbonMetricName, quantity: carbonQuantity,
+		gaugeVec: func(metrics *SusqlMetrics) *prometheus.GaugeVec { return metrics.totalEmbodiedCarbon },
+		field:    func(status *susqlv2.LabelGroupStatus) **resource.Quantity { return &status.TotalEmbodiedCarbonGrams }},
+}
+
+func (r *LabelGroupReconciler) SetAggregatedTotalsForLabels(status *susqlv2.LabelGroupStatus) error {
+	// Save the aggregated totals of the status to Prometheus table
+	if susqlMetrics == nil {
+		return fmt.Errorf("[SetAggregatedTotalsForLabels] the metrics exporter has not been initialized")
+	}
+
+	var errs []error
+	for _, total := range aggregatedTotals {
+		if err := r.SetAggregatedTotalForLabels(total.gaugeVec(susqlMetrics), total.name, quantityToFloat(*total.field(status)), status.PrometheusLabels); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *LabelGroupReconciler) SetAggregatedTotalForLabels(gaugeVec *prometheus.GaugeVec, name string, total float64, prometheusLabels map[string]string) error {
+	// Save an aggregated total, e.g., the energy, to its gauge in Prometheus table
+	gauge, err := gaugeVec.GetMetricWith(prometheusLabels)
+	if err != nil {
+		return fmt.Errorf("[SetAggregatedTotalForLabels] couldn't get the %s metric for %v: %w", name, prometheusLabels, err)
+	}
+	gauge.Set(total)
+
+	r.Logger.V(5).Info(fmt.Sprintf("[SetAggregatedTotalForLabels] Setting %s %f for %v.", name, total, prometheusLabels)) // trace
+
+	return nil
+}
+
+func (r *LabelGroupReconciler) SetSCIForLabels(sci *susqlv2.SCIStatus, prometheusLabels map[string]string) error {
+	// Save the SCI to Prometheus table, removing its series when the SCI is not computed
+	if susqlMetrics == nil {
+		return fmt.Errorf("[SetSCIForLabels] the metrics exporter has not been initialized")
+	}
 
-	r.Logger.V(5).Info(fmt.Sprintf("[SetAggregatedEnergyForLabels] Setting energy %f for %v.", totalEnergy, prometheusLabels)) // trace
+	if sci == nil {
+		susqlMetrics.sci.Delete(prometheusLabels)
+		return nil
+	}
+
+	gauge, err := susqlMetrics.sci.GetMetricWith(prometheusLabels)
+	if err != nil {
+		return fmt.Errorf("[SetSCIForLabels] couldn't get the SCI metric for %v: %w", prometheusLabels, err)
+	}
+	gauge.Set(quantityToFloat(&sci.GramsPerFunctionalUnit))
+
+	r.Logger.V(5).Info(fmt.Sprintf("[SetSCIForLabels] Setting SCI %s for %v.", sci.GramsPerFunctionalUnit.String(), prometheusLabels)) // trace
 
 	return nil
 }
 
-func (r *LabelGroupReconciler) SetAggregatedCarbonForLabels(totalCarbon float64, prometheusLabels map[string]string) error {
-	// Save aggregated carbon to Prometheus table
-	susqlMetrics.totalCarbon.With(prometheusLabels).Set(totalCarbon)
+func (r *LabelGroupReconciler) SetBudgetUtilizationForLabels(utilizations map[string]float64, prometheusLabels map[string]string) error {
+	// Save the budget utilization ratios to Prometheus table, removing the series of the limits that are not set
+	if susqlMetrics == nil {
+		return fmt.Errorf("[SetBudgetUtilizationForLabels] the metrics exporter has not been initialized")
+	}
+
+	for _, resourceName := range []string{budgetResourceEnergy, budgetResourceCarbon} {
+		resourceLabels := maps.Clone(prometheusLabels)
+		resourceLabels[budgetResourceLabelName] = resourceName
 
-	r.Logger.V(5).Info(fmt.Sprintf("[SetAggregatedCarbonForLabels] Setting carbon %f for %v.", totalCarbon, prometheusLabels)) // trace
+		utilization, found := utilizations[resourceName]
+		if !found {
+			susqlMetrics.budgetUtilization.Delete(resourceLabels)
+			continue
+		}
+
+		gauge, err := susqlMetrics.budgetUtilization.GetMetricWith(resourceLabels)
+		if err != nil {
+			return fmt.Errorf("[SetBudgetUtilizationForLabels] couldn't get the budget utilization metric for %v: %w", resourceLabels, err)
+		}
+		gauge.Set(utilization)
+	}
+
+	r.Logger.V(5).Info(fmt.Sprintf("[SetBudgetUtilizationForLabels] Setting budget utilization %v for %v.", utilizations, prometheusLabels)) // trace
+
+	return nil
+}
+
+func (r *LabelGroupReconciler) SetEnergyBreakdownForLabels(breakdown []susqlv2.EnergyBreakdownEntry, prometheusLabels map[string]string) error {
+	// Save the energy breakdown to Prometheus table, replacing the series of the entries that were folded or removed
+	if susqlMetrics == nil {
+		return fmt.Errorf("[SetEnergyBreakdownForLabels] the metrics exporter has not been initialized")
+	}
+
+	susqlMetrics.breakdownEnergy.DeletePartialMatch(prometheusLabels)
+	susqlMetrics.breakdownCarbon.DeletePartialMatch(prometheusLabels)
+
+	for _, entry := range breakdown {
+		entryLabels := maps.Clone(prometheusLabels)
+		entryLabels[breakdownNamespaceLabelName] = entry.Namespace
+		entryLabels[breakdownPodLabelName] = entry.Pod
+		entryLabels[breakdownContainerLabelName] = entry.Container
+
+		energyGauge, err := susqlMetrics.breakdownEnergy.GetMetricWith(entryLabels)
+		if err != nil {
+			return fmt.Errorf("[SetEnergyBreakdownForLabels] couldn't get the breakdown energy metric for %v: %w", entryLabels, err)
+		}
+		energyGauge.Set(quantityToFloat(&entry.EnergyJoules))
+
+		carbonGauge, err := susqlMetrics.breakdownCarbon.GetMetricWith(entryLabels)
+		if err != nil {
+			return fmt.Errorf("[SetEnergyBreakdownForLabels] couldn't get the breakdown carbon metric for %v: %w", entryLabels, err)
+		}
+		carbonGauge.Set(quantityToFloat(&entry.CarbonGrams))
+	}
+
+	r.Logger.V(5).Info(fmt.Sprintf("[SetEnergyBreakdownForLabels] Setting %d breakdown entries for %v.", len(breakdown), prometheusLabels)) // trace
 
 	return nil
 }
+
+func (r *LabelGroupReconciler) DeleteAggregatedMetricsForLabels(prometheusLabels map[string]string) {
+	// Remove series that are no longer updated from Prometheus table
+	if susqlMetrics == nil {
+		return
+	}
+
+	susqlMetrics.totalEnergy.Delete(prometheusLabels)
+	susqlMetrics.totalCarbon.Delete(prometheusLabels)
+	susqlMetrics.totalDynamicEnergy.Delete(prometheusLabels)
+	susqlMetrics.totalIdleEnergy.Delete(prometheusLabels)
+	susqlMetrics.totalFacilityEnergy.Delete(prometheusLabels)
+	susqlMetrics.totalEmbodiedCarbon.Delete(prometheusLabels)
+	susqlMetrics.sci.Delete(prometheusLabels)
+	susqlMetrics.budgetUtilization.DeletePartialMatch(prometheusLabels)
+	susqlMetrics.breakdownEnergy.DeletePartialMatch(prometheusLabels)
+	susqlMetrics.breakdownCarbon.DeletePartialMatch(prometheusLabels)
+
+	r.Logger.V(5).Info(fmt.Sprintf("[DeleteAggregatedMetricsForLabels] Deleting series for %v.", prometheusLabels)) // trace
+}
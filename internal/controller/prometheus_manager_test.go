@@ -0,0 +1,161 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("SusQL Prometheus Labels", func() {
+	newLabelGroup := func(namespace string, groupLabels []string, objectLabels map[string]string) labelGroupObject {
+		if namespace == "" {
+			return &clusterLabelGroup{&susqlv2.ClusterLabelGroup{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: objectLabels}}}
+		}
+		return &namespacedLabelGroup{&susqlv2.LabelGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: namespace, Labels: objectLabels},
+			Spec:       susqlv2.LabelGroupSpec{Labels: groupLabels},
+		}}
+	}
+
+	It("should build the SusQL Prometheus labels of both metric label modes", func() {
+		tests := []struct {
+			description      string
+			mode             string
+			labelMap         map[string]string
+			labelGroup       labelGroupObject
+			prometheusLabels map[string]string
+			query            string
+		}{
+			{
+				description:      "a namespaced label group",
+				mode:             MetricLabelModeLabelGroup,
+				labelGroup:       newLabelGroup("shop", nil, nil),
+				prometheusLabels: map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop"},
+				query:            `susql_total_energy_joules{labelgroup_name="shop",labelgroup_namespace="shop"}`,
+			},
+			{
+				description:      "a cluster scoped label group",
+				mode:             MetricLabelModeLabelGroup,
+				labelGroup:       newLabelGroup("", nil, nil),
+				prometheusLabels: map[string]string{"labelgroup_namespace": "", "labelgroup_name": "shop"},
+				query:            `susql_total_energy_joules{labelgroup_name="shop",labelgroup_namespace=""}`,
+			},
+			{
+				description:      "the mapped object labels, empty when the object does not have them",
+				mode:             MetricLabelModeLabelGroup,
+				labelMap:         map[string]string{"app.kubernetes.io/team": "team", "cost-center": "cost_center"},
+				labelGroup:       newLabelGroup("shop", nil, map[string]string{"app.kubernetes.io/team": `web "frontend"`}),
+				prometheusLabels: map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop", "team": `web "frontend"`, "cost_center": ""},
+				query:            `susql_total_energy_joules{cost_center="",labelgroup_name="shop",labelgroup_namespace="shop",team="web \"frontend\""}`,
+			},
+			{
+				description: "the labels of the legacy mode",
+				mode:        MetricLabelModeLegacy,
+				labelMap:    map[string]string{"app.kubernetes.io/team": "team"},
+				labelGroup:  newLabelGroup("shop", []string{"shop", "web"}, map[string]string{"app.kubernetes.io/team": "web"}),
+				prometheusLabels: map[string]string{"susql_label_1": "shop", "susql_label_2": "web", "susql_label_3": "",
+					"susql_label_4": "", "susql_label_5": "", "susql_label_6": ""},
+				query: `susql_total_energy_joules{susql_label_1="shop",susql_label_2="web",susql_label_3="",susql_label_4="",susql_label_5="",susql_label_6=""}`,
+			},
+			{
+				description: "a cluster scoped label group in the legacy mode",
+				mode:        MetricLabelModeLegacy,
+				labelGroup:  newLabelGroup("", nil, nil),
+			},
+			{
+				description: "more than 6 labels in the legacy mode",
+				mode:        MetricLabelModeLegacy,
+				labelGroup:  newLabelGroup("shop", []string{"a", "b", "c", "d", "e", "f", "g"}, nil),
+			},
+		}
+
+		for _, test := range tests {
+			reconciler := &LabelGroupReconciler{MetricLabelMode: test.mode, MetricLabelMap: test.labelMap, Logger: logr.Discard()}
+
+			prometheusLabels, err := reconciler.prometheusLabelsForLabelGroup(test.labelGroup)
+			if test.prometheusLabels == nil {
+				Expect(err).To(HaveOccurred(), test.description)
+				continue
+			}
+			Expect(err).NotTo(HaveOccurred(), test.description)
+			Expect(prometheusLabels).To(Equal(test.prometheusLabels), test.description)
+			Expect(buildSusQLPrometheusQuery(susqlEnergyMetricName, prometheusLabels)).To(Equal(test.query), test.description)
+		}
+	})
+
+	It("should reinitialize a label group whose SusQL Prometheus labels are outdated", func() {
+		ctx := context.Background()
+		previousMetrics := susqlMetrics
+		susqlMetrics = newSusqlMetrics([]string{susqlPrometheusNamespaceLabelName, susqlPrometheusNameLabelName, "team"})
+		defer func() { susqlMetrics = previousMetrics }()
+		series := func() int {
+			registry := prometheus.NewRegistry()
+			Expect(registry.Register(susqlMetrics.totalEnergy)).To(Succeed())
+			families, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			if len(families) == 0 {
+				return 0
+			}
+			return len(families[0].GetMetric())
+		}
+
+		// The team label of the label group changed since its series were exported
+		outdatedLabels := map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop", "team": "web"}
+		labelGroup := &susqlv2.LabelGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "shop", Labels: map[string]string{"team": "store"}},
+			Spec:       susqlv2.LabelGroupSpec{Labels: []string{"shop"}},
+			Status:     susqlv2.LabelGroupStatus{Phase: susqlv2.Aggregating, PrometheusLabels: outdatedLabels},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(labelGroup).
+			WithStatusSubresource(&susqlv2.LabelGroup{}).
+			Build()
+		reconciler := &LabelGroupReconciler{Client: k8sClient, MetricLabelMap: map[string]string{"team": "team"}, Logger: logr.Discard()}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "shop", Namespace: "shop"}}
+
+		Expect(reconciler.SetAggregatedTotalForLabels(susqlMetrics.totalEnergy, "energy", 42, outdatedLabels)).To(Succeed())
+		Expect(series()).To(Equal(1))
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, request.NamespacedName, labelGroup)).To(Succeed())
+		Expect(labelGroup.Status.Phase).To(Equal(susqlv2.Initializing))
+		Expect(series()).To(BeZero())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, request.NamespacedName, labelGroup)).To(Succeed())
+		Expect(labelGroup.Status.Phase).To(Equal(susqlv2.Reloading))
+		Expect(labelGroup.Status.PrometheusLabels).To(Equal(map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop", "team": "store"}))
+		Expect(labelGroup.Status.SusQLPrometheusEnergyQuery).To(Equal(`susql_total_energy_joules{labelgroup_name="shop",labelgroup_namespace="shop",team="store"}`))
+	})
+})
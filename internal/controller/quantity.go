@@ -0,0 +1,74 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Energy is kept in the status with millijoule precision and carbon with nanogram precision
+const (
+	energyScale = resource.Milli
+	carbonScale = resource.Nano
+)
+
+// quantityToFloat returns the value of a status quantity, or zero when it is not set
+func quantityToFloat(value *resource.Quantity) float64 {
+	if value == nil {
+		return 0.0
+	}
+
+	return value.AsApproximateFloat64()
+}
+
+// energyQuantity returns the quantity for the given energy in joules
+func energyQuantity(joules float64) *resource.Quantity {
+	return scaledQuantity(joules, energyScale)
+}
+
+// carbonQuantity returns the quantity for the given carbon dioxide emission in grams
+func carbonQuantity(grams float64) *resource.Quantity {
+	return scaledQuantity(grams, carbonScale)
+}
+
+// scaledQuantity returns the quantity of a value at a scale, or at the finest coarser scale whose precision holds it
+// when it does not fit at the scale, so that a large total is never wrapped. The values beyond the int64 range, which
+// quantities can't serialize from an int64 amount, saturate, and NaN is zero.
+func scaledQuantity(value float64, scale resource.Scale) *resource.Quantity {
+	if math.IsNaN(value) {
+		return resource.NewScaledQuantity(0, scale)
+	}
+
+	for ; scale < 0; scale += 3 {
+		// float64(math.MaxInt64) is 2^63, the first value that does not fit in an int64
+		scaled := math.Round(value * math.Pow10(-int(scale)))
+		if math.Abs(scaled) < float64(math.MaxInt64) {
+			return resource.NewScaledQuantity(int64(scaled), scale)
+		}
+	}
+
+	if value >= float64(math.MaxInt64) {
+		return resource.NewScaledQuantity(math.MaxInt64, 0)
+	}
+	if value <= -float64(math.MaxInt64) {
+		return resource.NewScaledQuantity(-math.MaxInt64, 0)
+	}
+
+	return resource.NewScaledQuantity(int64(math.Round(value)), 0)
+}
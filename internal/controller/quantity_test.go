@@ -0,0 +1,53 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("Quantities", func() {
+	It("should keep the carbon with nanogram precision", func() {
+		Expect(carbonQuantity(0.123456789).String()).To(Equal("123456789n"))
+		Expect(energyQuantity(1234.56).String()).To(Equal("1234560m"))
+	})
+
+	It("should keep the totals too large for their precision with a coarser scale", func() {
+		// 9.2e9 g is the largest carbon with nanogram precision in an int64
+		for _, grams := range []float64{9.2e9, 9.3e9, -9.3e9, 1e15, 1e18} {
+			quantity := carbonQuantity(grams)
+			Expect(quantityToFloat(quantity)).To(BeNumerically("~", grams, math.Abs(grams)*1e-12))
+
+			// The status holds the serialized quantity
+			parsed, err := resource.ParseQuantity(quantity.String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed.AsApproximateFloat64()).To(BeNumerically("~", grams, math.Abs(grams)*1e-12))
+		}
+		Expect(quantityToFloat(energyQuantity(1e17))).To(BeNumerically("~", 1e17, 1e5))
+	})
+
+	It("should saturate the values that do not fit and not turn NaN into garbage", func() {
+		Expect(quantityToFloat(carbonQuantity(1e30))).To(BeNumerically("~", math.MaxInt64, 1e6))
+		Expect(quantityToFloat(carbonQuantity(math.Inf(1)))).To(BeNumerically("~", math.MaxInt64, 1e6))
+		Expect(quantityToFloat(carbonQuantity(math.Inf(-1)))).To(BeNumerically("~", -math.MaxInt64, 1e6))
+		Expect(quantityToFloat(carbonQuantity(math.NaN()))).To(BeZero())
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxConcurrentQueries = 4 // Maximum number of queries of a plan sent at the same time
+)
+
+// planQueries splits values (e.g., pod names) in batches whose rendered query is at most maxQueryLength
+// characters long, and returns the queries of the batches. Values are never dropped: a value whose query
+// alone is too long gets a query of its own.
+func planQueries(values []string, render func(batch []string) (string, error)) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	// Estimate the length of the query without values from the query of the first value
+	firstQuery, err := render(values[:1])
+	if err != nil {
+		return nil, err
+	}
+	baseLength := len(firstQuery) - regexValueLength(values[0])
+
+	var queries []string
+	start := 0
+	length := baseLength
+
+	for i, value := range values {
+		valueLength := regexValueLength(value)
+		if i > start && length+valueLength > maxQueryLength {
+			batchQueries, err := renderBatch(values[start:i], render)
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, batchQueries...)
+			start = i
+			length = baseLength
+		}
+		length += valueLength
+	}
+
+	batchQueries, err := renderBatch(values[start:], render)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(queries, batchQueries...), nil
+}
+
+// renderBatch renders the query of a batch, halving the batch until the queries fit when the estimate was wrong
+func renderBatch(batch []string, render func(batch []string) (string, error)) ([]string, error) {
+	query, err := render(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(query) <= maxQueryLength || len(batch) == 1 {
+		return []string{query}, nil
+	}
+
+	firstQueries, err := renderBatch(batch[:len(batch)/2], render)
+	if err != nil {
+		return nil, err
+	}
+
+	lastQueries, err := renderBatch(batch[len(batch)/2:], render)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(firstQueries, lastQueries...), nil
+}
+
+// Number of characters a value adds to the quoted PromQL regular expression of promQLRegex, including the separator
+func regexValueLength(value string) int {
+	return len(strconv.Quote(regexp.QuoteMeta(value))) - 2 + 1
+}
+
+// runQueries runs the queries of a plan concurrently and merges their results
+func runQueries(ctx context.Context, queries []string, query func(ctx context.Context, queryString string) (map[string]float64, error)) (map[string]float64, error) {
+	metricValues := make(map[string]float64)
+	var mutex sync.Mutex
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentQueries)
+
+	for i, queryString := range queries {
+		group.Go(func() error {
+			values, err := query(groupCtx, queryString)
+			if err != nil {
+				return fmt.Errorf("query %d of %d failed: %w", i+1, len(queries), err)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			for containerId, value := range values {
+				metricValues[containerId] += value
+			}
+
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return metricValues, nil
+}
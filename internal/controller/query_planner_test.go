@@ -0,0 +1,117 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query Planner", func() {
+	render := func(batch []string) (string, error) {
+		return fmt.Sprintf("sum by (container_id) (kepler_container_joules_total{pod_name=~%s})", promQLRegex(batch)), nil
+	}
+
+	// Pod names matched by the regular expression of a query rendered by render
+	podsOfQuery := func(query string) []string {
+		match := regexp.MustCompile(`pod_name=~"(.*)"`).FindStringSubmatch(query)
+		Expect(match).To(HaveLen(2))
+		return strings.Split(strings.ReplaceAll(match[1], `\\.`, "."), "|")
+	}
+
+	Context("When the pods fit in a single query", func() {
+		It("should plan one query", func() {
+			queries, err := planQueries([]string{"a", "b.c"}, render)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queries).To(Equal([]string{`sum by (container_id) (kepler_container_joules_total{pod_name=~"a|b\\.c"})`}))
+		})
+
+		It("should not plan queries without pods", func() {
+			queries, err := planQueries(nil, render)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queries).To(BeEmpty())
+		})
+	})
+
+	Context("When the pods don't fit in a single query", func() {
+		It("should split them in bounded queries without dropping any pod", func() {
+			var podNames []string
+			for i := 0; i < 2000; i++ {
+				podNames = append(podNames, fmt.Sprintf("my-deployment-with-a-long-name.%d-5d8f7c9b4-x2x7q", i))
+			}
+
+			queries, err := planQueries(podNames, render)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(queries)).To(BeNumerically(">", 1))
+
+			var plannedPods []string
+			for _, query := range queries {
+				Expect(len(query)).To(BeNumerically("<=", maxQueryLength))
+				plannedPods = append(plannedPods, podsOfQuery(query)...)
+			}
+			Expect(plannedPods).To(Equal(podNames))
+		})
+
+		It("should give a pod whose query is too long a query of its own", func() {
+			longName := strings.Repeat("a", maxQueryLength)
+
+			queries, err := planQueries([]string{"a", longName, "b"}, render)
+			Expect(err).NotTo(HaveOccurred())
+
+			var plannedPods []string
+			for _, query := range queries {
+				plannedPods = append(plannedPods, podsOfQuery(query)...)
+			}
+			Expect(plannedPods).To(Equal([]string{"a", longName, "b"}))
+		})
+	})
+
+	Context("When running the queries of a plan", func() {
+		It("should merge the results of all the queries", func() {
+			results := map[string]map[string]float64{
+				"q1": {"a": 1, "b": 2},
+				"q2": {"c": 3},
+				"q3": {"d": 4},
+			}
+
+			metricValues, err := runQueries(context.Background(), []string{"q1", "q2", "q3"}, func(ctx context.Context, query string) (map[string]float64, error) {
+				return results[query], nil
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metricValues).To(Equal(map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4}))
+		})
+
+		It("should fail when any query fails", func() {
+			_, err := runQueries(context.Background(), []string{"q1", "q2"}, func(ctx context.Context, query string) (map[string]float64, error) {
+				if query == "q2" {
+					return nil, fmt.Errorf("unavailable")
+				}
+				return map[string]float64{"a": 1}, nil
+			})
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -22,56 +22,86 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+// Function to get the pod label selector of a LabelGroup. The selector in the spec takes
+// precedence over the SusQL Kubernetes labels constructed from the list of labels.
+func podSelectorForLabelGroup(labelGroup *susqlv2.LabelGroup) (labels.Selector, error) {
+	if labelGroup.Spec.Selector != nil {
+		return metav1.LabelSelectorAsSelector(labelGroup.Spec.Selector)
+	}
+
+	return labels.SelectorFromSet(labels.Set(labelGroup.Status.KubernetesLabels)), nil
+}
+
 // Function to filter pods with matching labels from namespace label is defined
-func (r *LabelGroupReconciler) filterPodsInNamespace(ctx context.Context, namespace string, labelSelector map[string]string) ([]string, error) {
+func (r *LabelGroupReconciler) filterPodsInNamespace(ctx context.Context, namespace string, labelSelector labels.Selector) ([]v1.Pod, error) {
 	// Initialize list options with label selector
 	listOptions := &client.ListOptions{
 		Namespace:     namespace,
-		LabelSelector: labels.SelectorFromSet(labels.Set(labelSelector)),
+		LabelSelector: labelSelector,
 	}
 
 	// List pods in the specified namespace with label selector applied
 	var podList v1.PodList
 	if err := r.Client.List(ctx, &podList, listOptions); err != nil {
-		r.Logger.V(5).Info(fmt.Sprintf("[filterPodsInNamespace] labelSelector: %#v", labelSelector))
+		r.Logger.V(5).Info(fmt.Sprintf("[filterPodsInNamespace] labelSelector: %s", labelSelector))
 		r.Logger.V(5).Info(fmt.Sprintf("[filterPodsInNamespace] podList: %#v", podList))
 		r.Logger.V(5).Info(fmt.Sprintf("[filterPodsInNamespace] listOptions: %#v", listOptions))
 		r.Logger.V(0).Error(err, "[filterPodsInNamespace] List Error:")
 		return nil, err
 	}
 
-	var podNames []string
-	for _, pod := range podList.Items {
-		podNames = append(podNames, pod.Name)
+	return podList.Items, nil
+}
+
+// Function to get the pods matching a pod selector in the namespaces matching a namespace selector, grouped by namespace
+func (r *LabelGroupReconciler) GetPodsMatchingLabels(ctx context.Context, namespaceSelector labels.Selector, podSelector labels.Selector) (map[string][]v1.Pod, error) {
+	namespaces := &v1.NamespaceList{}
+
+	if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: namespaceSelector}); err != nil {
+		r.Logger.V(5).Info(fmt.Sprintf("[GetPodsMatchingLabels] namespaceSelector: %s", namespaceSelector))
+		r.Logger.V(0).Error(err, "[GetPodsMatchingLabels] Namespace List Error:")
+		return nil, err
 	}
 
-	return podNames, nil
-}
+	selectedNamespaces := make(map[string]bool, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		selectedNamespaces[namespace.Name] = true
+	}
 
-// Functions to get data from the cluster
-func (r *LabelGroupReconciler) GetPodNamesMatchingLabels(ctx context.Context, labelGroup *susqlv1.LabelGroup) ([]string, []string, error) {
 	pods := &v1.PodList{}
 
-	if err := r.List(ctx, pods, client.UnsafeDisableDeepCopy, (client.MatchingLabels)(labelGroup.Status.KubernetesLabels)); err != nil {
-		r.Logger.V(5).Info(fmt.Sprintf("[GetPodNamesMatchingLabels] pods: %#v", pods))
-		r.Logger.V(5).Info(fmt.Sprintf("[GetPodNamesMatchingLabels] LabelGroup: %#v", labelGroup))
-		r.Logger.V(5).Info(fmt.Sprintf("[GetPodNamesMatchingLabels] KubernetesLabels: %#v", labelGroup.Status.KubernetesLabels))
-		r.Logger.V(0).Error(err, "[GetPodNamesMatchingLabels] List Error:")
-		return nil, nil, err
+	if err := r.List(ctx, pods, client.UnsafeDisableDeepCopy, client.MatchingLabelsSelector{Selector: podSelector}); err != nil {
+		r.Logger.V(5).Info(fmt.Sprintf("[GetPodsMatchingLabels] podSelector: %s", podSelector))
+		r.Logger.V(0).Error(err, "[GetPodsMatchingLabels] List Error:")
+		return nil, err
 	}
 
-	var podNames []string
-	var namespaceNames []string
+	podsByNamespace := make(map[string][]v1.Pod)
 
 	for _, pod := range pods.Items {
-		podNames = append(podNames, pod.Name)
-		namespaceNames = append(namespaceNames, pod.Namespace)
+		if selectedNamespaces[pod.Namespace] {
+			podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+		}
+	}
+
+	return podsByNamespace, nil
+}
+
+// Function to get the names of pods grouped by namespace
+func podNamesByNamespace(podsByNamespace map[string][]v1.Pod) map[string][]string {
+	podNames := make(map[string][]string, len(podsByNamespace))
+
+	for namespace, pods := range podsByNamespace {
+		for _, pod := range pods {
+			podNames[namespace] = append(podNames[namespace], pod.Name)
+		}
 	}
 
-	return podNames, namespaceNames, nil
+	return podNames
 }
@@ -0,0 +1,77 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Pod Selectors", func() {
+	newLabelGroup := func(groupLabels []string, selector *metav1.LabelSelector) *susqlv2.LabelGroup {
+		labelGroup := &susqlv2.LabelGroup{Spec: susqlv2.LabelGroupSpec{Labels: groupLabels, Selector: selector}}
+		if len(groupLabels) > 0 {
+			labelGroup.Status.KubernetesLabels = map[string]string{susqlKubernetesLabelPrefix + "1": groupLabels[0]}
+		}
+		return labelGroup
+	}
+
+	It("should select the pods with the SusQL Kubernetes labels without a selector", func() {
+		podSelector, err := podSelectorForLabelGroup(newLabelGroup([]string{"my-group"}, nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podSelector.Matches(labels.Set{"susql.label/1": "my-group"})).To(BeTrue())
+		Expect(podSelector.Matches(labels.Set{"app": "web"})).To(BeFalse())
+	})
+
+	It("should select the pods with the selector", func() {
+		podSelector, err := podSelectorForLabelGroup(newLabelGroup(nil, &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "web"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"frontend", "backend"}},
+			},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podSelector.Matches(labels.Set{"app": "web", "tier": "backend"})).To(BeTrue())
+		Expect(podSelector.Matches(labels.Set{"app": "web", "tier": "database"})).To(BeFalse())
+		Expect(podSelector.Matches(labels.Set{"app": "web"})).To(BeFalse())
+	})
+
+	It("should use the selector instead of the SusQL Kubernetes labels when both are set", func() {
+		podSelector, err := podSelectorForLabelGroup(newLabelGroup([]string{"my-group"}, &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "web"},
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podSelector.Matches(labels.Set{"app": "web"})).To(BeTrue())
+		Expect(podSelector.Matches(labels.Set{"susql.label/1": "my-group"})).To(BeFalse())
+	})
+
+	It("should reject an invalid selector", func() {
+		_, err := podSelectorForLabelGroup(newLabelGroup(nil, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Matches", Values: []string{"web"}}},
+		}))
+		Expect(err).To(HaveOccurred())
+
+		_, err = podSelectorForLabelGroup(newLabelGroup(nil, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: metav1.LabelSelectorOpIn}},
+		}))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,175 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+const (
+	defaultSCIWindow  = "1h"        // Window of the SCI when the spec does not set one
+	sciUpdateInterval = time.Minute // Minimum time between two computations of the SCI of a label group
+)
+
+// ParseSCIFunctionalUnits parses the PromQL queries of the functional units of the SCI, one 'name=query' per line.
+// The queries are Go templates given the .Namespace of the LabelGroup, which is empty for a ClusterLabelGroup.
+func ParseSCIFunctionalUnits(functionalUnits string) (map[string]*template.Template, error) {
+	queries := make(map[string]*template.Template)
+
+	for _, line := range strings.Split(functionalUnits, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, query, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		query = strings.TrimSpace(query)
+		if !found || name == "" || query == "" {
+			return nil, fmt.Errorf("invalid SCI functional unit '%s', expected 'name=query'", line)
+		}
+		if _, duplicate := queries[name]; duplicate {
+			return nil, fmt.Errorf("duplicate SCI functional unit '%s'", name)
+		}
+
+		queryTemplate, err := template.New(name).Option("missingkey=error").Parse(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query template of the SCI functional unit '%s': %w", name, err)
+		}
+
+		queries[name] = queryTemplate
+	}
+
+	return queries, nil
+}
+
+// ValidateSCISpec checks that the functional unit of a label group is one of the functional units of the operator
+func ValidateSCISpec(spec *susqlv2.SCISpec, functionalUnits map[string]*template.Template) error {
+	if spec == nil {
+		return nil
+	}
+
+	if _, found := functionalUnits[spec.FunctionalUnit]; !found {
+		return fmt.Errorf("the SCI functional unit '%s' is not configured for the operator", spec.FunctionalUnit)
+	}
+
+	window, err := model.ParseDuration(sciWindow(spec))
+	if err != nil {
+		return fmt.Errorf("invalid SCI window '%s': %w", spec.Window, err)
+	}
+	if window <= 0 {
+		return fmt.Errorf("invalid SCI window '%s': it must be positive", spec.Window)
+	}
+
+	return nil
+}
+
+// Window of the SCI of a label group
+func sciWindow(spec *susqlv2.SCISpec) string {
+	if spec.Window == "" {
+		return defaultSCIWindow
+	}
+
+	return spec.Window
+}
+
+// Query of the SusQL Prometheus database returning the operational and embodied carbon of a label group over a window,
+// E*I + M
+func sciCarbonQuery(status *susqlv2.LabelGroupStatus, window string) string {
+	return fmt.Sprintf("sum(increase(%s[%s])) + (sum(increase(%s[%s])) or vector(0))",
+		status.SusQLPrometheusCarbonQuery, window,
+		buildSusQLPrometheusQuery(susqlEmbodiedCarbonMetricName, status.PrometheusLabels), window)
+}
+
+// Query of the Kepler Prometheus server returning the functional units of a label group of a namespace over a window, R
+func sciFunctionalUnitQuery(spec *susqlv2.SCISpec, functionalUnits map[string]*template.Template, namespace string) (string, error) {
+	queryTemplate, found := functionalUnits[spec.FunctionalUnit]
+	if !found {
+		return "", fmt.Errorf("the SCI functional unit '%s' is not configured for the operator", spec.FunctionalUnit)
+	}
+
+	var queryBuilder strings.Builder
+	if err := queryTemplate.Execute(&queryBuilder, struct{ Namespace string }{Namespace: namespace}); err != nil {
+		return "", fmt.Errorf("couldn't execute the query template of the SCI functional unit '%s': %w", spec.FunctionalUnit, err)
+	}
+
+	return fmt.Sprintf("sum(increase((%s)[%s:]))", queryBuilder.String(), sciWindow(spec)), nil
+}
+
+// Whether the SCI of a label group is due for computation
+func sciDue(status *susqlv2.LabelGroupStatus, now time.Time) bool {
+	return status.SCI == nil || now.Sub(status.SCI.Time.Time) >= sciUpdateInterval
+}
+
+// newSCIStatus computes SCI = (E*I + M) / R from the carbon and the functional units of a window
+func newSCIStatus(spec *susqlv2.SCISpec, carbon float64, functionalUnits float64, now time.Time) (*susqlv2.SCIStatus, error) {
+	if functionalUnits <= 0 {
+		return nil, fmt.Errorf("no functional units over the SCI window of %s", sciWindow(spec))
+	}
+
+	return &susqlv2.SCIStatus{
+		GramsPerFunctionalUnit: *carbonQuantity(carbon / functionalUnits),
+		CarbonGrams:            *carbonQuantity(carbon),
+		FunctionalUnits:        *resource.NewMilliQuantity(int64(functionalUnits*1000), resource.DecimalSI),
+		FunctionalUnit:         spec.FunctionalUnit,
+		Window:                 sciWindow(spec),
+		Time:                   metav1.Time{Time: now},
+	}, nil
+}
+
+// computeSCI computes the Software Carbon Intensity of a label group of a namespace over the window ending now, from the
+// carbon exported to the SusQL Prometheus database and the functional units of the Kepler Prometheus server
+func (r *LabelGroupReconciler) computeSCI(ctx context.Context, spec *susqlv2.SCISpec, namespace string, status *susqlv2.LabelGroupStatus, now time.Time) (*susqlv2.SCIStatus, error) {
+	functionalUnitQuery, err := sciFunctionalUnitQuery(spec, r.SCIFunctionalUnits, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	susqlAPI, err := r.susqlAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	carbon, err := queryValue(ctx, susqlAPI, sciCarbonQuery(status, sciWindow(spec)), now)
+	if err != nil {
+		return nil, err
+	}
+
+	keplerAPI, err := r.keplerAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	functionalUnits, err := queryValue(ctx, keplerAPI, functionalUnitQuery, now)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Logger.V(5).Info(fmt.Sprintf("[computeSCI] Carbon: %f, functional units: %f", carbon, functionalUnits)) // trace
+
+	return newSCIStatus(spec, carbon, functionalUnits, now)
+}
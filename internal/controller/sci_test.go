@@ -0,0 +1,93 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("SCI", func() {
+	spec := &susqlv2.SCISpec{FunctionalUnit: "request"}
+	functionalUnits, err := ParseSCIFunctionalUnits(`
+		request=sum(http_requests_total{namespace="{{.Namespace}}"})
+		job = sum(jobs_completed_total)
+	`)
+
+	It("should parse the functional units of the operator", func() {
+		Expect(err).NotTo(HaveOccurred())
+		Expect(functionalUnits).To(HaveLen(2))
+
+		_, err := ParseSCIFunctionalUnits("request")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseSCIFunctionalUnits("request=up\nrequest=up")
+		Expect(err).To(HaveOccurred())
+		_, err = ParseSCIFunctionalUnits("request={{.Namespace")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should validate the functional unit configuration", func() {
+		Expect(ValidateSCISpec(nil, functionalUnits)).To(Succeed())
+		Expect(ValidateSCISpec(spec, functionalUnits)).To(Succeed())
+		Expect(ValidateSCISpec(&susqlv2.SCISpec{FunctionalUnit: "job", Window: "15m"}, functionalUnits)).To(Succeed())
+		Expect(ValidateSCISpec(&susqlv2.SCISpec{}, functionalUnits)).To(HaveOccurred())
+		Expect(ValidateSCISpec(&susqlv2.SCISpec{FunctionalUnit: "page"}, functionalUnits)).To(HaveOccurred())
+		Expect(ValidateSCISpec(spec, nil)).To(HaveOccurred())
+		Expect(ValidateSCISpec(&susqlv2.SCISpec{FunctionalUnit: "job", Window: "soon"}, functionalUnits)).To(HaveOccurred())
+	})
+
+	It("should build the carbon and functional unit queries over the window", func() {
+		status := &susqlv2.LabelGroupStatus{
+			SusQLPrometheusCarbonQuery: `susql_total_carbon_dioxide_grams{labelgroup_name="shop",labelgroup_namespace="shop"}`,
+			PrometheusLabels:           map[string]string{"labelgroup_namespace": "shop", "labelgroup_name": "shop"},
+		}
+
+		Expect(sciCarbonQuery(status, "1h")).To(Equal(`sum(increase(susql_total_carbon_dioxide_grams{labelgroup_name="shop",labelgroup_namespace="shop"}[1h])) + ` +
+			`(sum(increase(susql_total_embodied_carbon_dioxide_grams{labelgroup_name="shop",labelgroup_namespace="shop"}[1h])) or vector(0))`))
+		Expect(sciFunctionalUnitQuery(spec, functionalUnits, "shop")).To(Equal(`sum(increase((sum(http_requests_total{namespace="shop"}))[1h:]))`))
+		Expect(sciFunctionalUnitQuery(&susqlv2.SCISpec{FunctionalUnit: "job", Window: "1d"}, functionalUnits, "")).To(Equal(`sum(increase((sum(jobs_completed_total))[1d:]))`))
+	})
+
+	It("should divide the carbon by the functional units", func() {
+		now := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+		sci, err := newSCIStatus(spec, 12, 4800, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(quantityToFloat(&sci.GramsPerFunctionalUnit)).To(BeNumerically("~", 0.0025, 1e-9))
+		Expect(quantityToFloat(&sci.FunctionalUnits)).To(Equal(4800.0))
+		Expect(sci.FunctionalUnit).To(Equal("request"))
+		Expect(sci.Window).To(Equal("1h"))
+
+		_, err = newSCIStatus(spec, 12, 0, now)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should compute the SCI at most once per update interval", func() {
+		now := time.Now()
+		status := &susqlv2.LabelGroupStatus{}
+		Expect(sciDue(status, now)).To(BeTrue())
+
+		status.SCI = &susqlv2.SCIStatus{Time: metav1.Time{Time: now}}
+		Expect(sciDue(status, now.Add(time.Second))).To(BeFalse())
+		Expect(sciDue(status, now.Add(sciUpdateInterval))).To(BeTrue())
+	})
+})
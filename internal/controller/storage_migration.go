@@ -0,0 +1,260 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=get;patch
+
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// servedVersionTimeout bounds the wait for the API server to serve v1 for the migration
+const servedVersionTimeout = time.Minute
+
+// StorageVersionMigrator rewrites the LabelGroup and ClusterLabelGroup objects stored with the v1 API in the storage
+// version (v2) when the operator starts, and then removes v1 from the stored versions of their CRDs. Without the
+// conversion webhook, the v2 API serves the objects stored as v1 without their v1 totals, which are read through the
+// v1 API and converted.
+//
+// v1 is only served with the conversion webhook, which config/crd-conversion adds: the CRDs without it serve v1 for
+// the time of the migration, and the migrator stops serving v1 from them afterwards, as v1 clients would otherwise
+// read the v2 objects without their totals.
+//
+// Done is closed once all the objects are migrated: the label groups are reconciled after it, as a status written
+// through the v2 API before the migration would drop the v1 totals. An error stops the manager, so that the migration
+// is retried when the operator restarts instead of reconciling the objects that are not migrated.
+type StorageVersionMigrator struct {
+	Client client.Client
+	Reader client.Reader // Reads without cache, e.g., the API reader of the manager
+	Done   chan struct{} // Closed when the migration is complete, may be nil
+	Logger logr.Logger
+}
+
+// storedKind is a kind whose objects are migrated
+type storedKind struct {
+	crdName string
+	v1List  func() client.ObjectList
+	migrate func(m *StorageVersionMigrator, ctx context.Context) (int, error)
+}
+
+var storedKinds = []storedKind{
+	{
+		crdName: "labelgroups.susql.ibm.com",
+		v1List:  func() client.ObjectList { return &susqlv1.LabelGroupList{} },
+		migrate: (*StorageVersionMigrator).migrateLabelGroups,
+	},
+	{
+		crdName: "clusterlabelgroups.susql.ibm.com",
+		v1List:  func() client.ObjectList { return &susqlv1.ClusterLabelGroupList{} },
+		migrate: (*StorageVersionMigrator).migrateClusterLabelGroups,
+	},
+}
+
+// Start migrates the objects of the kinds whose CRD still records another stored version than v2, and stops serving
+// v1 from the CRDs without the conversion webhook
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	for _, kind := range storedKinds {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		if err := m.Reader.Get(ctx, client.ObjectKey{Name: kind.crdName}, crd); err != nil {
+			return fmt.Errorf("[StorageVersionMigrator] couldn't get the CRD '%s': %w", kind.crdName, err)
+		}
+
+		storedVersions, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+		if needsMigration(storedVersions) {
+			if err := m.migrateStoredVersions(ctx, kind, crd); err != nil {
+				return fmt.Errorf("[StorageVersionMigrator] couldn't migrate the objects of '%s', they will be migrated at the next start: %w", kind.crdName, err)
+			}
+		}
+
+		if strategy, _, _ := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy"); strategy != "Webhook" {
+			if _, err := m.serveVersion(ctx, crd, susqlv1.GroupVersion.Version, false); err != nil {
+				return fmt.Errorf("[StorageVersionMigrator] couldn't stop serving %s from '%s', which has no conversion webhook: %w", susqlv1.GroupVersion.Version, kind.crdName, err)
+			}
+		}
+	}
+
+	if m.Done != nil {
+		close(m.Done)
+	}
+
+	return nil
+}
+
+// migrateStoredVersions migrates the objects of a kind through the v1 API, which is served for it, and then records v2
+// as the only stored version of its CRD
+func (m *StorageVersionMigrator) migrateStoredVersions(ctx context.Context, kind storedKind, crd *unstructured.Unstructured) error {
+	served, err := m.serveVersion(ctx, crd, susqlv1.GroupVersion.Version, true)
+	if err != nil {
+		return fmt.Errorf("couldn't serve %s: %w", susqlv1.GroupVersion.Version, err)
+	}
+	if served {
+		if err := m.waitForVersion(ctx, kind.v1List()); err != nil {
+			return fmt.Errorf("%s isn't served: %w", susqlv1.GroupVersion.Version, err)
+		}
+	}
+
+	count, err := kind.migrate(m, ctx)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(crd.DeepCopy())
+	if err := unstructured.SetNestedStringSlice(crd.Object, []string{susqlv2.GroupVersion.Version}, "status", "storedVersions"); err != nil {
+		return err
+	}
+	if err := m.Client.Status().Patch(ctx, crd, patch); err != nil {
+		// The objects are migrated, and migrated again at the next start
+		m.Logger.V(0).Error(err, fmt.Sprintf("[StorageVersionMigrator] Couldn't update the stored versions of '%s'.", kind.crdName))
+		return nil
+	}
+
+	m.Logger.V(2).Info(fmt.Sprintf("[StorageVersionMigrator] Migrated %d objects of '%s' to %s.", count, kind.crdName, susqlv2.GroupVersion.Version))
+	return nil
+}
+
+// serveVersion sets whether a CRD serves a version, and tells whether it changed. A version that the CRD doesn't
+// define is left as is.
+func (m *StorageVersionMigrator) serveVersion(ctx context.Context, crd *unstructured.Unstructured, version string, served bool) (bool, error) {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for i := range versions {
+		crdVersion, ok := versions[i].(map[string]any)
+		if !ok || crdVersion["name"] != version {
+			continue
+		}
+		if crdVersion["served"] == served {
+			return false, nil
+		}
+
+		patch := client.MergeFromWithOptions(crd.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		crdVersion["served"] = served
+		if err := unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"); err != nil {
+			return false, err
+		}
+		if err := m.Client.Patch(ctx, crd, patch); err != nil {
+			return false, err
+		}
+
+		m.Logger.V(2).Info(fmt.Sprintf("[StorageVersionMigrator] Set served to %t for %s of '%s'.", served, version, crd.GetName()))
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// waitForVersion waits until the API server serves the version of a list
+func (m *StorageVersionMigrator) waitForVersion(ctx context.Context, list client.ObjectList) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, servedVersionTimeout, true, func(ctx context.Context) (bool, error) {
+		err := m.Reader.List(ctx, list, client.Limit(1))
+		if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+}
+
+// NeedLeaderElection returns true so that a single replica migrates the objects
+func (m *StorageVersionMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// needsMigration tells whether objects may be stored with another version than v2
+func needsMigration(storedVersions []string) bool {
+	return slices.ContainsFunc(storedVersions, func(version string) bool {
+		return version != susqlv2.GroupVersion.Version
+	})
+}
+
+func (m *StorageVersionMigrator) migrateLabelGroups(ctx context.Context) (int, error) {
+	labelGroups := &susqlv2.LabelGroupList{}
+	if err := m.Reader.List(ctx, labelGroups); err != nil {
+		return 0, err
+	}
+
+	for i := range labelGroups.Items {
+		labelGroup := &labelGroups.Items[i]
+		if err := m.rewrite(ctx, labelGroup, &labelGroup.Status, &susqlv1.LabelGroup{}, func(v1Object client.Object) (*susqlv2.LabelGroupStatus, error) {
+			hub := &susqlv2.LabelGroup{}
+			err := v1Object.(*susqlv1.LabelGroup).ConvertTo(hub)
+			return &hub.Status, err
+		}); err != nil {
+			return i, fmt.Errorf("couldn't migrate the LabelGroup %s/%s: %w", labelGroup.Namespace, labelGroup.Name, err)
+		}
+	}
+
+	return len(labelGroups.Items), nil
+}
+
+func (m *StorageVersionMigrator) migrateClusterLabelGroups(ctx context.Context) (int, error) {
+	clusterLabelGroups := &susqlv2.ClusterLabelGroupList{}
+	if err := m.Reader.List(ctx, clusterLabelGroups); err != nil {
+		return 0, err
+	}
+
+	for i := range clusterLabelGroups.Items {
+		clusterLabelGroup := &clusterLabelGroups.Items[i]
+		if err := m.rewrite(ctx, clusterLabelGroup, &clusterLabelGroup.Status, &susqlv1.ClusterLabelGroup{}, func(v1Object client.Object) (*susqlv2.LabelGroupStatus, error) {
+			hub := &susqlv2.ClusterLabelGroup{}
+			err := v1Object.(*susqlv1.ClusterLabelGroup).ConvertTo(hub)
+			return &hub.Status, err
+		}); err != nil {
+			return i, fmt.Errorf("couldn't migrate the ClusterLabelGroup %s: %w", clusterLabelGroup.Name, err)
+		}
+	}
+
+	return len(clusterLabelGroups.Items), nil
+}
+
+// rewrite writes an object read through the v2 API back, which stores it as v2. The status of an object without v2
+// totals is converted from the v1 API when it has v1 totals, and written with the status.
+func (m *StorageVersionMigrator) rewrite(ctx context.Context, object client.Object, status *susqlv2.LabelGroupStatus, v1Object client.Object,
+	convert func(v1Object client.Object) (*susqlv2.LabelGroupStatus, error)) error {
+	if status.TotalEnergyJoules == nil {
+		if err := m.Reader.Get(ctx, client.ObjectKeyFromObject(object), v1Object); err != nil {
+			return err
+		}
+
+		v1Status, err := convert(v1Object)
+		if err != nil {
+			return err
+		}
+
+		if v1Status.TotalEnergyJoules != nil {
+			*status = *v1Status
+			return m.Client.Status().Update(ctx, object)
+		}
+	}
+
+	return m.Client.Update(ctx, object)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	susqlv1 "github.com/sustainable-computing-io/susql-operator/api/v1"
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+var _ = Describe("Storage Version Migration", func() {
+	newCRD := func(name string, storedVersions ...any) *unstructured.Unstructured {
+		crd := &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": name},
+			"status":   map[string]any{"storedVersions": storedVersions},
+		}}
+		crd.SetGroupVersionKind(crdGVK)
+		return crd
+	}
+
+	storedVersionsOf := func(k8sClient client.Client, name string) []string {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: name}, crd)).To(Succeed())
+		storedVersions, _, _ := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+		return storedVersions
+	}
+
+	It("should migrate only when another version than v2 is stored", func() {
+		Expect(needsMigration([]string{"v1", "v2"})).To(BeTrue())
+		Expect(needsMigration([]string{"v1"})).To(BeTrue())
+		Expect(needsMigration([]string{"v2"})).To(BeFalse())
+		Expect(needsMigration(nil)).To(BeFalse())
+	})
+
+	It("should convert the v1 totals of the objects stored as v1 and trim the stored versions", func() {
+		scheme := runtime.NewScheme()
+		Expect(susqlv1.AddToScheme(scheme)).To(Succeed())
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		// The fake client keeps each version apart, as the v2 API serves an object stored as v1 without its totals
+		objectMeta := metav1.ObjectMeta{Name: "my-group", Namespace: "default"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(
+				newCRD("labelgroups.susql.ibm.com", "v1", "v2"),
+				newCRD("clusterlabelgroups.susql.ibm.com", "v2"),
+				&susqlv2.LabelGroup{ObjectMeta: objectMeta},
+				&susqlv1.LabelGroup{ObjectMeta: objectMeta, Status: susqlv1.LabelGroupStatus{TotalEnergy: "1234.5", TotalCarbon: "0.5"}},
+			).
+			WithStatusSubresource(&susqlv2.LabelGroup{}, newCRD("labelgroups.susql.ibm.com")).
+			Build()
+
+		migrator := &StorageVersionMigrator{Client: k8sClient, Reader: k8sClient, Logger: logr.Discard()}
+		Expect(migrator.Start(context.Background())).To(Succeed())
+
+		labelGroup := &susqlv2.LabelGroup{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&susqlv2.LabelGroup{ObjectMeta: objectMeta}), labelGroup)).To(Succeed())
+		Expect(labelGroup.Status.TotalEnergyJoules).NotTo(BeNil())
+		Expect(quantityToFloat(labelGroup.Status.TotalEnergyJoules)).To(BeNumerically("~", 1234.5, 1e-3))
+		Expect(quantityToFloat(labelGroup.Status.TotalCarbonGrams)).To(BeNumerically("~", 0.5, 1e-6))
+
+		Expect(storedVersionsOf(k8sClient, "labelgroups.susql.ibm.com")).To(Equal([]string{"v2"}))
+		Expect(storedVersionsOf(k8sClient, "clusterlabelgroups.susql.ibm.com")).To(Equal([]string{"v2"}))
+	})
+
+	It("should reconcile the label groups only once they are migrated", func() {
+		scheme := runtime.NewScheme()
+		Expect(susqlv1.AddToScheme(scheme)).To(Succeed())
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		objectMeta := metav1.ObjectMeta{Name: "my-group", Namespace: "default"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(
+				newCRD("labelgroups.susql.ibm.com", "v1", "v2"),
+				newCRD("clusterlabelgroups.susql.ibm.com", "v2"),
+				&susqlv2.LabelGroup{ObjectMeta: objectMeta, Spec: susqlv2.LabelGroupSpec{Labels: []string{"my-label"}}},
+				&susqlv1.LabelGroup{ObjectMeta: objectMeta, Status: susqlv1.LabelGroupStatus{TotalEnergy: "1234.5", TotalCarbon: "0.5"}},
+			).
+			WithStatusSubresource(&susqlv2.LabelGroup{}, newCRD("labelgroups.susql.ibm.com")).
+			Build()
+
+		migrated := make(chan struct{})
+		migrator := &StorageVersionMigrator{Client: k8sClient, Reader: k8sClient, Done: migrated, Logger: logr.Discard()}
+		reconciler := &LabelGroupReconciler{Client: k8sClient, StorageMigrated: migrated, Logger: logr.Discard()}
+
+		// The reconcile starts before the migrator, and waits for it
+		reconciled := make(chan error, 1)
+		go func() {
+			_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&susqlv2.LabelGroup{ObjectMeta: objectMeta})})
+			reconciled <- err
+		}()
+		Consistently(reconciled, "100ms").ShouldNot(Receive())
+
+		Expect(migrator.Start(context.Background())).To(Succeed())
+		Eventually(reconciled).Should(Receive(BeNil()))
+
+		labelGroup := &susqlv2.LabelGroup{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&susqlv2.LabelGroup{ObjectMeta: objectMeta}), labelGroup)).To(Succeed())
+		Expect(labelGroup.Status.Phase).To(Equal(susqlv2.Initializing))
+		Expect(quantityToFloat(labelGroup.Status.TotalEnergyJoules)).To(BeNumerically("~", 1234.5, 1e-3))
+		Expect(quantityToFloat(labelGroup.Status.TotalCarbonGrams)).To(BeNumerically("~", 0.5, 1e-6))
+	})
+
+	It("should not release the reconciles when the migration fails", func() {
+		scheme := runtime.NewScheme()
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		// The CRDs can't be read
+		migrated := make(chan struct{})
+		migrator := &StorageVersionMigrator{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Done: migrated, Logger: logr.Discard()}
+		migrator.Reader = migrator.Client
+		Expect(migrator.Start(context.Background())).NotTo(Succeed())
+		Expect(migrated).NotTo(BeClosed())
+	})
+
+	It("should serve v1 only for the migration without the conversion webhook", func() {
+		scheme := runtime.NewScheme()
+		Expect(susqlv1.AddToScheme(scheme)).To(Succeed())
+		Expect(susqlv2.AddToScheme(scheme)).To(Succeed())
+
+		withVersions := func(crd *unstructured.Unstructured, v1Served bool, strategy string) *unstructured.Unstructured {
+			crd.Object["spec"] = map[string]any{
+				"conversion": map[string]any{"strategy": strategy},
+				"versions": []any{
+					map[string]any{"name": "v1", "served": v1Served, "storage": false},
+					map[string]any{"name": "v2", "served": true, "storage": true},
+				},
+			}
+			return crd
+		}
+		v1ServedOf := func(k8sClient client.Client, name string) bool {
+			crd := &unstructured.Unstructured{}
+			crd.SetGroupVersionKind(crdGVK)
+			Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: name}, crd)).To(Succeed())
+			versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+			return versions[0].(map[string]any)["served"].(bool)
+		}
+
+		// The v1 reads record whether v1 is served when they are made
+		var v1ServedOnRead []bool
+		objectMeta := metav1.ObjectMeta{Name: "my-group", Namespace: "default"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(
+				withVersions(newCRD("labelgroups.susql.ibm.com", "v1", "v2"), false, "None"),
+				withVersions(newCRD("clusterlabelgroups.susql.ibm.com", "v2"), true, "Webhook"),
+				&susqlv2.LabelGroup{ObjectMeta: objectMeta},
+				&susqlv1.LabelGroup{ObjectMeta: objectMeta, Status: susqlv1.LabelGroupStatus{TotalEnergy: "1234.5", TotalCarbon: "0.5"}},
+			).
+			WithStatusSubresource(&susqlv2.LabelGroup{}, newCRD("labelgroups.susql.ibm.com")).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*susqlv1.LabelGroup); ok {
+						v1ServedOnRead = append(v1ServedOnRead, v1ServedOf(c, "labelgroups.susql.ibm.com"))
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+
+		migrator := &StorageVersionMigrator{Client: k8sClient, Reader: k8sClient, Logger: logr.Discard()}
+		Expect(migrator.Start(context.Background())).To(Succeed())
+
+		labelGroup := &susqlv2.LabelGroup{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&susqlv2.LabelGroup{ObjectMeta: objectMeta}), labelGroup)).To(Succeed())
+		Expect(quantityToFloat(labelGroup.Status.TotalEnergyJoules)).To(BeNumerically("~", 1234.5, 1e-3))
+		Expect(v1ServedOnRead).To(Equal([]bool{true}))
+
+		// Without the conversion webhook, v1 is served again only for the next migration
+		Expect(v1ServedOf(k8sClient, "labelgroups.susql.ibm.com")).To(BeFalse())
+		Expect(v1ServedOf(k8sClient, "clusterlabelgroups.susql.ibm.com")).To(BeTrue())
+		Expect(storedVersionsOf(k8sClient, "labelgroups.susql.ibm.com")).To(Equal([]string{"v2"}))
+
+		// A v1 left served without the webhook, e.g., by an interrupted migration, is not served anymore
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(crdGVK)
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "labelgroups.susql.ibm.com"}, crd)).To(Succeed())
+		Expect(unstructured.SetNestedSlice(crd.Object, []any{
+			map[string]any{"name": "v1", "served": true, "storage": false},
+			map[string]any{"name": "v2", "served": true, "storage": true},
+		}, "spec", "versions")).To(Succeed())
+		Expect(k8sClient.Update(context.Background(), crd)).To(Succeed())
+		Expect(migrator.Start(context.Background())).To(Succeed())
+		Expect(v1ServedOf(k8sClient, "labelgroups.susql.ibm.com")).To(BeFalse())
+	})
+})
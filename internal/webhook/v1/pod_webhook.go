@@ -0,0 +1,116 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/sustainable-computing-io/susql-operator/internal/controller"
+)
+
+// SetupPodWebhookWithManager registers the webhooks adding the carbon-aware scheduling gate to the deferred pods,
+// and denying the creation of the pods of the label groups whose budget enforcement blocks new pods.
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.Pod{}).
+		WithDefaulter(&PodCustomDefaulter{}).
+		WithValidator(&PodCustomValidator{Reader: mgr.GetClient()}).
+		Complete()
+}
+
+// The objectSelector of the webhook, which only receives the pods labeled for deferral, is added by config/webhook.
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-carbon-aware.susql.ibm.com,admissionReviewVersions=v1,timeoutSeconds=5
+
+// PodCustomDefaulter adds the carbon-aware scheduling gate to the pods labeled for deferral, which is removed by the
+// carbon-aware gate controller
+type PodCustomDefaulter struct{}
+
+var _ admission.CustomDefaulter = &PodCustomDefaulter{}
+
+// Default adds the carbon-aware scheduling gate to an unscheduled pod labeled for deferral
+func (d *PodCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+
+	if pod.Labels[controller.CarbonAwareLabel] != "true" || pod.Spec.NodeName != "" {
+		return nil
+	}
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == controller.CarbonAwareSchedulingGate {
+			return nil
+		}
+	}
+
+	pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: controller.CarbonAwareSchedulingGate})
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=vpod-budget.susql.ibm.com,admissionReviewVersions=v1,timeoutSeconds=5
+
+// PodCustomValidator denies the creation of the pods matched by a LabelGroup or ClusterLabelGroup labeled as
+// blocked by its budget enforcement
+type PodCustomValidator struct {
+	Reader client.Reader
+}
+
+var _ admission.CustomValidator = &PodCustomValidator{}
+
+// ValidateCreate denies the pods of the label groups blocked by their budget
+func (v *PodCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, fmt.Errorf("expected a Pod object but got %T", obj)
+	}
+
+	// The namespace of the pod is only set in the request when the pod is created through its namespace
+	namespace := pod.Namespace
+	if namespace == "" {
+		if request, err := admission.RequestFromContext(ctx); err == nil {
+			namespace = request.Namespace
+		}
+	}
+
+	// Like the failure policy of the webhook, let the pod be created when the label groups can't be checked
+	labelGroup, err := controller.BudgetBlockingLabelGroup(ctx, v.Reader, namespace, pod.Labels)
+	if err != nil {
+		return admission.Warnings{fmt.Sprintf("couldn't check the SusQL budgets blocking new pods: %v", err)}, nil
+	}
+	if labelGroup != "" {
+		return nil, fmt.Errorf("the budget of %s is exceeded and blocks new pods", labelGroup)
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate allows all the updates of pods
+func (v *PodCustomValidator) ValidateUpdate(_ context.Context, _, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete allows all the deletions of pods
+func (v *PodCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	susqlv2 "github.com/sustainable-computing-io/susql-operator/api/v2"
+)
+
+// SetupLabelGroupWebhookWithManager registers the conversion webhook for LabelGroup in the manager.
+// The v2 API is the hub, and v1 objects are converted to and from it.
+func SetupLabelGroupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&susqlv2.LabelGroup{}).
+		Complete()
+}
+
+// SetupClusterLabelGroupWebhookWithManager registers the conversion webhook for ClusterLabelGroup in the manager.
+func SetupClusterLabelGroupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&susqlv2.ClusterLabelGroup{}).
+		Complete()
+}
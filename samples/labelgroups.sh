@@ -2,6 +2,9 @@
 
 namespace=default
 
+# Convert the resource.Quantity values of the v2 status to plain numbers
+quantity='def quantity: if . == null then null else capture("^(?<value>[-+]?[0-9.]+(e[-+]?[0-9]+)?)(?<suffix>[numkMGTPE]?)$") | (.value | tonumber) * ({"n": 1e-9, "u": 1e-6, "m": 1e-3, "": 1, "k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18}[.suffix]) end;'
+
 t_start=$(date +%s.%N)
 
 alldata=$(kubectl -n ${namespace} get labelgroups -o json)
@@ -9,8 +12,8 @@ alldata=$(kubectl -n ${namespace} get labelgroups -o json)
 for labelgroup in $(echo ${alldata} | jq -cr '.items[].metadata.name')
 do
     newdata=$(echo ${alldata} | jq '.items[] | select(.metadata.name=="'${labelgroup}'")')
-    totalEnergy=$(echo ${newdata} | jq -cr '.status.totalEnergy')
-    totalCarbon=$(echo ${newdata} | jq -cr '.status.totalCarbon')
+    totalEnergy=$(echo ${newdata} | jq -cr "${quantity}"' .status.totalEnergyJoules | quantity')
+    totalCarbon=$(echo ${newdata} | jq -cr "${quantity}"' .status.totalCarbonGrams | quantity')
     susqlPrometheusQuery=$(echo ${newdata} | jq -cr '.status.susqlPrometheusQuery')
     phase=$(echo ${newdata} | jq -cr '.status.phase')
     labels=$(echo ${newdata} | jq -cr '.spec.labels')